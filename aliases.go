@@ -0,0 +1,48 @@
+package openbindings
+
+// AliasIndex maps operation keys and aliases to canonical operation keys.
+// Build one with Interface.BuildAliasIndex when resolving many names against
+// the same document.
+type AliasIndex map[string]string
+
+// BuildAliasIndex indexes every operation key and alias of i. Operation keys take
+// precedence over aliases. If several operations share an alias (which Validate
+// reports), the one with the lexically smallest key wins.
+func (i Interface) BuildAliasIndex() AliasIndex {
+	idx := make(AliasIndex, len(i.Operations))
+	keys := sortedKeys(i.Operations)
+	for _, k := range keys {
+		idx[k] = k
+	}
+	for _, k := range keys {
+		for _, a := range i.Operations[k].Aliases {
+			if _, taken := idx[a]; !taken {
+				idx[a] = k
+			}
+		}
+	}
+	return idx
+}
+
+// Resolve returns the canonical operation key for name.
+func (idx AliasIndex) Resolve(name string) (string, bool) {
+	k, ok := idx[name]
+	return k, ok
+}
+
+// OperationByAlias resolves name to an operation, matching operation keys first and
+// then aliases, with the same precedence as BuildAliasIndex. ok is false when no
+// operation has that key or alias.
+func (i Interface) OperationByAlias(name string) (opKey string, op Operation, ok bool) {
+	if op, ok := i.Operations[name]; ok {
+		return name, op, true
+	}
+	for _, k := range sortedKeys(i.Operations) {
+		for _, a := range i.Operations[k].Aliases {
+			if a == name {
+				return k, i.Operations[k], true
+			}
+		}
+	}
+	return "", Operation{}, false
+}
@@ -0,0 +1,35 @@
+package openbindings
+
+import "testing"
+
+func TestOperationByAlias(t *testing.T) {
+	i := Interface{Operations: map[string]Operation{
+		"getItem":  {Description: "get", Aliases: []string{"fetchItem", "read"}},
+		"listItem": {Description: "list", Aliases: []string{"read", "getItem"}},
+	}}
+
+	cases := []struct {
+		name    string
+		wantKey string
+		wantOK  bool
+	}{
+		{"getItem", "getItem", true},
+		{"fetchItem", "getItem", true},
+		{"listItem", "listItem", true},
+		{"read", "getItem", true}, // shared alias: lexically smallest key wins
+		{"missing", "", false},
+	}
+	idx := i.BuildAliasIndex()
+	for _, c := range cases {
+		key, op, ok := i.OperationByAlias(c.name)
+		if key != c.wantKey || ok != c.wantOK {
+			t.Fatalf("OperationByAlias(%q) = %q, %v; want %q, %v", c.name, key, ok, c.wantKey, c.wantOK)
+		}
+		if ok && op.Description != i.Operations[key].Description {
+			t.Fatalf("OperationByAlias(%q) returned the wrong operation", c.name)
+		}
+		if key, ok := idx.Resolve(c.name); key != c.wantKey || ok != c.wantOK {
+			t.Fatalf("Resolve(%q) = %q, %v; want %q, %v", c.name, key, ok, c.wantKey, c.wantOK)
+		}
+	}
+}
@@ -3,6 +3,8 @@ package openbindings
 import (
 	"context"
 	"errors"
+	"reflect"
+	"strings"
 	"sync"
 	"testing"
 )
@@ -583,6 +585,44 @@ func TestExecuteOperation_ContextFlowsThrough(t *testing.T) {
 	}
 }
 
+func TestExecuteOperation_RawSourceContent(t *testing.T) {
+	var content any
+	executor := &mockExecutor{
+		formats: []FormatInfo{{Token: "test"}},
+		executeFn: func(_ context.Context, in *BindingExecutionInput) (<-chan StreamEvent, error) {
+			content = in.Source.Content
+			return SingleEventChannel(&ExecuteOutput{Output: "ok"}), nil
+		},
+	}
+
+	iface, err := DecodeInterface(strings.NewReader(`{
+  "openbindings": "0.1.0",
+  "operations": {"getUser": {}},
+  "sources": {"api": {"format": "test", "content": {"paths": {"/users": {}}}}},
+  "bindings": {"getUser.api": {"operation": "getUser", "source": "api", "ref": "#/paths/users"}}
+}`), WithRawSourceContent())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if iface.Sources["api"].Content != nil {
+		t.Fatal("expected content to be kept raw")
+	}
+
+	ch, err := NewOperationExecutor(executor).ExecuteOperation(context.Background(), &OperationExecutionInput{
+		Interface: iface,
+		Operation: "getUser",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range ch {
+	}
+	want := map[string]any{"paths": map[string]any{"/users": map[string]any{}}}
+	if !reflect.DeepEqual(content, want) {
+		t.Fatalf("expected decoded source content, got %#v", content)
+	}
+}
+
 // ---------------------------------------------------------------------------
 // Error sentinel tests
 // ---------------------------------------------------------------------------
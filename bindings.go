@@ -0,0 +1,104 @@
+package openbindings
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// BindingsFor returns the bindings of operation in the order the default binding
+// selector prefers them: non-deprecated bindings first, then by ascending
+// priority (lower values are preferred, as with DefaultBindingSelector), with
+// ties broken by binding key. A binding without a priority inherits its source's
+// priority; if neither is set it sorts after every binding that has one.
+// It returns nil if the operation has no bindings.
+func (i Interface) BindingsFor(operation string) []BindingEntry {
+	var keys []string
+	for k, b := range i.Bindings {
+		if b.Operation == operation {
+			keys = append(keys, k)
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	sort.Slice(keys, func(a, b int) bool {
+		return i.bindingLess(keys[a], keys[b])
+	})
+	out := make([]BindingEntry, len(keys))
+	for idx, k := range keys {
+		out[idx] = i.Bindings[k]
+	}
+	return out
+}
+
+// BestBindingFor returns the most preferred binding of operation, the first
+// entry of BindingsFor. It reports false if the operation has no bindings.
+func (i Interface) BestBindingFor(operation string) (BindingEntry, bool) {
+	var bestKey string
+	found := false
+	for k, b := range i.Bindings {
+		if b.Operation != operation {
+			continue
+		}
+		if !found || i.bindingLess(k, bestKey) {
+			bestKey, found = k, true
+		}
+	}
+	if !found {
+		return BindingEntry{}, false
+	}
+	return i.Bindings[bestKey], true
+}
+
+// bindingLess reports whether binding a is preferred over binding b.
+func (i Interface) bindingLess(a, b string) bool {
+	ba, bb := i.Bindings[a], i.Bindings[b]
+	if ba.Deprecated != bb.Deprecated {
+		return !ba.Deprecated
+	}
+	pa, pb := i.bindingPriority(ba), i.bindingPriority(bb)
+	if pa != pb {
+		return pa < pb
+	}
+	return a < b
+}
+
+// bindingPriority returns the effective priority of b: its own priority, else its
+// source's, else math.MaxFloat64. Binding priority overrides source priority.
+func (i Interface) bindingPriority(b BindingEntry) float64 {
+	if b.Priority != nil {
+		return *b.Priority
+	}
+	if src, ok := i.Sources[b.Source]; ok && src.Priority != nil {
+		return *src.Priority
+	}
+	return math.MaxFloat64
+}
+
+// ResolvedTransforms returns the binding's input and output transforms, with
+// "#/transforms/..." references resolved against transforms. An absent transform
+// is nil. A reference that does not resolve is an error from
+// TransformOrRef.ResolveErr, naming the field.
+func (b BindingEntry) ResolvedTransforms(transforms map[string]Transform) (input, output *Transform, err error) {
+	if input, err = resolveTransform(b.InputTransform, transforms); err != nil {
+		return nil, nil, fmt.Errorf("inputTransform: %w", err)
+	}
+	if output, err = resolveTransform(b.OutputTransform, transforms); err != nil {
+		return nil, nil, fmt.Errorf("outputTransform: %w", err)
+	}
+	return input, output, nil
+}
+
+// resolveTransform is TransformOrRef.ResolveErr that also rejects a TransformOrRef
+// holding neither a reference nor a transform. A nil tor resolves to nil.
+func resolveTransform(tor *TransformOrRef, transforms map[string]Transform) (*Transform, error) {
+	if tor == nil {
+		return nil, nil
+	}
+	if !tor.IsRef() && tor.Transform == nil {
+		return nil, errors.New("openbindings: invalid transform: neither ref nor inline")
+	}
+	return tor.ResolveErr(transforms)
+}
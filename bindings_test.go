@@ -0,0 +1,86 @@
+package openbindings
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestInterface_BindingsFor(t *testing.T) {
+	var iface Interface
+	mustUnmarshalJSON(t, []byte(`{
+  "openbindings": "0.1.0",
+  "operations": {"get": {}, "put": {}},
+  "sources": {"rest": {"format": "openapi@3.1", "priority": 2}, "rpc": {"format": "grpc"}},
+  "bindings": {
+    "get.none":       {"operation": "get", "ref": "get.none", "source": "rpc"},
+    "get.source":     {"operation": "get", "ref": "get.source", "source": "rest"},
+    "get.b":          {"operation": "get", "ref": "get.b", "source": "rpc", "priority": 1},
+    "get.a":          {"operation": "get", "ref": "get.a", "source": "rpc", "priority": 1},
+    "get.deprecated": {"operation": "get", "ref": "get.deprecated", "source": "rpc", "priority": 0, "deprecated": true},
+    "put.only":       {"operation": "put", "source": "rpc", "ref": "Put"}
+  }
+}`), &iface)
+
+	got := iface.BindingsFor("get")
+	want := []string{"get.a", "get.b", "get.source", "get.none", "get.deprecated"}
+	if len(got) != len(want) {
+		t.Fatalf("got %d bindings, want %d", len(got), len(want))
+	}
+	for idx, key := range want {
+		if got[idx].Ref != key {
+			t.Fatalf("binding %d: got %q, want %q", idx, got[idx].Ref, key)
+		}
+	}
+
+	best, ok := iface.BestBindingFor("get")
+	if !ok || best.Ref != "get.a" {
+		t.Fatalf("BestBindingFor(get) = %+v, %v", best, ok)
+	}
+	key, _, err := DefaultBindingSelector(&iface, "get")
+	if err != nil || key != "get.a" {
+		t.Fatalf("DefaultBindingSelector(get) = %q, %v", key, err)
+	}
+
+	if best, ok := iface.BestBindingFor("put"); !ok || best.Ref != "Put" {
+		t.Fatalf("BestBindingFor(put) = %+v, %v", best, ok)
+	}
+	if got := iface.BindingsFor("missing"); got != nil {
+		t.Fatalf("expected nil, got %+v", got)
+	}
+	if _, ok := iface.BestBindingFor("missing"); ok {
+		t.Fatal("expected no binding for missing operation")
+	}
+}
+
+func TestBindingEntry_ResolvedTransforms(t *testing.T) {
+	transforms := map[string]Transform{"toUser": {Type: "jsonata", Expression: "$.user"}}
+	inline := &Transform{Type: "jsonata", Expression: "{ id: id }"}
+
+	b := BindingEntry{
+		InputTransform:  &TransformOrRef{Transform: inline},
+		OutputTransform: &TransformOrRef{Ref: "#/transforms/toUser"},
+	}
+	in, out, err := b.ResolvedTransforms(transforms)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if in != inline || out == nil || out.Expression != "$.user" {
+		t.Fatalf("unexpected transforms %+v %+v", in, out)
+	}
+
+	in, out, err = BindingEntry{}.ResolvedTransforms(nil)
+	if in != nil || out != nil || err != nil {
+		t.Fatalf("expected nil transforms for a binding without any, got %v %v %v", in, out, err)
+	}
+
+	for ref, want := range map[string]string{
+		"#/transforms/missing": `outputTransform: openbindings: unknown transform "missing"`,
+		"#/schemas/toUser":     `outputTransform: openbindings: malformed transform reference "#/schemas/toUser": must start with "#/transforms/"`,
+	} {
+		b.OutputTransform = &TransformOrRef{Ref: ref}
+		_, _, err := b.ResolvedTransforms(transforms)
+		if !errors.Is(err, ErrTransformRefNotFound) || err.Error() != want {
+			t.Fatalf("%s: expected %q, got %v", ref, want, err)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package openbindings
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Builder assembles an Interface in code. Its methods return the Builder so calls
+// chain; mistakes such as a duplicate key are recorded and reported by Build, which
+// also validates the result:
+//
+//	iface, err := openbindings.NewInterface("0.1.0").
+//		AddOperation("getUser", openbindings.WithDescription("Get a user.")).
+//		AddSource("api", openbindings.Source{Format: "openapi@3.1", Location: "./api.json"}).
+//		AddBinding("getUser.api", openbindings.BindingEntry{Operation: "getUser", Source: "api", Ref: "#/paths/~1users/get"}).
+//		Build()
+type Builder struct {
+	iface Interface
+	errs  []error
+}
+
+// NewInterface starts a Builder for a document with the given openbindings version.
+func NewInterface(openbindings string) *Builder {
+	return &Builder{iface: Interface{OpenBindings: openbindings}}
+}
+
+// OperationOption configures an operation added with Builder.AddOperation.
+type OperationOption func(*Operation)
+
+// WithDescription sets the operation description.
+func WithDescription(description string) OperationOption {
+	return func(op *Operation) { op.Description = description }
+}
+
+// WithInput sets the operation input schema.
+func WithInput(schema JSONSchema) OperationOption {
+	return func(op *Operation) { op.Input = schema }
+}
+
+// WithOutput sets the operation output schema.
+func WithOutput(schema JSONSchema) OperationOption {
+	return func(op *Operation) { op.Output = schema }
+}
+
+// WithTags appends tags to the operation.
+func WithTags(tags ...string) OperationOption {
+	return func(op *Operation) { op.Tags = append(op.Tags, tags...) }
+}
+
+// WithDeprecated marks the operation deprecated.
+func WithDeprecated() OperationOption {
+	return func(op *Operation) { op.Deprecated = true }
+}
+
+// Name sets the interface name.
+func (b *Builder) Name(name string) *Builder {
+	b.iface.Name = name
+	return b
+}
+
+// Version sets the interface version.
+func (b *Builder) Version(version string) *Builder {
+	b.iface.Version = version
+	return b
+}
+
+// Description sets the interface description.
+func (b *Builder) Description(description string) *Builder {
+	b.iface.Description = description
+	return b
+}
+
+// AddOperation adds the operation key, configured by opts.
+func (b *Builder) AddOperation(key string, opts ...OperationOption) *Builder {
+	var op Operation
+	for _, opt := range opts {
+		opt(&op)
+	}
+	addEntry(b, "operation", &b.iface.Operations, key, op)
+	return b
+}
+
+// AddSource adds the source key.
+func (b *Builder) AddSource(key string, src Source) *Builder {
+	addEntry(b, "source", &b.iface.Sources, key, src)
+	return b
+}
+
+// AddBinding adds the binding key. Its operation and source may be added before or
+// after it; Build reports references to ones that were never added.
+func (b *Builder) AddBinding(key string, binding BindingEntry) *Builder {
+	addEntry(b, "binding", &b.iface.Bindings, key, binding)
+	return b
+}
+
+// AddTransform adds the named transform key.
+func (b *Builder) AddTransform(key string, tr Transform) *Builder {
+	addEntry(b, "transform", &b.iface.Transforms, key, tr)
+	return b
+}
+
+// Build returns the assembled Interface. It fails with the recorded builder errors,
+// or else with the ValidationError from Validate(opts...). The Builder keeps its own
+// copy, so it can go on to build further variants.
+func (b *Builder) Build(opts ...ValidateOption) (Interface, error) {
+	if len(b.errs) > 0 {
+		return Interface{}, errors.Join(b.errs...)
+	}
+	iface := b.iface.Clone()
+	if err := iface.Validate(opts...); err != nil {
+		return Interface{}, err
+	}
+	return iface, nil
+}
+
+func addEntry[V any](b *Builder, kind string, m *map[string]V, key string, v V) {
+	if _, exists := (*m)[key]; exists {
+		b.errs = append(b.errs, fmt.Errorf("openbindings: duplicate %s %q", kind, key))
+		return
+	}
+	if *m == nil {
+		*m = map[string]V{}
+	}
+	(*m)[key] = v
+}
@@ -0,0 +1,49 @@
+package openbindings
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestBuilder_BuildsValidInterface(t *testing.T) {
+	iface, err := NewInterface("0.1.0").
+		Name("users").
+		AddOperation("getUser", WithDescription("Get a user."), WithInput(JSONSchema{"type": "object"}), WithTags("users")).
+		AddBinding("getUser.api", BindingEntry{Operation: "getUser", Source: "api", Ref: "#/paths/~1users/get"}).
+		AddSource("api", Source{Format: "openapi@3.1", Location: "./api.json"}).
+		Build()
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	op := iface.Operations["getUser"]
+	if iface.Name != "users" || op.Description != "Get a user." || op.Input["type"] != "object" || len(op.Tags) != 1 {
+		t.Fatalf("unexpected interface: %+v", iface)
+	}
+	if iface.Bindings["getUser.api"].Source != "api" {
+		t.Fatalf("binding missing: %+v", iface.Bindings)
+	}
+}
+
+func TestBuilder_ReportsDuplicatesAndUnknownReferences(t *testing.T) {
+	_, err := NewInterface("0.1.0").
+		AddOperation("op").
+		AddOperation("op").
+		Build()
+	if err == nil || !strings.Contains(err.Error(), `duplicate operation "op"`) {
+		t.Fatalf("expected duplicate error, got %v", err)
+	}
+
+	_, err = NewInterface("0.1.0").
+		AddOperation("op").
+		AddBinding("op.api", BindingEntry{Operation: "missing", Source: "api"}).
+		Build()
+	var ve *ValidationError
+	if !errors.As(err, &ve) {
+		t.Fatalf("expected ValidationError, got %v", err)
+	}
+	if !containsProblem(err, `bindings["op.api"].operation: references unknown operation "missing"`) ||
+		!containsProblem(err, `bindings["op.api"].source: references unknown source "api"`) {
+		t.Fatalf("expected unknown reference problems, got %v", ve.Problems)
+	}
+}
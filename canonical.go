@@ -0,0 +1,39 @@
+package openbindings
+
+import "github.com/openbindings/openbindings-go/canonicaljson"
+
+// The MarshalCanonical methods return the RFC 8785 (JCS) canonical encoding of a
+// value, Extensions and Unknown included: members sorted, numbers in canonical
+// form, no insignificant whitespace. Unlike MarshalJSON, whose key order is only a
+// property of encoding/json, the bytes are stable by definition, so they suit
+// hashing, signing, and golden files.
+
+// MarshalCanonical returns the canonical JSON encoding of the document.
+func (i Interface) MarshalCanonical() ([]byte, error) { return canonicaljson.Marshal(i) }
+
+// MarshalCanonical returns the canonical JSON encoding of the operation.
+func (o Operation) MarshalCanonical() ([]byte, error) { return canonicaljson.Marshal(o) }
+
+// MarshalCanonical returns the canonical JSON encoding of the satisfies entry.
+func (s Satisfies) MarshalCanonical() ([]byte, error) { return canonicaljson.Marshal(s) }
+
+// MarshalCanonical returns the canonical JSON encoding of the example.
+func (e OperationExample) MarshalCanonical() ([]byte, error) { return canonicaljson.Marshal(e) }
+
+// MarshalCanonical returns the canonical JSON encoding of the source.
+func (s Source) MarshalCanonical() ([]byte, error) { return canonicaljson.Marshal(s) }
+
+// MarshalCanonical returns the canonical JSON encoding of the transform.
+func (t Transform) MarshalCanonical() ([]byte, error) { return canonicaljson.Marshal(t) }
+
+// MarshalCanonical returns the canonical JSON encoding of the transform or reference.
+func (t TransformOrRef) MarshalCanonical() ([]byte, error) { return canonicaljson.Marshal(t) }
+
+// MarshalCanonical returns the canonical JSON encoding of the binding.
+func (be BindingEntry) MarshalCanonical() ([]byte, error) { return canonicaljson.Marshal(be) }
+
+// MarshalCanonical returns the canonical JSON encoding of the contact.
+func (c Contact) MarshalCanonical() ([]byte, error) { return canonicaljson.Marshal(c) }
+
+// MarshalCanonical returns the canonical JSON encoding of the license.
+func (l License) MarshalCanonical() ([]byte, error) { return canonicaljson.Marshal(l) }
@@ -0,0 +1,35 @@
+package openbindings
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestInterface_MarshalCanonical(t *testing.T) {
+	i := Interface{
+		OpenBindings: "0.1.0",
+		Operations: map[string]Operation{
+			"op": {Input: JSONSchema{"type": "number", "maximum": 1.0}},
+		},
+		LosslessFields: LosslessFields{
+			Extensions: map[string]json.RawMessage{"x-team": json.RawMessage(`"core"`)},
+			Unknown:    map[string]json.RawMessage{"zeta": json.RawMessage(`{ "b": 2, "a": 1.50 }`)},
+		},
+	}
+	got, err := i.MarshalCanonical()
+	if err != nil {
+		t.Fatalf("MarshalCanonical: %v", err)
+	}
+	want := `{"openbindings":"0.1.0","operations":{"op":{"input":{"maximum":1,"type":"number"}}},"x-team":"core","zeta":{"a":1.5,"b":2}}`
+	if string(got) != want {
+		t.Fatalf("got  %s\nwant %s", got, want)
+	}
+
+	op, err := i.Operations["op"].MarshalCanonical()
+	if err != nil {
+		t.Fatalf("Operation.MarshalCanonical: %v", err)
+	}
+	if want := `{"input":{"maximum":1,"type":"number"}}`; string(op) != want {
+		t.Fatalf("got  %s\nwant %s", op, want)
+	}
+}
@@ -1,12 +1,14 @@
 package canonicaljson
 
 import (
+	"bufio"
 	"bytes"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"io"
 	"math"
+	"math/big"
 	"sort"
 	"strconv"
 	"strings"
@@ -39,7 +41,76 @@ import (
 //   shorthand escapes; remaining control characters use \u00XX (lowercase hex).
 // - Numbers are serialized using ECMAScript-compatible number serialization (as required by RFC 8785).
 // - Output is compact (no extra whitespace).
+// - json.RawMessage and []byte are taken as JSON text. Any other value is first encoded
+//   with encoding/json and the result is canonicalized, so values implementing
+//   json.Marshaler or encoding.TextMarshaler (e.g. time.Time), at any depth and as map
+//   keys, are encoded as they define. fmt.Stringer has no effect.
 func Marshal(v any) ([]byte, error) {
+	return marshal(v, false)
+}
+
+// Canonicalize returns the canonical form of the JSON text data. data must hold a
+// single JSON value; trailing data is an error, as in Marshal.
+func Canonicalize(data []byte) ([]byte, error) {
+	return Marshal(json.RawMessage(data))
+}
+
+// Equal reports whether the JSON texts a and b are semantically equal: the same
+// after canonicalization, so whitespace, member order, and number spelling (1.0 vs
+// 1) do not matter. It returns an error if either is not a single valid JSON value.
+func Equal(a, b []byte) (bool, error) {
+	ac, err := Canonicalize(a)
+	if err != nil {
+		return false, err
+	}
+	bc, err := Canonicalize(b)
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(ac, bc), nil
+}
+
+// MarshalPreservingIntegers is like Marshal, except that numbers written as
+// integer literals whose magnitude exceeds 2^53 are emitted digit for digit
+// instead of being rounded through an IEEE-754 double. This keeps 64-bit IDs
+// and similar values exact, but the output is NOT RFC 8785 canonical for such
+// numbers and may not match other JCS implementations; use Marshal when
+// interoperable canonical bytes are required. All other values are serialized
+// exactly as Marshal does.
+func MarshalPreservingIntegers(v any) ([]byte, error) {
+	return marshal(v, true)
+}
+
+// MarshalTo writes the Marshal encoding of v to w. Output is produced as the value
+// is walked rather than collected first, which suits hashing or writing large
+// documents; only the member names of the object being written are buffered for
+// sorting. The input is still decoded in full. If an error occurs, w may have
+// received part of the output.
+func MarshalTo(w io.Writer, v any) error {
+	bw := bufio.NewWriter(w)
+	if err := marshalTo(bw, v, false); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+func marshal(v any, preserveIntegers bool) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := marshalTo(&buf, v, preserveIntegers); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// jcsWriter is the subset of *bytes.Buffer and *bufio.Writer used for output.
+type jcsWriter interface {
+	io.Writer
+	io.ByteWriter
+	io.StringWriter
+	WriteRune(r rune) (int, error)
+}
+
+func marshalTo(w jcsWriter, v any, preserveIntegers bool) error {
 	var b []byte
 
 	switch x := v.(type) {
@@ -51,7 +122,7 @@ func Marshal(v any) ([]byte, error) {
 		var err error
 		b, err = json.Marshal(v)
 		if err != nil {
-			return nil, err
+			return err
 		}
 	}
 
@@ -59,24 +130,49 @@ func Marshal(v any) ([]byte, error) {
 	dec.UseNumber()
 	var anyVal any
 	if err := dec.Decode(&anyVal); err != nil {
-		return nil, err
+		return err
 	}
 	var extra any
 	if err := dec.Decode(&extra); err != io.EOF {
 		if err == nil {
-			return nil, errors.New("invalid JSON: trailing data")
+			return errors.New("invalid JSON: trailing data")
 		}
-		return nil, err
+		return err
 	}
 
-	var buf bytes.Buffer
-	if err := writeJCS(&buf, anyVal); err != nil {
-		return nil, err
+	return writeJCS(w, anyVal, preserveIntegers)
+}
+
+// Unmarshal decodes JSON data into v, using json.Number for numbers held in
+// interface values so numeric intent survives a Marshal round trip. Trailing data
+// after the first JSON value is an error.
+func Unmarshal(data []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(v); err != nil {
+		return err
 	}
-	return buf.Bytes(), nil
+	var extra any
+	if err := dec.Decode(&extra); err != io.EOF {
+		if err == nil {
+			return errors.New("invalid JSON: trailing data")
+		}
+		return err
+	}
+	return nil
+}
+
+// Valid reports whether data is already in RFC 8785 canonical form, i.e. whether
+// Marshal(data) reproduces data byte for byte.
+func Valid(data []byte) bool {
+	out, err := Marshal(json.RawMessage(data))
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(out, data)
 }
 
-func writeJCS(buf *bytes.Buffer, v any) error {
+func writeJCS(buf jcsWriter, v any, preserveIntegers bool) error {
 	switch x := v.(type) {
 	case nil:
 		buf.WriteString("null")
@@ -92,6 +188,12 @@ func writeJCS(buf *bytes.Buffer, v any) error {
 		writeJCSString(buf, x)
 		return nil
 	case json.Number:
+		if preserveIntegers {
+			if digits, ok := largeInteger(x.String()); ok {
+				buf.WriteString(digits)
+				return nil
+			}
+		}
 		s, err := formatJCSNumber(x.String())
 		if err != nil {
 			return err
@@ -112,7 +214,7 @@ func writeJCS(buf *bytes.Buffer, v any) error {
 			if i > 0 {
 				buf.WriteByte(',')
 			}
-			if err := writeJCS(buf, item); err != nil {
+			if err := writeJCS(buf, item, preserveIntegers); err != nil {
 				return err
 			}
 		}
@@ -138,7 +240,7 @@ func writeJCS(buf *bytes.Buffer, v any) error {
 			}
 			writeJCSString(buf, entry.k)
 			buf.WriteByte(':')
-			if err := writeJCS(buf, x[entry.k]); err != nil {
+			if err := writeJCS(buf, x[entry.k], preserveIntegers); err != nil {
 				return err
 			}
 		}
@@ -163,7 +265,7 @@ func lessUTF16(a, b []uint16) bool {
 	return len(a) < len(b)
 }
 
-func writeJCSString(buf *bytes.Buffer, s string) {
+func writeJCSString(buf jcsWriter, s string) {
 	buf.WriteByte('"')
 	for _, r := range s {
 		switch {
@@ -207,6 +309,23 @@ func formatJCSNumber(s string) (string, error) {
 	return formatJCSFloat64(f)
 }
 
+// maxExactInteger is 2^53, the largest magnitude up to which every integer is
+// exactly representable as an IEEE-754 double.
+var maxExactInteger = new(big.Int).Lsh(big.NewInt(1), 53)
+
+// largeInteger reports whether s is a JSON integer literal (no fraction or
+// exponent) whose magnitude exceeds 2^53, and returns its digits if so.
+func largeInteger(s string) (string, bool) {
+	if strings.ContainsAny(s, ".eE") {
+		return "", false
+	}
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok || n.CmpAbs(maxExactInteger) <= 0 {
+		return "", false
+	}
+	return n.String(), true
+}
+
 func formatJCSFloat64(f float64) (string, error) {
 	if math.IsNaN(f) || math.IsInf(f, 0) {
 		return "", errors.New("invalid JSON number: NaN or Infinity")
@@ -2,8 +2,12 @@ package canonicaljson
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"encoding/json"
+	"fmt"
+	"io"
 	"testing"
+	"time"
 )
 
 func TestMarshal_DeterministicAcrossKeyOrder(t *testing.T) {
@@ -89,3 +93,164 @@ func TestMarshal_NumberExponentPaddingNormalized(t *testing.T) {
 		t.Fatalf("expected exponent without padding for 1e-7, got %s", string(out))
 	}
 }
+
+func TestUnmarshal_PreservesNumbersAndRejectsTrailingData(t *testing.T) {
+	var v map[string]any
+	if err := Unmarshal([]byte(`{"n":1.50,"big":12345678901234567890}`), &v); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if n, ok := v["n"].(json.Number); !ok || n.String() != "1.50" {
+		t.Fatalf("expected json.Number 1.50, got %#v", v["n"])
+	}
+	if err := Unmarshal([]byte(`{} {}`), &v); err == nil {
+		t.Fatalf("expected trailing data error")
+	}
+}
+
+func TestValid(t *testing.T) {
+	cases := map[string]bool{
+		`{"a":1,"b":[true,null]}`: true,
+		`{"b":1,"a":2}`:           false,
+		`{"a": 1}`:                false,
+		`{"a":1.0}`:               false,
+		`not json`:                false,
+	}
+	for in, want := range cases {
+		if got := Valid([]byte(in)); got != want {
+			t.Fatalf("Valid(%s) = %v, want %v", in, got, want)
+		}
+	}
+}
+
+func TestMarshalPreservingIntegers(t *testing.T) {
+	cases := []struct {
+		in, strict, preserving string
+	}{
+		{`{"id":9007199254740993}`, `{"id":9007199254740992}`, `{"id":9007199254740993}`},
+		{`{"id":12345678901234567890}`, `{"id":12345678901234567000}`, `{"id":12345678901234567890}`},
+		{`[-9007199254740993]`, `[-9007199254740992]`, `[-9007199254740993]`},
+		{`[9007199254740992,1.5,1e2,9007199254740993.0]`, `[9007199254740992,1.5,100,9007199254740992]`, `[9007199254740992,1.5,100,9007199254740992]`},
+	}
+	for _, c := range cases {
+		strict, err := Marshal(json.RawMessage(c.in))
+		if err != nil {
+			t.Fatalf("Marshal(%s): %v", c.in, err)
+		}
+		if string(strict) != c.strict {
+			t.Fatalf("Marshal(%s) = %s, want %s", c.in, strict, c.strict)
+		}
+		got, err := MarshalPreservingIntegers(json.RawMessage(c.in))
+		if err != nil {
+			t.Fatalf("MarshalPreservingIntegers(%s): %v", c.in, err)
+		}
+		if string(got) != c.preserving {
+			t.Fatalf("MarshalPreservingIntegers(%s) = %s, want %s", c.in, got, c.preserving)
+		}
+	}
+
+	got, err := MarshalPreservingIntegers(map[string]any{"id": json.Number("9007199254740993")})
+	if err != nil {
+		t.Fatalf("MarshalPreservingIntegers: %v", err)
+	}
+	if want := `{"id":9007199254740993}`; string(got) != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
+
+func TestMarshalTo_MatchesMarshal(t *testing.T) {
+	in := json.RawMessage(`{"b":[1.0,{"z":null,"a":"\u000f"}],"a":1e-7}`)
+	want, err := Marshal(in)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	h := sha256.New()
+	var buf bytes.Buffer
+	if err := MarshalTo(io.MultiWriter(&buf, h), in); err != nil {
+		t.Fatalf("marshal to: %v", err)
+	}
+	if buf.String() != string(want) {
+		t.Fatalf("expected %s, got %s", want, buf.String())
+	}
+	if sum := sha256.Sum256(want); !bytes.Equal(h.Sum(nil), sum[:]) {
+		t.Fatal("hash of streamed output differs")
+	}
+
+	if err := MarshalTo(&buf, json.RawMessage(`{} {}`)); err == nil {
+		t.Fatal("expected error for trailing data")
+	}
+}
+
+func TestCanonicalize(t *testing.T) {
+	got, err := Canonicalize([]byte(" {\"b\": 2.50, \"a\": [true, null]}\n"))
+	if err != nil {
+		t.Fatalf("canonicalize: %v", err)
+	}
+	if string(got) != `{"a":[true,null],"b":2.5}` {
+		t.Fatalf("unexpected output: %s", got)
+	}
+	for _, bad := range []string{`{"a":1} {}`, `{"a":`, ``} {
+		if _, err := Canonicalize([]byte(bad)); err == nil {
+			t.Fatalf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestEqual(t *testing.T) {
+	eq, err := Equal([]byte(`{"b": [1.0, "x"], "a": {"c": 1e2}}`), []byte("{\"a\":{\"c\":100},\n \"b\":[1,\"x\"]}"))
+	if err != nil || !eq {
+		t.Fatalf("expected equal, got %v, %v", eq, err)
+	}
+	eq, err = Equal([]byte(`[1, 2]`), []byte(`[2, 1]`))
+	if err != nil || eq {
+		t.Fatalf("expected array order to matter, got %v, %v", eq, err)
+	}
+	if _, err := Equal([]byte(`{}`), []byte(`{} {}`)); err == nil {
+		t.Fatal("expected error for trailing data")
+	}
+	if _, err := Equal([]byte(`{`), []byte(`{}`)); err == nil {
+		t.Fatal("expected error for invalid JSON")
+	}
+}
+
+type point struct{ X, Y int }
+
+func (p point) MarshalText() ([]byte, error) { return []byte(fmt.Sprintf("%d,%d", p.X, p.Y)), nil }
+
+type version struct {
+	Major int `json:"major"`
+	Minor int `json:"minor"`
+}
+
+func (v version) String() string { return fmt.Sprintf("v%d.%d", v.Major, v.Minor) }
+
+type reversed struct{ Z, A float64 }
+
+func (r reversed) MarshalJSON() ([]byte, error) {
+	return []byte(fmt.Sprintf(`{"z": %v, "a": %v}`, r.Z, r.A)), nil
+}
+
+func TestMarshal_GoValuesEncodeThroughEncodingJSON(t *testing.T) {
+	ts := time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)
+	got, err := Marshal(map[string]any{
+		"time":    ts,
+		"point":   point{1, 2},
+		"keys":    map[point]int{{3, 4}: 1, {1, 2}: 2},
+		"version": version{Major: 1, Minor: 2},
+		"custom":  []any{reversed{Z: 1.50, A: 2e3}},
+	})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	want := `{"custom":[{"a":2000,"z":1.5}],"keys":{"1,2":2,"3,4":1},"point":"1,2","time":"2024-05-01T12:30:00Z","version":{"major":1,"minor":2}}`
+	if string(got) != want {
+		t.Fatalf("unexpected output:\n got %s\nwant %s", got, want)
+	}
+
+	direct, err := Marshal(ts)
+	if err != nil {
+		t.Fatalf("marshal time: %v", err)
+	}
+	if string(direct) != `"2024-05-01T12:30:00Z"` {
+		t.Fatalf("unexpected time encoding: %s", direct)
+	}
+}
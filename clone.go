@@ -0,0 +1,242 @@
+package openbindings
+
+import "encoding/json"
+
+// Clone returns a deep copy of the interface. Maps, slices, JSON Schema values,
+// and the raw bytes held in LosslessFields are all copied, so mutating the clone
+// never affects the original.
+func (i Interface) Clone() Interface {
+	out := i
+	out.LosslessFields = i.LosslessFields.Clone()
+	out.operationOrder = append([]string(nil), i.operationOrder...)
+
+	if i.Schemas != nil {
+		out.Schemas = make(map[string]JSONSchema, len(i.Schemas))
+		for k, v := range i.Schemas {
+			out.Schemas[k] = v.Clone()
+		}
+	}
+	if i.Operations != nil {
+		out.Operations = make(map[string]Operation, len(i.Operations))
+		for k, v := range i.Operations {
+			out.Operations[k] = v.Clone()
+		}
+	}
+	if i.Roles != nil {
+		out.Roles = make(map[string]string, len(i.Roles))
+		for k, v := range i.Roles {
+			out.Roles[k] = v
+		}
+	}
+	if i.Sources != nil {
+		out.Sources = make(map[string]Source, len(i.Sources))
+		for k, v := range i.Sources {
+			out.Sources[k] = v.Clone()
+		}
+	}
+	if i.Bindings != nil {
+		out.Bindings = make(map[string]BindingEntry, len(i.Bindings))
+		for k, v := range i.Bindings {
+			out.Bindings[k] = v.Clone()
+		}
+	}
+	if i.Security != nil {
+		out.Security = make(map[string][]SecurityMethod, len(i.Security))
+		for k, methods := range i.Security {
+			var cp []SecurityMethod
+			if methods != nil {
+				cp = make([]SecurityMethod, len(methods))
+				for idx, m := range methods {
+					m.Scopes = cloneStrings(m.Scopes)
+					cp[idx] = m
+				}
+			}
+			out.Security[k] = cp
+		}
+	}
+	if i.Transforms != nil {
+		out.Transforms = make(map[string]Transform, len(i.Transforms))
+		for k, v := range i.Transforms {
+			out.Transforms[k] = v.Clone()
+		}
+	}
+	return out
+}
+
+// Clone returns a deep copy of the operation.
+func (o Operation) Clone() Operation {
+	out := o
+	out.LosslessFields = o.LosslessFields.Clone()
+	out.Tags = cloneStrings(o.Tags)
+	out.Aliases = cloneStrings(o.Aliases)
+	if o.Satisfies != nil {
+		out.Satisfies = make([]Satisfies, len(o.Satisfies))
+		for idx, s := range o.Satisfies {
+			out.Satisfies[idx] = s.Clone()
+		}
+	}
+	if o.Idempotent != nil {
+		v := *o.Idempotent
+		out.Idempotent = &v
+	}
+	out.Input = o.Input.Clone()
+	out.Output = o.Output.Clone()
+	if o.Examples != nil {
+		out.Examples = make(map[string]OperationExample, len(o.Examples))
+		for k, v := range o.Examples {
+			out.Examples[k] = v.Clone()
+		}
+	}
+	if o.Security != nil {
+		out.Security = make([]map[string]any, len(o.Security))
+		for idx, req := range o.Security {
+			out.Security[idx], _ = cloneJSONValue(req).(map[string]any)
+		}
+	}
+	return out
+}
+
+// Clone returns a deep copy of the satisfies entry.
+func (s Satisfies) Clone() Satisfies {
+	out := s
+	out.LosslessFields = s.LosslessFields.Clone()
+	return out
+}
+
+// Clone returns a deep copy of the example.
+func (e OperationExample) Clone() OperationExample {
+	out := e
+	out.LosslessFields = e.LosslessFields.Clone()
+	out.Input = cloneJSONValue(e.Input)
+	out.Output = cloneJSONValue(e.Output)
+	return out
+}
+
+// Clone returns a deep copy of the source.
+func (s Source) Clone() Source {
+	out := s
+	out.LosslessFields = s.LosslessFields.Clone()
+	out.Content = cloneJSONValue(s.Content)
+	out.ContentRaw = cloneRaw(s.ContentRaw)
+	out.Priority = cloneFloatPtr(s.Priority)
+	return out
+}
+
+// Clone returns a deep copy of the transform.
+func (t Transform) Clone() Transform {
+	out := t
+	out.LosslessFields = t.LosslessFields.Clone()
+	return out
+}
+
+// Clone returns a deep copy of the transform or reference.
+func (t TransformOrRef) Clone() TransformOrRef {
+	out := t
+	if t.Transform != nil {
+		tr := t.Transform.Clone()
+		out.Transform = &tr
+	}
+	out.RefExtensions = cloneRawMap(t.RefExtensions)
+	return out
+}
+
+// Clone returns a deep copy of the binding entry.
+func (be BindingEntry) Clone() BindingEntry {
+	out := be
+	out.LosslessFields = be.LosslessFields.Clone()
+	out.Priority = cloneFloatPtr(be.Priority)
+	if be.InputTransform != nil {
+		tr := be.InputTransform.Clone()
+		out.InputTransform = &tr
+	}
+	if be.OutputTransform != nil {
+		tr := be.OutputTransform.Clone()
+		out.OutputTransform = &tr
+	}
+	return out
+}
+
+// Clone returns a deep copy of the preserved extension and unknown fields.
+func (lf LosslessFields) Clone() LosslessFields {
+	return LosslessFields{
+		Extensions: cloneRawMap(lf.Extensions),
+		Unknown:    cloneRawMap(lf.Unknown),
+	}
+}
+
+// Clone returns a deep copy of the schema, including nested maps and slices.
+func (s JSONSchema) Clone() JSONSchema {
+	if s == nil {
+		return nil
+	}
+	return JSONSchema(cloneJSONValue(map[string]any(s)).(map[string]any))
+}
+
+// cloneJSONValue deep-copies the container types produced by encoding/json.
+// Other values are returned as-is.
+func cloneJSONValue(v any) any {
+	switch x := v.(type) {
+	case map[string]any:
+		if x == nil {
+			return x
+		}
+		out := make(map[string]any, len(x))
+		for k, item := range x {
+			out[k] = cloneJSONValue(item)
+		}
+		return out
+	case []any:
+		if x == nil {
+			return x
+		}
+		out := make([]any, len(x))
+		for idx, item := range x {
+			out[idx] = cloneJSONValue(item)
+		}
+		return out
+	case JSONSchema:
+		return x.Clone()
+	case json.RawMessage:
+		return cloneRaw(x)
+	case []byte:
+		if x == nil {
+			return x
+		}
+		return append([]byte(nil), x...)
+	default:
+		return v
+	}
+}
+
+func cloneRawMap(m map[string]json.RawMessage) map[string]json.RawMessage {
+	if m == nil {
+		return nil
+	}
+	out := make(map[string]json.RawMessage, len(m))
+	for k, v := range m {
+		out[k] = cloneRaw(v)
+	}
+	return out
+}
+
+func cloneRaw(b json.RawMessage) json.RawMessage {
+	if b == nil {
+		return nil
+	}
+	return append(json.RawMessage(nil), b...)
+}
+
+func cloneStrings(s []string) []string {
+	if s == nil {
+		return nil
+	}
+	return append([]string(nil), s...)
+}
+
+func cloneFloatPtr(p *float64) *float64 {
+	if p == nil {
+		return nil
+	}
+	v := *p
+	return &v
+}
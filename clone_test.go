@@ -0,0 +1,66 @@
+package openbindings
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestInterface_Clone_IsDeepAndRoundTripsIdentically(t *testing.T) {
+	in := []byte(`{
+		"openbindings": "0.1.0",
+		"name": "demo",
+		"x-top": {"a": [1, 2]},
+		"schemas": {"User": {"type": "object", "properties": {"id": {"type": "string"}}}},
+		"operations": {
+			"getUser": {
+				"tags": ["users"],
+				"input": {"type": "object", "required": ["id"]},
+				"satisfies": [{"role": "r", "operation": "lookup", "x-s": true}],
+				"examples": {"basic": {"input": {"id": "1"}}},
+				"security": [{"oauth": ["read"]}]
+			}
+		},
+		"roles": {"r": "https://example.com/r.json"},
+		"sources": {"api": {"format": "openapi@3.1", "content": {"openapi": "3.1.0"}, "priority": 1}},
+		"bindings": {
+			"getUser.api": {
+				"operation": "getUser",
+				"source": "api",
+				"inputTransform": {"$ref": "#/transforms/t", "x-r": 1},
+				"outputTransform": {"type": "jsonata", "expression": "$"}
+			}
+		},
+		"transforms": {"t": {"type": "jsonata", "expression": "$", "unknownField": 1}}
+	}`)
+	var orig Interface
+	mustUnmarshalJSON(t, in, &orig)
+	before := mustMarshalJSON(t, orig)
+
+	c := orig.Clone()
+	if got := mustMarshalJSON(t, c); !bytes.Equal(got, before) {
+		t.Fatalf("clone marshals differently:\n got: %s\nwant: %s", got, before)
+	}
+
+	// Mutate every layer of the clone.
+	c.Extensions["x-top"][0] = '!'
+	c.Schemas["User"]["properties"].(map[string]any)["id"].(map[string]any)["type"] = "integer"
+	op := c.Operations["getUser"]
+	op.Tags[0] = "changed"
+	op.Input["required"].([]any)[0] = "changed"
+	op.Satisfies[0].Extensions["x-s"] = json.RawMessage(`false`)
+	op.Examples["basic"].Input.(map[string]any)["id"] = "2"
+	op.Security[0]["oauth"].([]any)[0] = "write"
+	src := c.Sources["api"]
+	src.Content.(map[string]any)["openapi"] = "3.0.0"
+	*src.Priority = 5
+	be := c.Bindings["getUser.api"]
+	be.InputTransform.RefExtensions["x-r"] = json.RawMessage(`2`)
+	be.OutputTransform.Transform.Expression = "changed"
+	c.Transforms["t"].Unknown["unknownField"][0] = '9'
+	c.Roles["r"] = "changed"
+
+	if after := mustMarshalJSON(t, orig); !bytes.Equal(after, before) {
+		t.Fatalf("mutating clone changed original:\n got: %s\nwant: %s", after, before)
+	}
+}
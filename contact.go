@@ -0,0 +1,158 @@
+package openbindings
+
+import "encoding/json"
+
+// Contact describes who maintains an interface. The core document model has no
+// contact field, so it travels in Interface.Unknown under "contact"; use
+// Interface.ContactInfo and Interface.SetContact to work with it typed.
+type Contact struct {
+	Name  string `json:"name,omitempty"`
+	URL   string `json:"url,omitempty"`
+	Email string `json:"email,omitempty"`
+
+	LosslessFields
+}
+
+type contactWire struct {
+	Name  string `json:"name,omitempty"`
+	URL   string `json:"url,omitempty"`
+	Email string `json:"email,omitempty"`
+}
+
+func (w *contactWire) field(key string) any {
+	switch key {
+	case "name":
+		return &w.Name
+	case "url":
+		return &w.URL
+	case "email":
+		return &w.Email
+	}
+	return nil
+}
+
+func (c *Contact) UnmarshalJSON(b []byte) error {
+	var w contactWire
+	extensions, unknown, err := unmarshalLossless(b, w.field)
+	if err != nil {
+		return err
+	}
+
+	*c = Contact{
+		Name:  w.Name,
+		URL:   w.URL,
+		Email: w.Email,
+	}
+
+	c.Extensions, c.Unknown = extensions, unknown
+	return nil
+}
+
+func (c Contact) MarshalJSON() ([]byte, error) {
+	w := contactWire{
+		Name:  c.Name,
+		URL:   c.URL,
+		Email: c.Email,
+	}
+	return marshalLossless(c.Unknown, c.Extensions, w)
+}
+
+// License describes the terms an interface is published under. Like Contact it
+// travels in Interface.Unknown, under "license". Identifier is an SPDX expression.
+type License struct {
+	Name       string `json:"name,omitempty"`
+	Identifier string `json:"identifier,omitempty"`
+	URL        string `json:"url,omitempty"`
+
+	LosslessFields
+}
+
+type licenseWire struct {
+	Name       string `json:"name,omitempty"`
+	Identifier string `json:"identifier,omitempty"`
+	URL        string `json:"url,omitempty"`
+}
+
+func (w *licenseWire) field(key string) any {
+	switch key {
+	case "name":
+		return &w.Name
+	case "identifier":
+		return &w.Identifier
+	case "url":
+		return &w.URL
+	}
+	return nil
+}
+
+func (l *License) UnmarshalJSON(b []byte) error {
+	var w licenseWire
+	extensions, unknown, err := unmarshalLossless(b, w.field)
+	if err != nil {
+		return err
+	}
+
+	*l = License{
+		Name:       w.Name,
+		Identifier: w.Identifier,
+		URL:        w.URL,
+	}
+
+	l.Extensions, l.Unknown = extensions, unknown
+	return nil
+}
+
+func (l License) MarshalJSON() ([]byte, error) {
+	w := licenseWire{
+		Name:       l.Name,
+		Identifier: l.Identifier,
+		URL:        l.URL,
+	}
+	return marshalLossless(l.Unknown, l.Extensions, w)
+}
+
+// ContactInfo decodes the document's "contact" entry. It returns the zero Contact
+// if there is none, and an error if the entry is not a contact object.
+func (i Interface) ContactInfo() (Contact, error) {
+	var c Contact
+	err := i.decodeUnknown("contact", &c)
+	return c, err
+}
+
+// SetContact stores c as the document's "contact" entry, unknown keys included.
+func (i *Interface) SetContact(c Contact) error {
+	return i.setUnknown("contact", c)
+}
+
+// LicenseInfo decodes the document's "license" entry. It returns the zero License
+// if there is none, and an error if the entry is not a license object.
+func (i Interface) LicenseInfo() (License, error) {
+	var l License
+	err := i.decodeUnknown("license", &l)
+	return l, err
+}
+
+// SetLicense stores l as the document's "license" entry, unknown keys included.
+func (i *Interface) SetLicense(l License) error {
+	return i.setUnknown("license", l)
+}
+
+func (i Interface) decodeUnknown(key string, v any) error {
+	raw, ok := i.Unknown[key]
+	if !ok {
+		return nil
+	}
+	return json.Unmarshal(raw, v)
+}
+
+func (i *Interface) setUnknown(key string, v any) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if i.Unknown == nil {
+		i.Unknown = map[string]json.RawMessage{}
+	}
+	i.Unknown[key] = b
+	return nil
+}
@@ -0,0 +1,58 @@
+package openbindings
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestInterface_ContactAndLicense(t *testing.T) {
+	var iface Interface
+	mustUnmarshalJSON(t, []byte(`{
+  "openbindings": "0.1.0",
+  "operations": {},
+  "contact": {"name": "API Team", "email": "api@example.com", "team": "platform", "x-slack": "#api"},
+  "license": {"name": "Apache 2.0", "identifier": "Apache-2.0"}
+}`), &iface)
+
+	c, err := iface.ContactInfo()
+	if err != nil {
+		t.Fatalf("ContactInfo: %v", err)
+	}
+	if c.Name != "API Team" || c.Email != "api@example.com" {
+		t.Fatalf("unexpected contact: %+v", c)
+	}
+	if string(c.Unknown["team"]) != `"platform"` || string(c.Extensions["x-slack"]) != `"#api"` {
+		t.Fatalf("expected unknown keys preserved, got %v %v", c.Unknown, c.Extensions)
+	}
+	l, err := iface.LicenseInfo()
+	if err != nil {
+		t.Fatalf("LicenseInfo: %v", err)
+	}
+	if l.Identifier != "Apache-2.0" {
+		t.Fatalf("unexpected license: %+v", l)
+	}
+
+	c.URL = "https://example.com/support"
+	if err := iface.SetContact(c); err != nil {
+		t.Fatalf("SetContact: %v", err)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(mustMarshalJSON(t, iface), &got); err != nil {
+		t.Fatal(err)
+	}
+	contact := got["contact"].(map[string]any)
+	if contact["url"] != "https://example.com/support" || contact["team"] != "platform" || contact["x-slack"] != "#api" {
+		t.Fatalf("unexpected round-tripped contact: %v", contact)
+	}
+}
+
+func TestInterface_ContactInfoAbsentOrMalformed(t *testing.T) {
+	c, err := Interface{}.ContactInfo()
+	if err != nil || c.Name != "" {
+		t.Fatalf("expected zero contact, got %+v, %v", c, err)
+	}
+	iface := Interface{LosslessFields: LosslessFields{Unknown: map[string]json.RawMessage{"license": json.RawMessage(`"MIT"`)}}}
+	if _, err := iface.LicenseInfo(); err == nil {
+		t.Fatal("expected error for non-object license")
+	}
+}
@@ -0,0 +1,438 @@
+package openbindings
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// DecodeInterface reads one interface document from r. The top-level object and
+// its keyed collections (operations, schemas, bindings, ...) are streamed one entry
+// at a time, so the decoder buffers a single entry rather than the whole document,
+// and the document is not parsed twice at the top level as json.Unmarshal does.
+// Entries are decoded with their lossless UnmarshalJSON methods, and the result is
+// the same as json.Unmarshal for documents whose keys use the specified casing.
+// Data after the document is an error.
+func DecodeInterface(r io.Reader, opts ...DecodeOption) (*Interface, error) {
+	var o decodeOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	dec := json.NewDecoder(r)
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	if d, ok := tok.(json.Delim); !ok || d != '{' {
+		return nil, errors.New("openbindings: interface document must be a JSON object")
+	}
+
+	var i Interface
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key := tok.(string)
+
+		switch key {
+		case "openbindings":
+			err = dec.Decode(&i.OpenBindings)
+		case "name":
+			err = dec.Decode(&i.Name)
+		case "version":
+			err = dec.Decode(&i.Version)
+		case "description":
+			err = dec.Decode(&i.Description)
+		case "schemas":
+			err = decodeMapEntries(dec, &i.Schemas)
+		case "operations":
+			if o.operationOrder {
+				err = decodeOrderedEntries(dec, &i.Operations, &i.operationOrder)
+			} else {
+				err = decodeMapEntries(dec, &i.Operations)
+			}
+		case "roles":
+			err = decodeMapEntries(dec, &i.Roles)
+		case "sources":
+			if o.rawSourceContent {
+				err = decodeRawContentSources(dec, &i.Sources)
+			} else {
+				err = decodeMapEntries(dec, &i.Sources)
+			}
+		case "bindings":
+			err = decodeMapEntries(dec, &i.Bindings)
+		case "security":
+			err = decodeMapEntries(dec, &i.Security)
+		case "transforms":
+			err = decodeMapEntries(dec, &i.Transforms)
+		default:
+			var v json.RawMessage
+			if err := dec.Decode(&v); err != nil {
+				return nil, fmt.Errorf("openbindings: decode %q: %w", key, err)
+			}
+			i.Extensions, i.Unknown = addLossless(i.Extensions, i.Unknown, key, v)
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("openbindings: decode %q: %w", key, err)
+		}
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	if err := expectEOF(dec); err != nil {
+		return nil, err
+	}
+	if o.validate {
+		if err := i.Validate(o.validateOpts...); err != nil {
+			return nil, err
+		}
+	}
+
+	return &i, nil
+}
+
+// DecodeOption configures DecodeInterface.
+type DecodeOption func(*decodeOptions)
+
+type decodeOptions struct {
+	rawSourceContent bool
+	operationOrder   bool
+	validate         bool
+	validateOpts     []ValidateOption
+}
+
+// WithValidation validates each decoded document with Validate(opts...) and
+// reports its ValidationError as the decode error.
+func WithValidation(opts ...ValidateOption) DecodeOption {
+	return func(o *decodeOptions) {
+		o.validate = true
+		o.validateOpts = append([]ValidateOption(nil), opts...)
+	}
+}
+
+// WithRawSourceContent keeps each source's inline content as the bytes it was read
+// from, in Source.ContentRaw, instead of decoding it into Source.Content.
+func WithRawSourceContent() DecodeOption {
+	return func(o *decodeOptions) { o.rawSourceContent = true }
+}
+
+// WithOperationOrder records the order in which the document lists its
+// operations, for Interface.OperationKeysInSourceOrder and
+// Interface.MarshalJSONInSourceOrder. JSON objects are unordered, so the order is
+// presentation only: it does not take part in validation, Equal, or Diff.
+func WithOperationOrder() DecodeOption {
+	return func(o *decodeOptions) { o.operationOrder = true }
+}
+
+// rawContentSource decodes a Source with its content kept in ContentRaw.
+type rawContentSource struct{ Source }
+
+func (s *rawContentSource) UnmarshalJSON(b []byte) error {
+	return s.Source.unmarshal(b, true)
+}
+
+func decodeRawContentSources(dec *json.Decoder, m *map[string]Source) error {
+	var raw map[string]rawContentSource
+	if err := decodeMapEntries(dec, &raw); err != nil {
+		return err
+	}
+	if raw == nil {
+		return nil
+	}
+	if *m == nil {
+		*m = make(map[string]Source, len(raw))
+	}
+	for k, s := range raw {
+		(*m)[k] = s.Source
+	}
+	return nil
+}
+
+// DecodeStrict decodes data into *i like json.Unmarshal, but first rejects any
+// object, at any depth, that repeats a key. encoding/json keeps the last of the
+// duplicates silently, so two readers of such a document can disagree on what it
+// says, which matters for documents that are signed or hashed. The error names the
+// repeated key and its JSON Pointer.
+func DecodeStrict(data []byte, i *Interface) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if err := checkDuplicateKeys(dec, ""); err != nil {
+		return err
+	}
+	if err := expectEOF(dec); err != nil {
+		return err
+	}
+	return json.Unmarshal(data, i)
+}
+
+// checkDuplicateKeys reads one JSON value from dec and reports the first object
+// member whose key repeats an earlier one in the same object. ptr is the value's
+// JSON Pointer.
+func checkDuplicateKeys(dec *json.Decoder, ptr string) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	switch tok {
+	case json.Delim('{'):
+		seen := map[string]bool{}
+		for dec.More() {
+			tok, err := dec.Token()
+			if err != nil {
+				return err
+			}
+			key := tok.(string)
+			memberPtr := ptr + "/" + escapePointerToken(key)
+			if seen[key] {
+				return fmt.Errorf("openbindings: duplicate key %q at %s", key, memberPtr)
+			}
+			seen[key] = true
+			if err := checkDuplicateKeys(dec, memberPtr); err != nil {
+				return err
+			}
+		}
+		_, err = dec.Token()
+		return err
+	case json.Delim('['):
+		for idx := 0; dec.More(); idx++ {
+			if err := checkDuplicateKeys(dec, ptr+"/"+strconv.Itoa(idx)); err != nil {
+				return err
+			}
+		}
+		_, err = dec.Token()
+		return err
+	}
+	return nil
+}
+
+// DecodeInterfaceLossy reads one interface document from r for read-only use. It
+// decodes in a single pass and skips the lossless bookkeeping, so Extensions,
+// Unknown, and TransformOrRef.RefExtensions are left empty throughout and the
+// result does not round-trip unmodeled fields. Data after the document is an error.
+func DecodeInterfaceLossy(r io.Reader) (*Interface, error) {
+	dec := json.NewDecoder(r)
+	var w lossyInterface
+	if err := dec.Decode(&w); err != nil {
+		return nil, err
+	}
+	if err := expectEOF(dec); err != nil {
+		return nil, err
+	}
+	return w.toInterface(), nil
+}
+
+// decodeMapEntries decodes a JSON object into *m one entry at a time, with the
+// same results as decoding the whole object: null leaves *m unchanged and
+// entries are added to an existing map.
+func decodeMapEntries[V any](dec *json.Decoder, m *map[string]V) error {
+	return decodeOrderedEntries(dec, m, nil)
+}
+
+// decodeOrderedEntries is decodeMapEntries that also appends each key to *order,
+// if order is non-nil, as it is read. A key repeated in the object is recorded
+// once, where it first appears.
+func decodeOrderedEntries[V any](dec *json.Decoder, m *map[string]V, order *[]string) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok == nil {
+		return nil
+	}
+	if d, ok := tok.(json.Delim); !ok || d != '{' {
+		return fmt.Errorf("expected object, got %v", tok)
+	}
+	if *m == nil {
+		*m = map[string]V{}
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key := tok.(string)
+		var v V
+		if err := dec.Decode(&v); err != nil {
+			return fmt.Errorf("%q: %w", key, err)
+		}
+		if _, dup := (*m)[key]; order != nil && !dup {
+			*order = append(*order, key)
+		}
+		(*m)[key] = v
+	}
+	_, err = dec.Token()
+	return err
+}
+
+func expectEOF(dec *json.Decoder) error {
+	if _, err := dec.Token(); err != io.EOF {
+		return errors.New("openbindings: unexpected data after interface document")
+	}
+	return nil
+}
+
+// The lossy* types mirror the public types without their UnmarshalJSON methods,
+// so encoding/json decodes them directly in one pass.
+
+type lossyInterface struct {
+	OpenBindings string                       `json:"openbindings"`
+	Name         string                       `json:"name"`
+	Version      string                       `json:"version"`
+	Description  string                       `json:"description"`
+	Schemas      map[string]JSONSchema        `json:"schemas"`
+	Operations   map[string]lossyOperation    `json:"operations"`
+	Roles        map[string]string            `json:"roles"`
+	Sources      map[string]lossySource       `json:"sources"`
+	Bindings     map[string]lossyBindingEntry `json:"bindings"`
+	Security     map[string][]SecurityMethod  `json:"security"`
+	Transforms   map[string]lossyTransform    `json:"transforms"`
+}
+
+type lossyOperation struct {
+	Description string                           `json:"description"`
+	Deprecated  bool                             `json:"deprecated"`
+	Tags        []string                         `json:"tags"`
+	Aliases     []string                         `json:"aliases"`
+	Satisfies   []lossySatisfies                 `json:"satisfies"`
+	Idempotent  *bool                            `json:"idempotent"`
+	Input       JSONSchema                       `json:"input"`
+	Output      JSONSchema                       `json:"output"`
+	Examples    map[string]lossyOperationExample `json:"examples"`
+	Security    []map[string]any                 `json:"security"`
+}
+
+type lossySatisfies struct {
+	Role      string `json:"role"`
+	Operation string `json:"operation"`
+}
+
+type lossyOperationExample struct {
+	Description string `json:"description"`
+	Input       any    `json:"input"`
+	Output      any    `json:"output"`
+}
+
+type lossySource struct {
+	Format      string   `json:"format"`
+	Location    string   `json:"location"`
+	Content     any      `json:"content"`
+	Description string   `json:"description"`
+	Priority    *float64 `json:"priority"`
+}
+
+type lossyTransform struct {
+	Type       string `json:"type"`
+	Expression string `json:"expression"`
+}
+
+// lossyTransformOrRef holds either form; a present $ref wins, as in TransformOrRef.UnmarshalJSON.
+type lossyTransformOrRef struct {
+	Ref        *string `json:"$ref"`
+	Type       string  `json:"type"`
+	Expression string  `json:"expression"`
+}
+
+type lossyBindingEntry struct {
+	Operation       string               `json:"operation"`
+	Source          string               `json:"source"`
+	Ref             string               `json:"ref"`
+	Priority        *float64             `json:"priority"`
+	Description     string               `json:"description"`
+	Deprecated      bool                 `json:"deprecated"`
+	Security        string               `json:"security"`
+	InputTransform  *lossyTransformOrRef `json:"inputTransform"`
+	OutputTransform *lossyTransformOrRef `json:"outputTransform"`
+}
+
+func (w lossyInterface) toInterface() *Interface {
+	i := &Interface{
+		OpenBindings: w.OpenBindings,
+		Name:         w.Name,
+		Version:      w.Version,
+		Description:  w.Description,
+		Schemas:      w.Schemas,
+		Roles:        w.Roles,
+		Security:     w.Security,
+	}
+	if w.Operations != nil {
+		i.Operations = make(map[string]Operation, len(w.Operations))
+		for k, o := range w.Operations {
+			i.Operations[k] = o.toOperation()
+		}
+	}
+	if w.Sources != nil {
+		i.Sources = make(map[string]Source, len(w.Sources))
+		for k, s := range w.Sources {
+			i.Sources[k] = Source{
+				Format:      s.Format,
+				Location:    s.Location,
+				Content:     s.Content,
+				Description: s.Description,
+				Priority:    s.Priority,
+			}
+		}
+	}
+	if w.Bindings != nil {
+		i.Bindings = make(map[string]BindingEntry, len(w.Bindings))
+		for k, b := range w.Bindings {
+			i.Bindings[k] = BindingEntry{
+				Operation:       b.Operation,
+				Source:          b.Source,
+				Ref:             b.Ref,
+				Priority:        b.Priority,
+				Description:     b.Description,
+				Deprecated:      b.Deprecated,
+				Security:        b.Security,
+				InputTransform:  b.InputTransform.toTransformOrRef(),
+				OutputTransform: b.OutputTransform.toTransformOrRef(),
+			}
+		}
+	}
+	if w.Transforms != nil {
+		i.Transforms = make(map[string]Transform, len(w.Transforms))
+		for k, t := range w.Transforms {
+			i.Transforms[k] = Transform{Type: t.Type, Expression: t.Expression}
+		}
+	}
+	return i
+}
+
+func (w lossyOperation) toOperation() Operation {
+	op := Operation{
+		Description: w.Description,
+		Deprecated:  w.Deprecated,
+		Tags:        w.Tags,
+		Aliases:     w.Aliases,
+		Idempotent:  w.Idempotent,
+		Input:       w.Input,
+		Output:      w.Output,
+		Security:    w.Security,
+	}
+	if w.Satisfies != nil {
+		op.Satisfies = make([]Satisfies, len(w.Satisfies))
+		for idx, s := range w.Satisfies {
+			op.Satisfies[idx] = Satisfies{Role: s.Role, Operation: s.Operation}
+		}
+	}
+	if w.Examples != nil {
+		op.Examples = make(map[string]OperationExample, len(w.Examples))
+		for k, e := range w.Examples {
+			op.Examples[k] = OperationExample{Description: e.Description, Input: e.Input, Output: e.Output}
+		}
+	}
+	return op
+}
+
+func (w *lossyTransformOrRef) toTransformOrRef() *TransformOrRef {
+	if w == nil {
+		return nil
+	}
+	if w.Ref != nil {
+		return &TransformOrRef{Ref: *w.Ref}
+	}
+	return &TransformOrRef{Transform: &Transform{Type: w.Type, Expression: w.Expression}}
+}
@@ -0,0 +1,246 @@
+package openbindings
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const decodeTestDoc = `{
+  "openbindings": "0.1.0",
+  "name": "store",
+  "x-top": {"a": 1},
+  "future": [1, 2],
+  "schemas": {"Item": {"type": "object", "properties": {"id": {"type": "string"}}}},
+  "operations": {
+    "getItem": {
+      "description": "Get an item",
+      "aliases": ["fetchItem"],
+      "satisfies": [{"role": "base", "operation": "get", "x-note": true}],
+      "idempotent": true,
+      "input": {"type": "object"},
+      "output": {"$ref": "#/schemas/Item"},
+      "examples": {"one": {"input": {"id": "1"}, "x-ex": 1}},
+      "x-op": "yes"
+    }
+  },
+  "roles": {"base": "./base.obi.json"},
+  "sources": {"api": {"format": "openapi@3.1", "location": "./api.json", "priority": 1, "extra": 2}},
+  "bindings": {
+    "getItem.api": {
+      "operation": "getItem",
+      "source": "api",
+      "ref": "#/paths/~1items/get",
+      "inputTransform": {"$ref": "#/transforms/toApi", "x-ref-note": 1},
+      "outputTransform": {"type": "jsonata", "expression": "$", "x-inline": 1}
+    }
+  },
+  "security": {"default": [{"type": "bearer"}]},
+  "transforms": {"toApi": {"type": "jsonata", "expression": "{ 'id': id }"}}
+}`
+
+func TestDecodeInterface_MatchesUnmarshal(t *testing.T) {
+	var want Interface
+	if err := json.Unmarshal([]byte(decodeTestDoc), &want); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	got, err := DecodeInterface(strings.NewReader(decodeTestDoc))
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if !reflect.DeepEqual(*got, want) {
+		t.Fatalf("decoded interface differs from json.Unmarshal:\n got %#v\nwant %#v", *got, want)
+	}
+}
+
+func TestDecodeInterfaceLossy_DropsOnlyLosslessFields(t *testing.T) {
+	got, err := DecodeInterfaceLossy(strings.NewReader(decodeTestDoc))
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if got.Extensions != nil || got.Unknown != nil || got.Operations["getItem"].Extensions != nil {
+		t.Fatalf("expected lossless fields to be empty")
+	}
+
+	// Stripping the unmodeled fields from a lossless decode must give the same value.
+	var want Interface
+	if err := json.Unmarshal([]byte(decodeTestDoc), &want); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	want.LosslessFields = LosslessFields{}
+	op := want.Operations["getItem"]
+	op.LosslessFields = LosslessFields{}
+	op.Satisfies[0].LosslessFields = LosslessFields{}
+	ex := op.Examples["one"]
+	ex.LosslessFields = LosslessFields{}
+	op.Examples["one"] = ex
+	want.Operations["getItem"] = op
+	src := want.Sources["api"]
+	src.LosslessFields = LosslessFields{}
+	want.Sources["api"] = src
+	b := want.Bindings["getItem.api"]
+	b.InputTransform.RefExtensions = nil
+	b.OutputTransform.Transform.LosslessFields = LosslessFields{}
+	want.Bindings["getItem.api"] = b
+
+	if !reflect.DeepEqual(*got, want) {
+		t.Fatalf("lossy decode differs:\n got %#v\nwant %#v", *got, want)
+	}
+}
+
+func TestDecodeInterface_Errors(t *testing.T) {
+	for _, decode := range []func(string) error{
+		func(s string) error { _, err := DecodeInterface(strings.NewReader(s)); return err },
+		func(s string) error { _, err := DecodeInterfaceLossy(strings.NewReader(s)); return err },
+	} {
+		for _, in := range []string{
+			``,
+			`[]`,
+			`{"openbindings": "0.1.0"} {}`,
+			`{"operations": {"x": {"tags": "not-an-array"}}}`,
+			`{"openbindings": `,
+		} {
+			if err := decode(in); err == nil {
+				t.Fatalf("expected error for %q", in)
+			}
+		}
+	}
+}
+
+// largeInterfaceDocument builds an interface document of roughly size bytes.
+func largeInterfaceDocument(size int) []byte {
+	ops := map[string]any{}
+	bindings := map[string]any{}
+	for n := 0; ; n++ {
+		props := map[string]any{}
+		for f := 0; f < 20; f++ {
+			props[fmt.Sprintf("field%d", f)] = map[string]any{"type": "string", "description": "A field of the payload", "maxLength": 256}
+		}
+		key := fmt.Sprintf("op%d", n)
+		ops[key] = map[string]any{
+			"description": "Operation description",
+			"tags":        []any{"a", "b"},
+			"input":       map[string]any{"type": "object", "properties": props},
+			"output":      map[string]any{"type": "object", "properties": props},
+			"x-meta":      map[string]any{"n": n},
+		}
+		bindings[key+".api"] = map[string]any{
+			"operation":      key,
+			"source":         "api",
+			"ref":            "#/paths/" + key,
+			"inputTransform": map[string]any{"type": "jsonata", "expression": "$"},
+		}
+		if n%50 == 0 {
+			b, _ := json.Marshal(map[string]any{"operations": ops})
+			if len(b) >= size {
+				break
+			}
+		}
+	}
+	doc, _ := json.Marshal(map[string]any{
+		"openbindings": "0.1.0",
+		"operations":   ops,
+		"sources":      map[string]any{"api": map[string]any{"format": "openapi@3.1"}},
+		"bindings":     bindings,
+	})
+	return doc
+}
+
+func BenchmarkDecodeInterface(b *testing.B) {
+	doc := largeInterfaceDocument(5 << 20)
+	b.Run("Unmarshal", func(b *testing.B) {
+		b.SetBytes(int64(len(doc)))
+		for n := 0; n < b.N; n++ {
+			var i Interface
+			if err := json.Unmarshal(doc, &i); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("DecodeInterface", func(b *testing.B) {
+		b.SetBytes(int64(len(doc)))
+		for n := 0; n < b.N; n++ {
+			if _, err := DecodeInterface(bytes.NewReader(doc)); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("DecodeInterfaceLossy", func(b *testing.B) {
+		b.SetBytes(int64(len(doc)))
+		for n := 0; n < b.N; n++ {
+			if _, err := DecodeInterfaceLossy(bytes.NewReader(doc)); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func TestDecodeInterface_WithRawSourceContent(t *testing.T) {
+	doc := `{
+  "openbindings": "0.1.0",
+  "operations": {"op": {}},
+  "sources": {"api": {"format": "openapi@3.1", "content": {"openapi": "3.1.0", "info": {"title": "t", "version": "1"}, "paths": {}}}}
+}`
+	i, err := DecodeInterface(strings.NewReader(doc), WithRawSourceContent())
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	src := i.Sources["api"]
+	if src.Content != nil {
+		t.Fatalf("expected Content to be nil, got %v", src.Content)
+	}
+	if want := `{"openapi": "3.1.0", "info": {"title": "t", "version": "1"}, "paths": {}}`; string(src.ContentRaw) != want {
+		t.Fatalf("expected raw content bytes, got %s", src.ContentRaw)
+	}
+	content, err := src.DecodeContent()
+	if err != nil {
+		t.Fatalf("decode content: %v", err)
+	}
+	if m, ok := content.(map[string]any); !ok || m["openapi"] != "3.1.0" {
+		t.Fatalf("unexpected decoded content: %v", content)
+	}
+	if err := i.Validate(); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+
+	out := string(mustMarshalJSON(t, src))
+	if want := `{"format":"openapi@3.1","content":{"openapi":"3.1.0","info":{"title":"t","version":"1"},"paths":{}}}`; out != want {
+		t.Fatalf("expected content to keep member order:\n got: %s\nwant: %s", out, want)
+	}
+
+	plain, err := DecodeInterface(strings.NewReader(doc))
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if plain.Sources["api"].ContentRaw != nil || plain.Sources["api"].Content == nil {
+		t.Fatalf("expected decoded Content by default, got %+v", plain.Sources["api"])
+	}
+}
+
+func TestDecodeStrict(t *testing.T) {
+	var got Interface
+	if err := DecodeStrict([]byte(decodeTestDoc), &got); err != nil {
+		t.Fatalf("DecodeStrict: %v", err)
+	}
+	var want Interface
+	mustUnmarshalJSON(t, []byte(decodeTestDoc), &want)
+	if !reflect.DeepEqual(got, want) {
+		t.Fatal("DecodeStrict result differs from json.Unmarshal")
+	}
+
+	for in, wantErr := range map[string]string{
+		`{"openbindings": "0.1.0", "openbindings": "0.2.0"}`:                     `duplicate key "openbindings" at /openbindings`,
+		`{"operations": {"a/b": {"x-kind": "method", "x-kind": "event"}}}`:       `duplicate key "x-kind" at /operations/a~1b/x-kind`,
+		`{"sources": {"s": {"content": {"paths": [{}, {"get": 1, "get": 2}]}}}}`: `duplicate key "get" at /sources/s/content/paths/1/get`,
+		`{"openbindings": "0.1.0"} {}`:                                           `unexpected data after interface document`,
+	} {
+		var i Interface
+		err := DecodeStrict([]byte(in), &i)
+		if err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Fatalf("%s: err = %v, want %q", in, err, wantErr)
+		}
+	}
+}
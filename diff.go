@@ -0,0 +1,245 @@
+package openbindings
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/openbindings/openbindings-go/canonicaljson"
+)
+
+// ChangeKind classifies a single change between two interface documents.
+type ChangeKind string
+
+const (
+	ChangeAdded   ChangeKind = "added"
+	ChangeRemoved ChangeKind = "removed"
+	ChangeChanged ChangeKind = "changed"
+)
+
+// Change describes one difference between two versions of an interface.
+//
+// Change marshals to JSON with a fixed field order (path, kind, severity, old, new),
+// and Old/New are rendered as RFC 8785 canonical JSON, so serialized change lists
+// are stable across runs and suitable for posting to review bots or web UIs.
+type Change struct {
+	// Path is the RFC 6901 JSON Pointer of the changed value (e.g. "/operations/getUser").
+	Path string
+	Kind ChangeKind
+	// Severity is an optional impact classification (e.g. "breaking", "compatible", "patch").
+	Severity string
+	// Old is the previous value; nil for additions.
+	Old any
+	// New is the current value; nil for removals.
+	New any
+}
+
+func (c Change) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	if err := writeJSONField(&buf, "path", c.Path, false); err != nil {
+		return nil, err
+	}
+	if err := writeJSONField(&buf, "kind", string(c.Kind), true); err != nil {
+		return nil, err
+	}
+	if c.Severity != "" {
+		if err := writeJSONField(&buf, "severity", c.Severity, true); err != nil {
+			return nil, err
+		}
+	}
+	for _, f := range []struct {
+		name string
+		v    any
+	}{{"old", c.Old}, {"new", c.New}} {
+		if f.v == nil {
+			continue
+		}
+		b, err := json.Marshal(f.v)
+		if err != nil {
+			return nil, err
+		}
+		canon, err := canonicaljson.Marshal(json.RawMessage(b))
+		if err != nil {
+			return nil, err
+		}
+		buf.WriteString(`,"` + f.name + `":`)
+		buf.Write(canon)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func writeJSONField(buf *bytes.Buffer, name, value string, comma bool) error {
+	v, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	if comma {
+		buf.WriteByte(',')
+	}
+	buf.WriteString(`"` + name + `":`)
+	buf.Write(v)
+	return nil
+}
+
+// InterfaceDiff is a structural diff between two versions of an interface.
+type InterfaceDiff struct {
+	// Changes are ordered by document position: the top-level fields
+	// (openbindings, name, version, description), then schemas, operations,
+	// roles, sources, bindings, security, and transforms, each by entry key and
+	// then by field name.
+	Changes []Change
+}
+
+// Empty reports whether the diff has no changes.
+func (d *InterfaceDiff) Empty() bool {
+	return d == nil || len(d.Changes) == 0
+}
+
+// Diff reports the top-level fields and the schemas, operations, roles,
+// sources, bindings, security entries, and transforms added, removed, or
+// changed from old to new. A top-level field is reported at its own path (e.g.
+// "/version"). An added or removed entry is one Change at the entry's path
+// (e.g. "/operations/getUser") holding the whole entry. An object entry present
+// in both is compared field by field on its JSON form, including extensions and
+// unknown fields, with one Change per differing field (e.g.
+// "/operations/getUser/description"), so a changed description is distinct from
+// a changed input schema; roles and security entries are compared whole. Values
+// are compared by canonical JSON, so key order and number formatting do not
+// register as changes.
+func Diff(old, new Interface) (*InterfaceDiff, error) {
+	d := &InterfaceDiff{}
+	if err := diffFields(d, "", headerOf(old), headerOf(new)); err != nil {
+		return nil, fmt.Errorf("openbindings: diff: %w", err)
+	}
+	if err := diffEntries(d, "schemas", old.Schemas, new.Schemas, true); err != nil {
+		return nil, err
+	}
+	if err := diffEntries(d, "operations", old.Operations, new.Operations, true); err != nil {
+		return nil, err
+	}
+	if err := diffEntries(d, "roles", old.Roles, new.Roles, false); err != nil {
+		return nil, err
+	}
+	if err := diffEntries(d, "sources", old.Sources, new.Sources, true); err != nil {
+		return nil, err
+	}
+	if err := diffEntries(d, "bindings", old.Bindings, new.Bindings, true); err != nil {
+		return nil, err
+	}
+	if err := diffEntries(d, "security", old.Security, new.Security, false); err != nil {
+		return nil, err
+	}
+	if err := diffEntries(d, "transforms", old.Transforms, new.Transforms, true); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// interfaceHeader holds the top-level scalar fields of an Interface, encoded as
+// the document encodes them.
+type interfaceHeader struct {
+	OpenBindings string `json:"openbindings"`
+	Name         string `json:"name,omitempty"`
+	Version      string `json:"version,omitempty"`
+	Description  string `json:"description,omitempty"`
+}
+
+func headerOf(i Interface) interfaceHeader {
+	return interfaceHeader{OpenBindings: i.OpenBindings, Name: i.Name, Version: i.Version, Description: i.Description}
+}
+
+// diffEntries appends the changes between two collections. With byField, entries
+// present in both are compared field by field; otherwise they are compared whole.
+func diffEntries[V any](d *InterfaceDiff, collection string, old, new map[string]V, byField bool) error {
+	keys := sortedKeys(old)
+	for k := range new {
+		if _, ok := old[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		path := "/" + collection + "/" + escapePointerToken(k)
+		ov, inOld := old[k]
+		nv, inNew := new[k]
+		switch {
+		case !inOld:
+			d.Changes = append(d.Changes, Change{Path: path, Kind: ChangeAdded, New: nv})
+		case !inNew:
+			d.Changes = append(d.Changes, Change{Path: path, Kind: ChangeRemoved, Old: ov})
+		case byField:
+			if err := diffFields(d, path, ov, nv); err != nil {
+				return fmt.Errorf("openbindings: diff %s[%q]: %w", collection, k, err)
+			}
+		default:
+			same, err := canonicalEqual(ov, nv)
+			if err != nil {
+				return fmt.Errorf("openbindings: diff %s[%q]: %w", collection, k, err)
+			}
+			if !same {
+				d.Changes = append(d.Changes, Change{Path: path, Kind: ChangeChanged, Old: ov, New: nv})
+			}
+		}
+	}
+	return nil
+}
+
+// diffFields appends one Change per top-level JSON field that differs between
+// the JSON objects old and new.
+func diffFields(d *InterfaceDiff, path string, old, new any) error {
+	oldFields, err := jsonFields(old)
+	if err != nil {
+		return err
+	}
+	newFields, err := jsonFields(new)
+	if err != nil {
+		return err
+	}
+
+	names := sortedKeys(oldFields)
+	for name := range newFields {
+		if _, ok := oldFields[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		fieldPath := path + "/" + escapePointerToken(name)
+		ov, inOld := oldFields[name]
+		nv, inNew := newFields[name]
+		switch {
+		case !inOld:
+			d.Changes = append(d.Changes, Change{Path: fieldPath, Kind: ChangeAdded, New: nv})
+		case !inNew:
+			d.Changes = append(d.Changes, Change{Path: fieldPath, Kind: ChangeRemoved, Old: ov})
+		default:
+			same, err := canonicalEqual(ov, nv)
+			if err != nil {
+				return err
+			}
+			if !same {
+				d.Changes = append(d.Changes, Change{Path: fieldPath, Kind: ChangeChanged, Old: ov, New: nv})
+			}
+		}
+	}
+	return nil
+}
+
+// jsonFields returns the members of v's JSON object form, with numbers kept as
+// json.Number so they survive unchanged into Change values.
+func jsonFields(v any) (map[string]any, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]any
+	if err := canonicaljson.Unmarshal(b, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
@@ -0,0 +1,135 @@
+package openbindings
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestChange_MarshalJSON_StableOrderAndCanonicalValues(t *testing.T) {
+	c := Change{
+		Path:     "/operations/getUser",
+		Kind:     ChangeChanged,
+		Severity: "breaking",
+		Old:      Operation{Description: "old", Input: JSONSchema{"type": "object", "required": []any{"id"}}},
+		New:      map[string]any{"z": 1.0, "a": "x"},
+	}
+	got := string(mustMarshalJSON(t, c))
+	want := `{"path":"/operations/getUser","kind":"changed","severity":"breaking",` +
+		`"old":{"description":"old","input":{"required":["id"],"type":"object"}},"new":{"a":"x","z":1}}`
+	if got != want {
+		t.Fatalf("unexpected JSON:\n got: %s\nwant: %s", got, want)
+	}
+
+	added := string(mustMarshalJSON(t, Change{Path: "/sources/api", Kind: ChangeAdded, New: "x"}))
+	if added != `{"path":"/sources/api","kind":"added","new":"x"}` {
+		t.Fatalf("unexpected JSON for addition: %s", added)
+	}
+}
+
+func TestDiff_ReportsEntryAndFieldChanges(t *testing.T) {
+	var old, cur Interface
+	mustUnmarshalJSON(t, []byte(`{
+  "openbindings": "0.1.0",
+  "operations": {
+    "getUser": {"description": "Get a user.", "input": {"type": "object", "required": ["id"]}},
+    "listUsers": {"description": "List users.", "input": {"type": "object"}},
+    "removed": {}
+  },
+  "sources": {"api": {"format": "openapi@3.1", "location": "./api.json", "priority": 1.0}},
+  "bindings": {"getUser.api": {"operation": "getUser", "source": "api", "ref": "#/paths/~1users/get"}}
+}`), &old)
+	mustUnmarshalJSON(t, []byte(`{
+  "openbindings": "0.1.0",
+  "operations": {
+    "getUser": {"description": "Fetch a user.", "input": {"required": ["id"], "type": "object"}},
+    "listUsers": {"description": "List users.", "input": {"type": "object", "required": ["page"]}, "x-beta": true},
+    "added": {}
+  },
+  "sources": {"api": {"format": "openapi@3.1", "location": "./api.json", "priority": 1}},
+  "bindings": {"getUser.api": {"operation": "getUser", "source": "api", "ref": "#/paths/~1users/get"}},
+  "transforms": {"t/1": {"type": "jsonata", "expression": "$"}}
+}`), &cur)
+
+	d, err := Diff(old, cur)
+	if err != nil {
+		t.Fatalf("diff: %v", err)
+	}
+	var got []string
+	for _, c := range d.Changes {
+		got = append(got, string(c.Kind)+" "+c.Path)
+	}
+	want := []string{
+		"added /operations/added",
+		"changed /operations/getUser/description",
+		"changed /operations/listUsers/input",
+		"added /operations/listUsers/x-beta",
+		"removed /operations/removed",
+		"added /transforms/t~11",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("unexpected changes:\n got: %q\nwant: %q", got, want)
+	}
+
+	c := string(mustMarshalJSON(t, d.Changes[1]))
+	if c != `{"path":"/operations/getUser/description","kind":"changed","old":"Get a user.","new":"Fetch a user."}` {
+		t.Fatalf("unexpected change JSON: %s", c)
+	}
+
+	same, err := Diff(old, old.Clone())
+	if err != nil || !same.Empty() {
+		t.Fatalf("expected empty diff, got %+v, %v", same, err)
+	}
+}
+
+func TestDiff_ReportsSchemasRolesSecurityAndTopLevelFields(t *testing.T) {
+	var old Interface
+	mustUnmarshalJSON(t, []byte(`{
+  "openbindings": "0.1.0",
+  "name": "users",
+  "version": "1.0.0",
+  "schemas": {"User": {"type": "object", "required": ["id"]}},
+  "operations": {"getUser": {"output": {"$ref": "#/schemas/User"}}},
+  "roles": {"directory": "./directory.json"},
+  "security": {"default": [{"type": "bearer"}]}
+}`), &old)
+
+	schemaOnly := old.Clone()
+	schemaOnly.Schemas["User"] = JSONSchema{"type": "object", "required": []any{"id", "name"}}
+	d, err := Diff(old, schemaOnly)
+	if err != nil {
+		t.Fatalf("diff: %v", err)
+	}
+	if len(d.Changes) != 1 || d.Changes[0].Path != "/schemas/User/required" || d.Changes[0].Kind != ChangeChanged {
+		t.Fatalf("expected one change to /schemas/User/required, got %+v", d.Changes)
+	}
+
+	var cur Interface
+	mustUnmarshalJSON(t, []byte(`{
+  "openbindings": "0.2.0",
+  "name": "users",
+  "description": "User directory.",
+  "schemas": {"User": {"type": "object", "required": ["id"]}, "Group": {}},
+  "operations": {"getUser": {"output": {"$ref": "#/schemas/User"}}},
+  "roles": {"directory": "./directory-v2.json"},
+  "security": {"default": [{"type": "apiKey"}]}
+}`), &cur)
+	d, err = Diff(old, cur)
+	if err != nil {
+		t.Fatalf("diff: %v", err)
+	}
+	var got []string
+	for _, c := range d.Changes {
+		got = append(got, string(c.Kind)+" "+c.Path)
+	}
+	want := []string{
+		"added /description",
+		"changed /openbindings",
+		"removed /version",
+		"added /schemas/Group",
+		"changed /roles/directory",
+		"changed /security/default",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("unexpected changes:\n got: %q\nwant: %q", got, want)
+	}
+}
@@ -53,4 +53,8 @@
 //   - canonicaljson: RFC 8785 (JCS) deterministic JSON serialization
 //   - formattoken: Parse and normalize <name>@<version> format tokens
 //   - schemaprofile: OpenBindings Schema Compatibility Profile v0.1
+//
+// YAML encoding and decoding of interface documents lives in the separate
+// github.com/openbindings/openbindings-go/obyaml module, so the core SDK has no
+// third-party dependencies.
 package openbindings
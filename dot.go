@@ -0,0 +1,71 @@
+package openbindings
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DOT renders the document's reference graph as a Graphviz DOT digraph.
+//
+// Operations, sources, and roles become nodes. Each binding becomes an edge from
+// its operation to its source (labelled with the binding key), and each satisfies
+// entry becomes an edge from its operation to the referenced role (labelled with
+// the role's operation). Output is deterministic: nodes and edges are sorted.
+// References to undeclared operations, sources, or roles still produce edges, so
+// dangling references are visible in the rendered graph.
+func (i Interface) DOT() string {
+	var b strings.Builder
+
+	name := i.Name
+	if name == "" {
+		name = "openbindings"
+	}
+	fmt.Fprintf(&b, "digraph %s {\n", dotQuote(name))
+	b.WriteString("  rankdir=LR;\n")
+
+	for _, k := range sortedKeys(i.Operations) {
+		fmt.Fprintf(&b, "  %s [label=%s, shape=box];\n", dotQuote("operation:"+k), dotQuote(k))
+	}
+	for _, k := range sortedKeys(i.Sources) {
+		label := k
+		if f := i.Sources[k].Format; f != "" {
+			label = k + "\n" + f
+		}
+		fmt.Fprintf(&b, "  %s [label=%s, shape=cylinder];\n", dotQuote("source:"+k), dotQuote(label))
+	}
+	for _, k := range sortedKeys(i.Roles) {
+		fmt.Fprintf(&b, "  %s [label=%s, shape=component];\n", dotQuote("role:"+k), dotQuote(k))
+	}
+
+	for _, k := range sortedKeys(i.Bindings) {
+		be := i.Bindings[k]
+		fmt.Fprintf(&b, "  %s -> %s [label=%s];\n",
+			dotQuote("operation:"+be.Operation), dotQuote("source:"+be.Source), dotQuote(k))
+	}
+	for _, k := range sortedKeys(i.Operations) {
+		for _, s := range i.Operations[k].Satisfies {
+			fmt.Fprintf(&b, "  %s -> %s [label=%s, style=dashed];\n",
+				dotQuote("operation:"+k), dotQuote("role:"+s.Role), dotQuote(s.Operation))
+		}
+	}
+
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// dotQuote returns s as a DOT double-quoted string.
+func dotQuote(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	return `"` + r.Replace(s) + `"`
+}
+
+// sortedKeys returns the keys of m in ascending order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,43 @@
+package openbindings
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestInterface_DOT(t *testing.T) {
+	i := Interface{
+		Name: "demo",
+		Roles: map[string]string{
+			"io.example@1.0": "https://example.com/interface.json",
+		},
+		Operations: map[string]Operation{
+			"getUser": {
+				Satisfies: []Satisfies{{Role: "io.example@1.0", Operation: "lookup"}},
+			},
+		},
+		Sources: map[string]Source{
+			"api": {Format: "openapi@3.1", Location: "./api.json"},
+		},
+		Bindings: map[string]BindingEntry{
+			"getUser.api": {Operation: "getUser", Source: "api"},
+		},
+	}
+
+	got := i.DOT()
+	for _, want := range []string{
+		`digraph "demo" {`,
+		`"operation:getUser" [label="getUser", shape=box];`,
+		`"source:api" [label="api\nopenapi@3.1", shape=cylinder];`,
+		`"role:io.example@1.0" [label="io.example@1.0", shape=component];`,
+		`"operation:getUser" -> "source:api" [label="getUser.api"];`,
+		`"operation:getUser" -> "role:io.example@1.0" [label="lookup", style=dashed];`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("expected DOT output to contain %q, got:\n%s", want, got)
+		}
+	}
+	if got != i.DOT() {
+		t.Fatalf("expected deterministic output")
+	}
+}
@@ -0,0 +1,36 @@
+package openbindings
+
+import (
+	"bytes"
+
+	"github.com/openbindings/openbindings-go/canonicaljson"
+)
+
+// Equal reports whether i and other describe the same document. Both values are
+// marshaled (including Extensions and Unknown) and compared as RFC 8785 canonical
+// JSON, so key order inside schemas or raw fields and numeric spelling (1 vs 1.0)
+// do not matter. Values that fail to marshal are never equal.
+func (i Interface) Equal(other Interface) bool {
+	a, err := i.MarshalCanonical()
+	if err != nil {
+		return false
+	}
+	b, err := other.MarshalCanonical()
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(a, b)
+}
+
+// canonicalEqual reports whether a and b marshal to the same RFC 8785 canonical JSON.
+func canonicalEqual(a, b any) (bool, error) {
+	ac, err := canonicaljson.Marshal(a)
+	if err != nil {
+		return false, err
+	}
+	bc, err := canonicaljson.Marshal(b)
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(ac, bc), nil
+}
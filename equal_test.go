@@ -0,0 +1,28 @@
+package openbindings
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestInterface_Equal(t *testing.T) {
+	a := Interface{
+		OpenBindings: "0.1.0",
+		Operations: map[string]Operation{
+			"op": {Input: JSONSchema{"type": "object", "maximum": 1.0}},
+		},
+		LosslessFields: LosslessFields{
+			Unknown: map[string]json.RawMessage{"unknownField": json.RawMessage(`{"a":1,"b":2}`)},
+		},
+	}
+	b := a.Clone()
+	b.Unknown["unknownField"] = json.RawMessage(`{ "b": 2, "a": 1.0 }`)
+	if !a.Equal(b) {
+		t.Fatalf("expected documents to be equal")
+	}
+
+	b.Operations["op"].Input["maximum"] = 2
+	if a.Equal(b) {
+		t.Fatalf("expected documents to differ after schema change")
+	}
+}
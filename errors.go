@@ -30,6 +30,14 @@ var (
 	// ErrTransformRefNotFound is returned when a transform reference cannot be resolved.
 	ErrTransformRefNotFound = errors.New("openbindings: transform reference not found")
 
+	// ErrMalformedRef is returned by TransformOrRef.ResolveErr for a reference that
+	// is not of the form "#/transforms/<name>".
+	ErrMalformedRef = errors.New("openbindings: malformed transform reference")
+
+	// ErrUnknownTransform is returned by TransformOrRef.ResolveErr for a reference to
+	// a transform that does not exist.
+	ErrUnknownTransform = errors.New("openbindings: unknown transform")
+
 	// ErrEmptyTransformExpression is returned when a transform has no expression to evaluate.
 	ErrEmptyTransformExpression = errors.New("openbindings: transform expression is empty")
 
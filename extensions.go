@@ -0,0 +1,99 @@
+package openbindings
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// GetExtension decodes the extension stored under key into a value of type T.
+// ok is false if the key is absent; err is non-nil if the raw JSON does not decode into T.
+func GetExtension[T any](lf LosslessFields, key string) (value T, ok bool, err error) {
+	raw, ok := lf.Extensions[key]
+	if !ok {
+		return value, false, nil
+	}
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return value, true, fmt.Errorf("openbindings: extension %q: %w", key, err)
+	}
+	return value, true, nil
+}
+
+// SetExtension marshals v and stores it under key. Keys must start with "x-".
+func (lf *LosslessFields) SetExtension(key string, v any) error {
+	if !strings.HasPrefix(key, "x-") {
+		return fmt.Errorf("openbindings: extension key %q must start with \"x-\"", key)
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("openbindings: extension %q: %w", key, err)
+	}
+	if lf.Extensions == nil {
+		lf.Extensions = map[string]json.RawMessage{}
+	}
+	lf.Extensions[key] = raw
+	return nil
+}
+
+// WalkExtensions calls fn for every extension (x-*) field in i: at the root and
+// in every operation, satisfies entry, operation example, source, transform, and
+// binding, including a binding's inline transforms and the x-* fields kept next
+// to a transform $ref (TransformOrRef.RefExtensions). pointer is the RFC 6901
+// JSON Pointer of the object holding the field ("" for the root). Calls are
+// ordered by pointer, then by key.
+func WalkExtensions(i Interface, fn func(pointer string, key string, raw json.RawMessage)) {
+	type ext struct {
+		pointer, key string
+		raw          json.RawMessage
+	}
+	var all []ext
+	add := func(l location, exts map[string]json.RawMessage) {
+		for k, v := range exts {
+			all = append(all, ext{pointer: l.pointer, key: k, raw: v})
+		}
+	}
+	addTransform := func(l location, t *TransformOrRef) {
+		if t == nil {
+			return
+		}
+		add(l, t.RefExtensions)
+		if t.Transform != nil {
+			add(l, t.Transform.Extensions)
+		}
+	}
+
+	add(location{}, i.Extensions)
+	for k, op := range i.Operations {
+		opAt := at("operations", k)
+		add(opAt, op.Extensions)
+		for idx, s := range op.Satisfies {
+			add(opAt.field("satisfies").index(idx), s.Extensions)
+		}
+		for ek, ex := range op.Examples {
+			add(opAt.field("examples").key(ek), ex.Extensions)
+		}
+	}
+	for k, src := range i.Sources {
+		add(at("sources", k), src.Extensions)
+	}
+	for k, tr := range i.Transforms {
+		add(at("transforms", k), tr.Extensions)
+	}
+	for k, b := range i.Bindings {
+		bAt := at("bindings", k)
+		add(bAt, b.Extensions)
+		addTransform(bAt.field("inputTransform"), b.InputTransform)
+		addTransform(bAt.field("outputTransform"), b.OutputTransform)
+	}
+
+	sort.Slice(all, func(a, b int) bool {
+		if all[a].pointer != all[b].pointer {
+			return all[a].pointer < all[b].pointer
+		}
+		return all[a].key < all[b].key
+	})
+	for _, e := range all {
+		fn(e.pointer, e.key, e.raw)
+	}
+}
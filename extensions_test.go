@@ -0,0 +1,80 @@
+package openbindings
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestExtensions_GetAndSet(t *testing.T) {
+	var op Operation
+	type owner struct {
+		Team string `json:"team"`
+	}
+	if err := op.SetExtension("x-owner", owner{Team: "platform"}); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	if err := op.SetExtension("owner", "x"); err == nil {
+		t.Fatalf("expected error for key without x- prefix")
+	}
+
+	got, ok, err := GetExtension[owner](op.LosslessFields, "x-owner")
+	if err != nil || !ok || got.Team != "platform" {
+		t.Fatalf("unexpected result: %+v ok=%v err=%v", got, ok, err)
+	}
+	if _, ok, err := GetExtension[owner](op.LosslessFields, "x-missing"); ok || err != nil {
+		t.Fatalf("expected absent key, got ok=%v err=%v", ok, err)
+	}
+	if _, ok, err := GetExtension[int](op.LosslessFields, "x-owner"); !ok || err == nil {
+		t.Fatalf("expected decode error, got ok=%v err=%v", ok, err)
+	}
+
+	out := mustUnmarshalToMap(t, mustMarshalJSON(t, op))
+	if m, _ := out["x-owner"].(map[string]any); m["team"] != "platform" {
+		t.Fatalf("expected extension marshaled, got %#v", out["x-owner"])
+	}
+}
+
+func TestWalkExtensions(t *testing.T) {
+	var i Interface
+	mustUnmarshalJSON(t, []byte(`{
+  "openbindings": "0.1.0",
+  "x-root": 1,
+  "operations": {
+    "get": {
+      "x-op": true,
+      "satisfies": [{"role": "r", "operation": "get", "x-sat": 1}],
+      "examples": {"basic": {"x-ex": 1}}
+    }
+  },
+  "sources": {"api": {"format": "openapi@3.1", "location": "./a.json", "x-src": 1}},
+  "transforms": {"t": {"type": "jsonata", "expression": "$", "x-tr": 1}},
+  "bindings": {
+    "get/api": {
+      "operation": "get", "source": "api", "x-b": 1, "x-a": 2,
+      "inputTransform": {"$ref": "#/transforms/t", "x-ref": 1},
+      "outputTransform": {"type": "jsonata", "expression": "$", "x-inline": 1}
+    }
+  }
+}`), &i)
+
+	var got []string
+	WalkExtensions(i, func(pointer, key string, raw json.RawMessage) {
+		got = append(got, pointer+" "+key+"="+string(raw))
+	})
+	want := []string{
+		" x-root=1",
+		"/bindings/get~1api x-a=2",
+		"/bindings/get~1api x-b=1",
+		"/bindings/get~1api/inputTransform x-ref=1",
+		"/bindings/get~1api/outputTransform x-inline=1",
+		"/operations/get x-op=true",
+		"/operations/get/examples/basic x-ex=1",
+		"/operations/get/satisfies/0 x-sat=1",
+		"/sources/api x-src=1",
+		"/transforms/t x-tr=1",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("unexpected walk:\n%s", strings.Join(got, "\n"))
+	}
+}
@@ -55,6 +55,83 @@ func Normalize(s string) (string, error) {
 	return t.String(), nil
 }
 
+// CompareVersion compares the versions of t and other as dotted numeric components,
+// returning -1, 0, or 1. Missing trailing components count as zero, so "3.1" and
+// "3.1.0" compare equal. It returns an error when the names differ or either version
+// is not numeric (e.g. "2025-11-25").
+func (t FormatToken) CompareVersion(other FormatToken) (int, error) {
+	if t.Name != other.Name {
+		return 0, fmt.Errorf("format token: cannot compare versions of %q and %q", t.Name, other.Name)
+	}
+	a, err := parseNumericVersion(t.Version)
+	if err != nil {
+		return 0, err
+	}
+	b, err := parseNumericVersion(other.Version)
+	if err != nil {
+		return 0, err
+	}
+	return compareNumeric(a, b), nil
+}
+
+// compareNumeric compares two parsed numeric versions, treating missing trailing
+// components as zero.
+func compareNumeric(a, b []int) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		var x, y int
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+	}
+	return 0
+}
+
+// SatisfiesAtLeast reports whether t's version is at least min. min is either a bare
+// version ("3.1") or a token with the same name ("openapi@3.1"). It returns false when
+// the versions cannot be compared.
+func (t FormatToken) SatisfiesAtLeast(min string) bool {
+	other := FormatToken{Name: t.Name, Version: strings.TrimSpace(min)}
+	if strings.Contains(min, "@") {
+		parsed, err := Parse(min)
+		if err != nil {
+			return false
+		}
+		other = parsed
+	}
+	c, err := t.CompareVersion(other)
+	return err == nil && c >= 0
+}
+
+// parseNumericVersion splits a dotted numeric version such as "3", "3.1", or "3.1.0"
+// into its components.
+func parseNumericVersion(v string) ([]int, error) {
+	if v == "" {
+		return nil, errors.New("format token: empty version")
+	}
+	parts := strings.Split(v, ".")
+	nums := make([]int, len(parts))
+	for i, p := range parts {
+		if p == "" || strings.Trim(p, "0123456789") != "" {
+			return nil, fmt.Errorf("format token: version %q is not numeric", v)
+		}
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return nil, fmt.Errorf("format token: version %q is not numeric", v)
+		}
+		nums[i] = n
+	}
+	return nums, nil
+}
+
 var nameRe = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9.\-]*$`)
 
 // IsValidName reports whether s is a valid versionless format name (e.g., "grpc").
@@ -66,7 +143,8 @@ func IsValidName(s string) bool {
 
 // IsOpenBindings reports whether the token is an OpenBindings token (name == "openbindings").
 func IsOpenBindings(t FormatToken) bool {
-	return t.Name == "openbindings"
+	meta, ok := LookupFormat(t.Name)
+	return ok && meta.Name == openBindingsName
 }
 
 // RangeKind describes the type of version constraint in a VersionRange.
@@ -76,6 +154,8 @@ const (
 	RangeVersionless RangeKind = iota
 	RangeExact
 	RangeCaret
+	RangeTilde
+	RangeComparators
 )
 
 // VersionRange represents a version constraint parsed from an executor's format token.
@@ -86,10 +166,45 @@ type VersionRange struct {
 	Major   int    // for Caret
 	Minor   int    // for Caret
 	Patch   int    // for Caret
+	// Comparators must all hold (for Tilde and Comparators). A tilde range is
+	// stored as its equivalent lower and upper bound.
+	Comparators []Comparator
+}
+
+// Comparator is one numeric version constraint of a range, e.g. ">=3.0".
+type Comparator struct {
+	Op      string // one of "=", "<", "<=", ">", ">="
+	Version []int
+}
+
+func (c Comparator) holds(v []int) bool {
+	cmp := compareNumeric(v, c.Version)
+	switch c.Op {
+	case "=":
+		return cmp == 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	}
+	return false
+}
+
+// Contains reports whether t satisfies the range; it is Matches for a parsed token.
+func (vr VersionRange) Contains(t FormatToken) bool {
+	return Matches(vr, t.String())
 }
 
 // ParseRange parses an executor format token into a VersionRange.
-// Tokens may be versionless ("grpc"), exact ("mcp@2025-11-25"), or caret ("openapi@^3.0.0").
+// Tokens may be versionless ("grpc"), exact ("mcp@2025-11-25"), caret
+// ("openapi@^3.0.0": same major, at least the given version), tilde
+// ("openapi@~3.1": same major and minor, at least the given version; "~3" allows
+// any 3.x), or a space-separated comparator list over numeric versions that must
+// all hold ("openapi@>=3.0 <4.0"). Missing version components count as zero.
 func ParseRange(s string) (VersionRange, error) {
 	s = strings.TrimSpace(s)
 	if s == "" {
@@ -133,6 +248,44 @@ func ParseRange(s string) (VersionRange, error) {
 		}, nil
 	}
 
+	if strings.HasPrefix(ver, "~") {
+		nums, err := parseNumericVersion(ver[1:])
+		if err != nil {
+			return VersionRange{}, fmt.Errorf("format range: invalid tilde version %q", ver)
+		}
+		// ~3 allows any 3.x; ~3.1 and ~3.1.2 allow any 3.1.x at or above the version.
+		upper := []int{nums[0] + 1}
+		if len(nums) > 1 {
+			upper = []int{nums[0], nums[1] + 1}
+		}
+		return VersionRange{
+			Name: name,
+			Kind: RangeTilde,
+			Comparators: []Comparator{
+				{Op: ">=", Version: nums},
+				{Op: "<", Version: upper},
+			},
+		}, nil
+	}
+
+	if strings.ContainsAny(ver[:1], "<>=") {
+		var cs []Comparator
+		for _, f := range strings.Fields(ver) {
+			op := f[:len(f)-len(strings.TrimLeft(f, "<>="))]
+			switch op {
+			case "=", "<", "<=", ">", ">=":
+			default:
+				return VersionRange{}, fmt.Errorf("format range: invalid comparator %q", f)
+			}
+			nums, err := parseNumericVersion(f[len(op):])
+			if err != nil {
+				return VersionRange{}, fmt.Errorf("format range: invalid comparator %q", f)
+			}
+			cs = append(cs, Comparator{Op: op, Version: nums})
+		}
+		return VersionRange{Name: name, Kind: RangeComparators, Comparators: cs}, nil
+	}
+
 	// Exact
 	return VersionRange{Name: name, Kind: RangeExact, Version: ver}, nil
 }
@@ -186,6 +339,18 @@ func Matches(vr VersionRange, sourceToken string) bool {
 		}
 		return nums[1] == vr.Minor && nums[2] >= vr.Patch
 
+	case RangeTilde, RangeComparators:
+		v, err := parseNumericVersion(srcVer)
+		if err != nil {
+			return false
+		}
+		for _, c := range vr.Comparators {
+			if !c.holds(v) {
+				return false
+			}
+		}
+		return true
+
 	default:
 		return false
 	}
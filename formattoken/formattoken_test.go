@@ -83,6 +83,21 @@ func TestMatches(t *testing.T) {
 		{"openapi@3.1.0", "openapi@3.1", true},
 		{"openapi@3.1", "openapi@3.1.0", true},
 		{"OpenAPI@^3.0.0", "openapi@3.1", true},
+		{"openapi@~3.1", "openapi@3.1.5", true},
+		{"openapi@~3.1", "openapi@3.1", true},
+		{"openapi@~3.1", "openapi@3.2", false},
+		{"openapi@~3.1.2", "openapi@3.1.1", false},
+		{"openapi@~3", "openapi@3.9", true},
+		{"openapi@~3", "openapi@4.0", false},
+		{"openapi@>=3.0 <4.0", "openapi@3.1", true},
+		{"openapi@>=3.0 <4.0", "openapi@3", true},
+		{"openapi@>=3.0 <4.0", "openapi@4.0.0", false},
+		{"openapi@>=3.0 <4.0", "openapi@2.0", false},
+		{"openapi@>3.0 <=3.1", "openapi@3.0.0", false},
+		{"openapi@>3.0 <=3.1", "openapi@3.1.0", true},
+		{"openapi@=3.1", "openapi@3.1.0", true},
+		{"mcp@>=2025", "mcp@2025-11-25", false},
+		{"openapi@>=3.0", "openapi", false},
 	}
 	for _, tc := range cases {
 		vr, err := ParseRange(tc.rangeToken)
@@ -95,3 +110,81 @@ func TestMatches(t *testing.T) {
 		}
 	}
 }
+
+func TestCompareVersion(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{"openapi@3.1.0", "openapi@3.0.0", 1},
+		{"openapi@3.0.0", "openapi@3.1.0", -1},
+		{"openapi@3.1", "openapi@3.1.0", 0},
+		{"openapi@3.10", "openapi@3.9", 1},
+		{"OpenAPI@2", "openapi@2.0.1", -1},
+	}
+	for _, c := range cases {
+		a, _ := Parse(c.a)
+		b, _ := Parse(c.b)
+		got, err := a.CompareVersion(b)
+		if err != nil {
+			t.Fatalf("%s vs %s: %v", c.a, c.b, err)
+		}
+		if got != c.want {
+			t.Fatalf("%s vs %s: got %d, want %d", c.a, c.b, got, c.want)
+		}
+	}
+
+	for _, c := range [][2]string{
+		{"openapi@3.1", "asyncapi@3.1"},
+		{"mcp@2025-11-25", "mcp@2025-06-18"},
+		{"openapi@3.x", "openapi@3.1"},
+	} {
+		a, _ := Parse(c[0])
+		b, _ := Parse(c[1])
+		if _, err := a.CompareVersion(b); err == nil {
+			t.Fatalf("%s vs %s: expected error", c[0], c[1])
+		}
+	}
+}
+
+func TestSatisfiesAtLeast(t *testing.T) {
+	tok, _ := Parse("openapi@3.1.0")
+	for min, want := range map[string]bool{
+		"3.0":          true,
+		"3.1":          true,
+		"3.1.1":        false,
+		"openapi@3.0":  true,
+		"asyncapi@3.0": false,
+		"latest":       false,
+		"openapi@":     false,
+	} {
+		if got := tok.SatisfiesAtLeast(min); got != want {
+			t.Fatalf("SatisfiesAtLeast(%q) = %v, want %v", min, got, want)
+		}
+	}
+}
+
+func TestParseRange_RejectsInvalidRanges(t *testing.T) {
+	for _, in := range []string{"openapi@~", "openapi@~3.x", "openapi@>=3.0 4.0", "openapi@=>3", "openapi@>=", "openapi@>=3.0 <"} {
+		if _, err := ParseRange(in); err == nil {
+			t.Errorf("ParseRange(%q): expected error", in)
+		}
+	}
+}
+
+func TestVersionRange_Contains(t *testing.T) {
+	vr, err := ParseRange("OpenAPI@>=3.0 <4.0")
+	if err != nil {
+		t.Fatalf("ParseRange: %v", err)
+	}
+	tok, err := Parse("OpenAPI@3.1")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if !vr.Contains(tok) {
+		t.Fatalf("expected %v to contain %v", vr, tok)
+	}
+	if vr.Contains(FormatToken{Name: "asyncapi", Version: "3.1"}) {
+		t.Fatal("expected a different name not to be contained")
+	}
+}
@@ -0,0 +1,72 @@
+package formattoken
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// FormatMeta describes a known format kind.
+type FormatMeta struct {
+	// Name is the canonical lowercase format name. RegisterFormat sets it.
+	Name string
+	// DisplayName is the human-readable name, e.g. "OpenAPI".
+	DisplayName string
+	// KnownVersions lists versions tooling is known to handle. It is informational;
+	// an empty list means the format is versionless or its versions are not tracked.
+	KnownVersions []string
+}
+
+const openBindingsName = "openbindings"
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]FormatMeta{}
+)
+
+func init() {
+	for name, meta := range map[string]FormatMeta{
+		openBindingsName:               {DisplayName: "OpenBindings", KnownVersions: []string{"0.1.0"}},
+		"openbindings.operation-graph": {DisplayName: "OpenBindings Operation Graph", KnownVersions: []string{"0.1.0"}},
+		"openapi":                      {DisplayName: "OpenAPI", KnownVersions: []string{"3.0", "3.1"}},
+		"asyncapi":                     {DisplayName: "AsyncAPI", KnownVersions: []string{"3.0"}},
+		"mcp":                          {DisplayName: "MCP", KnownVersions: []string{"2025-11-25"}},
+		"grpc":                         {DisplayName: "gRPC"},
+		"connect":                      {DisplayName: "Connect"},
+		"graphql":                      {DisplayName: "GraphQL"},
+		"usage":                        {DisplayName: "Usage"},
+		"workers-rpc":                  {DisplayName: "Workers RPC"},
+	} {
+		RegisterFormat(name, meta)
+	}
+}
+
+// RegisterFormat records meta as the description of the format kind name, replacing
+// any earlier registration. The name is matched case-insensitively. It panics if name
+// is not a valid versionless format name.
+func RegisterFormat(name string, meta FormatMeta) {
+	if !IsValidName(name) {
+		panic(fmt.Sprintf("formattoken: RegisterFormat: invalid name %q", name))
+	}
+	meta.Name = strings.ToLower(strings.TrimSpace(name))
+	meta.KnownVersions = append([]string(nil), meta.KnownVersions...)
+	registryMu.Lock()
+	registry[meta.Name] = meta
+	registryMu.Unlock()
+}
+
+// LookupFormat returns the registered metadata for the format kind name. name may be
+// a bare name ("openapi") or a full token ("openapi@3.1"); the version is ignored.
+func LookupFormat(name string) (FormatMeta, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if at := strings.IndexByte(name, '@'); at >= 0 {
+		name = name[:at]
+	}
+	registryMu.RLock()
+	meta, ok := registry[name]
+	registryMu.RUnlock()
+	if ok {
+		meta.KnownVersions = append([]string(nil), meta.KnownVersions...)
+	}
+	return meta, ok
+}
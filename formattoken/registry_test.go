@@ -0,0 +1,37 @@
+package formattoken
+
+import "testing"
+
+func TestLookupFormat_BuiltIns(t *testing.T) {
+	for _, name := range []string{"openapi", "OpenAPI", "openapi@3.1", "asyncapi@^3.0.0", "grpc", "openbindings"} {
+		if _, ok := LookupFormat(name); !ok {
+			t.Fatalf("expected %q to be registered", name)
+		}
+	}
+	meta, _ := LookupFormat("openapi@3.1")
+	if meta.Name != "openapi" || meta.DisplayName != "OpenAPI" {
+		t.Fatalf("unexpected meta: %#v", meta)
+	}
+	if _, ok := LookupFormat("soap"); ok {
+		t.Fatalf("expected soap to be unknown")
+	}
+}
+
+func TestRegisterFormat(t *testing.T) {
+	RegisterFormat("Example-RPC", FormatMeta{DisplayName: "Example RPC", KnownVersions: []string{"1.0"}})
+	meta, ok := LookupFormat("example-rpc@1.0")
+	if !ok || meta.Name != "example-rpc" || meta.DisplayName != "Example RPC" {
+		t.Fatalf("unexpected lookup: %#v %v", meta, ok)
+	}
+	meta.KnownVersions[0] = "mutated"
+	if again, _ := LookupFormat("example-rpc"); again.KnownVersions[0] != "1.0" {
+		t.Fatalf("expected registry to be unaffected by caller mutation")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("expected panic for invalid name")
+		}
+	}()
+	RegisterFormat("bad@name", FormatMeta{})
+}
@@ -1,5 +1,3 @@
 module github.com/openbindings/openbindings-go
 
 go 1.22
-
-
@@ -0,0 +1,259 @@
+package openbindings
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/openbindings/openbindings-go/schemaprofile"
+)
+
+// Semver impact levels reported by SemverImpact, from most to least severe.
+const (
+	ImpactBreaking   = "breaking"
+	ImpactCompatible = "compatible"
+	ImpactPatch      = "patch"
+)
+
+// SemverImpact classifies the change from old to new as a version-bump
+// recommendation and explains each contributing change. The level is the most
+// severe of:
+//
+//   - breaking: a named schema or an operation or one of its aliases or
+//     satisfies entries was removed; a named schema was changed so that it
+//     neither widened nor narrowed; an input schema was narrowed (old input is
+//     not InputCompatible with new); or an output schema was widened (new
+//     output is not OutputCompatible with old). Adding an input schema or
+//     removing an output schema counts as such, since an unspecified schema
+//     accepts any value. A schema the profile cannot compare is treated as
+//     breaking.
+//   - compatible: a named schema, operation, alias, or satisfies entry was
+//     added; a named schema was widened or narrowed (its uses are judged at the
+//     operations that reference it); an input schema was widened or an output
+//     schema narrowed, including removing an input schema or adding an output
+//     schema; or an operation was deprecated or un-deprecated.
+//   - patch: any other difference Diff reports, such as a changed description,
+//     a named schema rewritten without changing the values it accepts, or a
+//     source, binding, or transform change.
+//
+// The level is "" when Diff reports no changes. Details are prefixed with their
+// level and list named schemas by key, removed operations, then the remaining
+// operations by key, then patch-level changes in Diff order. Schemas of old
+// are resolved by a copy of n whose Root is old, and schemas of new by a copy
+// whose Root is new, so a "#/schemas/..." reference is read from its own
+// document; if n is nil, a zero Normalizer's settings are used. Only Diff and
+// document encoding failures are returned as errors.
+func SemverImpact(old, new Interface, n *schemaprofile.Normalizer) (level string, details []string, err error) {
+	if n == nil {
+		n = &schemaprofile.Normalizer{}
+	}
+	d, err := Diff(old, new)
+	if err != nil {
+		return "", nil, err
+	}
+	if d.Empty() {
+		return "", nil, nil
+	}
+	oldDoc, err := jsonFields(old)
+	if err != nil {
+		return "", nil, fmt.Errorf("openbindings: semver impact: %w", err)
+	}
+	newDoc, err := jsonFields(new)
+	if err != nil {
+		return "", nil, fmt.Errorf("openbindings: semver impact: %w", err)
+	}
+	oldN, newN := n.WithRoot(oldDoc), n.WithRoot(newDoc)
+	// normalizePair resolves each schema against its own document, so the
+	// results are self-contained and can be compared by either Normalizer.
+	normalizePair := func(o, c JSONSchema) (map[string]any, map[string]any, error) {
+		on, err := oldN.Normalize(map[string]any(o))
+		if err != nil {
+			return nil, nil, err
+		}
+		cn, err := newN.Normalize(map[string]any(c))
+		if err != nil {
+			return nil, nil, err
+		}
+		return on, cn, nil
+	}
+
+	rank := map[string]int{ImpactPatch: 1, ImpactCompatible: 2, ImpactBreaking: 3}
+	level = ImpactPatch
+	note := func(l, format string, args ...any) {
+		if rank[l] > rank[level] {
+			level = l
+		}
+		details = append(details, l+": "+fmt.Sprintf(format, args...))
+	}
+
+	schemaKeys := sortedKeys(old.Schemas)
+	for _, k := range sortedKeys(new.Schemas) {
+		if _, ok := old.Schemas[k]; !ok {
+			schemaKeys = append(schemaKeys, k)
+		}
+	}
+	sort.Strings(schemaKeys)
+	for _, k := range schemaKeys {
+		oldS, inOld := old.Schemas[k]
+		newS, inNew := new.Schemas[k]
+		switch {
+		case !inNew:
+			note(ImpactBreaking, "schemas[%q]: removed", k)
+			continue
+		case !inOld:
+			note(ImpactCompatible, "schemas[%q]: added", k)
+			continue
+		}
+		if same, _ := canonicalEqual(oldS, newS); same {
+			continue
+		}
+		on, nn, err := normalizePair(oldS, newS)
+		if err != nil {
+			note(ImpactBreaking, "schemas[%q]: cannot compare schemas: %v", k, err)
+			continue
+		}
+		widened, reason, err := newN.InputCompatible(on, nn)
+		if err != nil {
+			note(ImpactBreaking, "schemas[%q]: cannot compare schemas: %v", k, err)
+			continue
+		}
+		narrowed, _, err := newN.InputCompatible(nn, on)
+		if err != nil {
+			note(ImpactBreaking, "schemas[%q]: cannot compare schemas: %v", k, err)
+			continue
+		}
+		switch {
+		case widened && narrowed:
+			note(ImpactPatch, "schemas[%q]: rewritten without changing accepted values", k)
+		case widened:
+			note(ImpactCompatible, "schemas[%q]: widened", k)
+		case narrowed:
+			note(ImpactCompatible, "schemas[%q]: narrowed", k)
+		default:
+			note(ImpactBreaking, "schemas[%q]: %s", k, reason)
+		}
+	}
+
+	for _, k := range sortedKeys(old.Operations) {
+		if _, ok := new.Operations[k]; !ok {
+			note(ImpactBreaking, "operations[%q]: removed", k)
+		}
+	}
+	for _, k := range sortedKeys(new.Operations) {
+		newOp := new.Operations[k]
+		oldOp, ok := old.Operations[k]
+		if !ok {
+			note(ImpactCompatible, "operations[%q]: added", k)
+			continue
+		}
+
+		for _, a := range stringsMissing(oldOp.Aliases, newOp.Aliases) {
+			note(ImpactBreaking, "operations[%q].aliases: %q removed", k, a)
+		}
+		for _, a := range stringsMissing(newOp.Aliases, oldOp.Aliases) {
+			note(ImpactCompatible, "operations[%q].aliases: %q added", k, a)
+		}
+		oldSat, newSat := satisfiesKeys(oldOp.Satisfies), satisfiesKeys(newOp.Satisfies)
+		for _, s := range stringsMissing(oldSat, newSat) {
+			note(ImpactBreaking, "operations[%q].satisfies: %s removed", k, s)
+		}
+		for _, s := range stringsMissing(newSat, oldSat) {
+			note(ImpactCompatible, "operations[%q].satisfies: %s added", k, s)
+		}
+		if newOp.Deprecated && !oldOp.Deprecated {
+			note(ImpactCompatible, "operations[%q]: deprecated", k)
+		}
+		if oldOp.Deprecated && !newOp.Deprecated {
+			note(ImpactCompatible, "operations[%q]: no longer deprecated", k)
+		}
+
+		for _, dir := range []struct {
+			field    string
+			old, new JSONSchema
+			check    func(tgt, cand map[string]any) (bool, string, error)
+			// narrowed and widened are the levels of adding and removing the schema.
+			narrowed, widened string
+		}{
+			{"input", oldOp.Input, newOp.Input, newN.InputCompatible, ImpactBreaking, ImpactCompatible},
+			{"output", oldOp.Output, newOp.Output, newN.OutputCompatible, ImpactCompatible, ImpactBreaking},
+		} {
+			switch {
+			case dir.old == nil && dir.new == nil:
+				continue
+			case dir.old == nil:
+				// An unspecified schema accepts any value, so adding one narrows it.
+				note(dir.narrowed, "operations[%q].%s: schema added", k, dir.field)
+				continue
+			case dir.new == nil:
+				note(dir.widened, "operations[%q].%s: schema removed", k, dir.field)
+				continue
+			}
+			on, nn, err := normalizePair(dir.old, dir.new)
+			if err != nil {
+				note(ImpactBreaking, "operations[%q].%s: cannot compare schemas: %v", k, dir.field, err)
+				continue
+			}
+			ok, reason, err := dir.check(on, nn)
+			switch {
+			case err != nil:
+				note(ImpactBreaking, "operations[%q].%s: cannot compare schemas: %v", k, dir.field, err)
+			case !ok:
+				note(ImpactBreaking, "operations[%q].%s: %s", k, dir.field, reason)
+			default:
+				if same, _ := canonicalEqual(dir.old, dir.new); !same {
+					note(ImpactCompatible, "operations[%q].%s: schema changed compatibly", k, dir.field)
+				}
+			}
+		}
+	}
+
+	// Everything else Diff reports is a patch-level change.
+	for _, c := range d.Changes {
+		if strings.HasPrefix(c.Path, "/schemas/") {
+			continue
+		}
+		if strings.HasPrefix(c.Path, "/operations/") && impactCoversOperationPath(c.Path) {
+			continue
+		}
+		note(ImpactPatch, "%s %s", c.Path, c.Kind)
+	}
+	return level, details, nil
+}
+
+// impactCoversOperationPath reports whether SemverImpact classifies the change
+// at path itself: whole operations and their aliases, satisfies, deprecated,
+// input, and output fields.
+func impactCoversOperationPath(path string) bool {
+	toks := strings.Split(strings.TrimPrefix(path, "/operations/"), "/")
+	if len(toks) == 1 {
+		return true
+	}
+	switch toks[1] {
+	case "aliases", "satisfies", "deprecated", "input", "output":
+		return true
+	}
+	return false
+}
+
+// stringsMissing returns the elements of a that are not in b, in a's order.
+func stringsMissing(a, b []string) []string {
+	in := make(map[string]struct{}, len(b))
+	for _, s := range b {
+		in[s] = struct{}{}
+	}
+	var out []string
+	for _, s := range a {
+		if _, ok := in[s]; !ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func satisfiesKeys(sat []Satisfies) []string {
+	out := make([]string, len(sat))
+	for idx, s := range sat {
+		out[idx] = fmt.Sprintf("%q/%q", s.Role, s.Operation)
+	}
+	return out
+}
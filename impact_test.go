@@ -0,0 +1,211 @@
+package openbindings
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func impactTestInterface(t *testing.T, doc string) Interface {
+	t.Helper()
+	var i Interface
+	mustUnmarshalJSON(t, []byte(doc), &i)
+	return i
+}
+
+func TestSemverImpact(t *testing.T) {
+	base := `{
+  "openbindings": "0.1.0",
+  "operations": {
+    "setColor": {
+      "description": "Set the color.",
+      "input": {"type": "object", "properties": {"color": {"enum": ["red", "green"]}, "note": {"type": "string"}}, "required": ["color"]},
+      "output": {"type": "object", "properties": {"color": {"enum": ["red", "green"]}}}
+    }
+  }
+}`
+	cases := []struct {
+		name    string
+		new     string
+		level   string
+		details []string
+	}{
+		{
+			name:  "unchanged",
+			new:   base,
+			level: "",
+		},
+		{
+			name: "tightening a required input field is breaking",
+			new: `{"openbindings": "0.1.0", "operations": {"setColor": {
+  "description": "Set the color.",
+  "input": {"type": "object", "properties": {"color": {"enum": ["red", "green"]}, "note": {"type": "string"}}, "required": ["color", "note"]},
+  "output": {"type": "object", "properties": {"color": {"enum": ["red", "green"]}}}}}}`,
+			level:   ImpactBreaking,
+			details: []string{`breaking: operations["setColor"].input: required: candidate requires "note" but target does not`},
+		},
+		{
+			name: "widening an input enum is compatible",
+			new: `{"openbindings": "0.1.0", "operations": {"setColor": {
+  "description": "Set the color.",
+  "input": {"type": "object", "properties": {"color": {"enum": ["red", "green", "blue"]}, "note": {"type": "string"}}, "required": ["color"]},
+  "output": {"type": "object", "properties": {"color": {"enum": ["red", "green"]}}}}}}`,
+			level:   ImpactCompatible,
+			details: []string{`compatible: operations["setColor"].input: schema changed compatibly`},
+		},
+		{
+			name: "widening an output enum is breaking",
+			new: `{"openbindings": "0.1.0", "operations": {"setColor": {
+  "description": "Set the color.",
+  "input": {"type": "object", "properties": {"color": {"enum": ["red", "green"]}, "note": {"type": "string"}}, "required": ["color"]},
+  "output": {"type": "object", "properties": {"color": {"enum": ["red", "green", "blue"]}}}}}}`,
+			level: ImpactBreaking,
+		},
+		{
+			name:  "adding an operation is compatible, removing one is breaking",
+			new:   `{"openbindings": "0.1.0", "operations": {"getColor": {}}}`,
+			level: ImpactBreaking,
+			details: []string{
+				`breaking: operations["setColor"]: removed`,
+				`compatible: operations["getColor"]: added`,
+			},
+		},
+		{
+			name: "description change is a patch",
+			new: `{"openbindings": "0.1.0", "operations": {"setColor": {
+  "description": "Set the current color.",
+  "input": {"required": ["color"], "type": "object", "properties": {"color": {"enum": ["red", "green"]}, "note": {"type": "string"}}},
+  "output": {"type": "object", "properties": {"color": {"enum": ["red", "green"]}}}}}}`,
+			level:   ImpactPatch,
+			details: []string{"patch: /operations/setColor/description changed"},
+		},
+	}
+	old := impactTestInterface(t, base)
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			level, details, err := SemverImpact(old, impactTestInterface(t, c.new), nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if level != c.level {
+				t.Fatalf("level = %q, want %q (details %q)", level, c.level, details)
+			}
+			if c.details != nil && !reflect.DeepEqual(details, c.details) {
+				t.Fatalf("details = %q, want %q", details, c.details)
+			}
+		})
+	}
+}
+
+func TestSemverImpact_NamedSchemas(t *testing.T) {
+	doc := func(colors, extra string) string {
+		return `{
+  "openbindings": "0.1.0",
+  "schemas": {"Color": {"enum": [` + colors + `]}` + extra + `},
+  "operations": {
+    "setColor": {"input": {"$ref": "#/schemas/Color"}},
+    "getColor": {"output": {"$ref": "#/schemas/Color"}}
+  }
+}`
+	}
+	old := impactTestInterface(t, doc(`"red", "green"`, `, "Unused": {"type": "string"}`))
+	cases := []struct {
+		name    string
+		new     string
+		level   string
+		details []string
+	}{
+		{
+			name:  "widening a referenced schema breaks the output that uses it",
+			new:   doc(`"red", "green", "blue"`, `, "Unused": {"type": "string"}`),
+			level: ImpactBreaking,
+			details: []string{
+				`compatible: schemas["Color"]: widened`,
+				`breaking: operations["getColor"].output: enum: candidate value "blue" not in target enum`,
+			},
+		},
+		{
+			name:    "removing a named schema is breaking",
+			new:     doc(`"red", "green"`, ``),
+			level:   ImpactBreaking,
+			details: []string{`breaking: schemas["Unused"]: removed`},
+		},
+		{
+			name:    "changing an unreferenced schema incompatibly is breaking",
+			new:     doc(`"red", "green"`, `, "Unused": {"type": "integer"}`),
+			level:   ImpactBreaking,
+			details: []string{`breaking: schemas["Unused"]: type: candidate does not allow "string"`},
+		},
+		{
+			name:    "rewriting a schema without changing its values is a patch",
+			new:     doc(`"green", "red"`, `, "Unused": {"type": "string"}`),
+			level:   ImpactPatch,
+			details: []string{`patch: schemas["Color"]: rewritten without changing accepted values`},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			level, details, err := SemverImpact(old, impactTestInterface(t, c.new), nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if level != c.level {
+				t.Fatalf("level = %q, want %q (details %q)", level, c.level, details)
+			}
+			if !reflect.DeepEqual(details, c.details) {
+				t.Fatalf("details = %q, want %q", details, c.details)
+			}
+		})
+	}
+}
+
+func TestSemverImpact_AddedAndRemovedSchemas(t *testing.T) {
+	schema := `{"type": "object", "properties": {"color": {"type": "string"}}}`
+	cases := []struct {
+		name     string
+		old, new string
+		want     string
+	}{
+		{"adding an input schema is breaking", `{}`, `{"input": ` + schema + `}`, `breaking: operations["op"].input: schema added`},
+		{"removing an input schema is compatible", `{"input": ` + schema + `}`, `{}`, `compatible: operations["op"].input: schema removed`},
+		{"adding an output schema is compatible", `{}`, `{"output": ` + schema + `}`, `compatible: operations["op"].output: schema added`},
+		{"removing an output schema is breaking", `{"output": ` + schema + `}`, `{}`, `breaking: operations["op"].output: schema removed`},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			old := impactTestInterface(t, `{"openbindings": "0.1.0", "operations": {"op": `+c.old+`}}`)
+			cur := impactTestInterface(t, `{"openbindings": "0.1.0", "operations": {"op": `+c.new+`}}`)
+			level, details, err := SemverImpact(old, cur, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if want := strings.SplitN(c.want, ":", 2)[0]; level != want {
+				t.Fatalf("level = %q, want %q (details %q)", level, want, details)
+			}
+			if !reflect.DeepEqual(details, []string{c.want}) {
+				t.Fatalf("details = %q, want %q", details, []string{c.want})
+			}
+		})
+	}
+}
+
+func TestSemverImpact_Deprecation(t *testing.T) {
+	plain := impactTestInterface(t, `{"openbindings": "0.1.0", "operations": {"op": {}}}`)
+	deprecated := impactTestInterface(t, `{"openbindings": "0.1.0", "operations": {"op": {"deprecated": true}}}`)
+	for _, c := range []struct {
+		name     string
+		old, new Interface
+		want     string
+	}{
+		{"deprecating", plain, deprecated, `compatible: operations["op"]: deprecated`},
+		{"un-deprecating", deprecated, plain, `compatible: operations["op"]: no longer deprecated`},
+	} {
+		level, details, err := SemverImpact(c.old, c.new, nil)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", c.name, err)
+		}
+		if level != ImpactCompatible || !reflect.DeepEqual(details, []string{c.want}) {
+			t.Fatalf("%s: got %q %q, want %q", c.name, level, details, c.want)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package openbindings
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// LineError is the error for one line of a JSON Lines stream of interfaces.
+type LineError struct {
+	// Line is the 1-based line number.
+	Line int
+	Err  error
+}
+
+func (e *LineError) Error() string {
+	return fmt.Sprintf("openbindings: line %d: %v", e.Line, e.Err)
+}
+
+func (e *LineError) Unwrap() error { return e.Err }
+
+// ScanInterfaces reads a JSON Lines stream holding one interface document per line
+// and calls fn for each, in order, with the line number and either the document or
+// the *LineError it failed with; decoding continues past failed lines. Each line is
+// decoded as by DecodeInterface(opts...), so WithValidation validates each document.
+// Blank lines are skipped. Scanning stops early when fn returns false. The returned
+// error is a read error from r, if any.
+func ScanInterfaces(r io.Reader, fn func(line int, iface *Interface, err error) bool, opts ...DecodeOption) error {
+	br := bufio.NewReader(r)
+	for line := 1; ; line++ {
+		b, err := br.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return err
+		}
+		if doc := bytes.TrimSpace(b); len(doc) > 0 {
+			iface, derr := DecodeInterface(bytes.NewReader(doc), opts...)
+			if derr != nil {
+				iface, derr = nil, &LineError{Line: line, Err: derr}
+			}
+			if !fn(line, iface, derr) {
+				return nil
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+	}
+}
+
+// DecodeInterfaces reads every interface of a JSON Lines stream, as ScanInterfaces
+// does. It returns the documents that decoded, in order, and the errors of the
+// lines that did not, joined, each a *LineError.
+func DecodeInterfaces(r io.Reader, opts ...DecodeOption) ([]Interface, error) {
+	var out []Interface
+	var errs []error
+	err := ScanInterfaces(r, func(_ int, iface *Interface, err error) bool {
+		if err != nil {
+			errs = append(errs, err)
+		} else {
+			out = append(out, *iface)
+		}
+		return true
+	}, opts...)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	return out, errors.Join(errs...)
+}
+
+// EncodeInterfaces writes ifaces to w as JSON Lines: the canonical encoding of each
+// document (see Interface.MarshalCanonical) followed by a newline.
+func EncodeInterfaces(w io.Writer, ifaces []Interface) error {
+	bw := bufio.NewWriter(w)
+	for n, iface := range ifaces {
+		b, err := iface.MarshalCanonical()
+		if err != nil {
+			return fmt.Errorf("openbindings: interface %d: %w", n, err)
+		}
+		bw.Write(b)
+		bw.WriteByte('\n')
+	}
+	return bw.Flush()
+}
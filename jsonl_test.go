@@ -0,0 +1,78 @@
+package openbindings
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDecodeInterfaces(t *testing.T) {
+	stream := strings.Join([]string{
+		`{"openbindings":"0.1.0","name":"a","operations":{"x":{}}}`,
+		``,
+		`{"openbindings":"0.1.0","name":"broken"`,
+		`{"openbindings":"0.1.0","name":"invalid","operations":{"x":{}},"bindings":{"x.api":{"operation":"x","source":"api"}}}`,
+		`  {"openbindings":"0.1.0","name":"b","operations":{"y":{}},"x-team":"core"}`,
+	}, "\n")
+
+	ifaces, err := DecodeInterfaces(strings.NewReader(stream))
+	var le *LineError
+	if !errors.As(err, &le) || le.Line != 3 {
+		t.Fatalf("expected an error for line 3, got %v", err)
+	}
+	if len(ifaces) != 3 || ifaces[0].Name != "a" || ifaces[2].Name != "b" {
+		t.Fatalf("unexpected interfaces %+v", ifaces)
+	}
+
+	ifaces, err = DecodeInterfaces(strings.NewReader(stream), WithValidation())
+	if len(ifaces) != 2 {
+		t.Fatalf("expected 2 valid interfaces, got %d", len(ifaces))
+	}
+	want := `openbindings: line 4: invalid interface: bindings["x.api"].source: references unknown source "api"`
+	if err == nil || !strings.Contains(err.Error(), want) {
+		t.Fatalf("expected %q, got %v", want, err)
+	}
+	var ve *ValidationError
+	if !errors.As(err, &ve) {
+		t.Fatalf("expected the ValidationError to be reachable, got %T", err)
+	}
+
+	var lines []int
+	if err := ScanInterfaces(strings.NewReader(stream), func(line int, _ *Interface, _ error) bool {
+		lines = append(lines, line)
+		return line < 3
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if len(lines) != 2 || lines[0] != 1 || lines[1] != 3 {
+		t.Fatalf("expected to stop after line 3 skipping the blank line, got %v", lines)
+	}
+}
+
+func TestEncodeInterfaces_RoundTrip(t *testing.T) {
+	idempotent := true
+	in := []Interface{
+		{OpenBindings: "0.1.0", Name: "a", Description: "line\nbreak", Operations: map[string]Operation{"x": {}}},
+		{OpenBindings: "0.1.0", Name: "b", Operations: map[string]Operation{"y": {Idempotent: &idempotent}}},
+	}
+	var buf bytes.Buffer
+	if err := EncodeInterfaces(&buf, in); err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(buf.String(), "\n"); n != 2 {
+		t.Fatalf("expected one line per interface, got %q", buf.String())
+	}
+	first, _ := in[0].MarshalCanonical()
+	if !strings.HasPrefix(buf.String(), string(first)+"\n") {
+		t.Fatalf("expected canonical lines, got %q", buf.String())
+	}
+
+	out, err := DecodeInterfaces(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out) != 2 || !out[0].Equal(in[0]) || !out[1].Equal(in[1]) {
+		t.Fatalf("round trip mismatch: %+v", out)
+	}
+}
@@ -0,0 +1,54 @@
+package openbindings
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// wireObject is a wire struct: the typed fields of a lossless type, with the
+// receiver for each key it models.
+type wireObject interface {
+	field(key string) any
+}
+
+// knownFieldKinds maps each KnownFields kind to its wire struct.
+var knownFieldKinds = map[string]wireObject{
+	"interface": &interfaceWire{},
+	"operation": &operationWire{},
+	"satisfies": &satisfiesWire{},
+	"example":   &operationExampleWire{},
+	"source":    &sourceWire{},
+	"transform": &transformWire{},
+	"binding":   &bindingEntryWire{},
+	"contact":   &contactWire{},
+	"license":   &licenseWire{},
+}
+
+// KnownFieldKinds returns the kinds KnownFields accepts, sorted.
+func KnownFieldKinds() []string {
+	return sortedKeys(knownFieldKinds)
+}
+
+// KnownFields returns the sorted JSON member names the SDK models for kind: one of
+// "interface", "operation", "satisfies", "example", "source", "transform",
+// "binding", "contact", or "license". Other members are kept in Unknown (or
+// Extensions, for x- names) when decoding, so tooling can diff this list against
+// the spec's to find fields the SDK has not caught up with. It returns nil for an
+// unrecognized kind.
+func KnownFields(kind string) []string {
+	w, ok := knownFieldKinds[kind]
+	if !ok {
+		return nil
+	}
+	t := reflect.TypeOf(w).Elem()
+	fields := make([]string, 0, t.NumField())
+	for idx := 0; idx < t.NumField(); idx++ {
+		name, _, _ := strings.Cut(t.Field(idx).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			fields = append(fields, name)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
@@ -0,0 +1,30 @@
+package openbindings
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestKnownFields(t *testing.T) {
+	want := []string{"aliases", "deprecated", "description", "examples", "idempotent", "input", "output", "satisfies", "security", "tags"}
+	if got := KnownFields("operation"); !reflect.DeepEqual(got, want) {
+		t.Fatalf("KnownFields(operation) = %v, want %v", got, want)
+	}
+	if got := KnownFields("nope"); got != nil {
+		t.Fatalf("expected nil for an unknown kind, got %v", got)
+	}
+
+	// Every listed field must be one the decoder models, so the list cannot drift
+	// from the decoding logic.
+	for _, kind := range KnownFieldKinds() {
+		fields := KnownFields(kind)
+		if len(fields) == 0 {
+			t.Fatalf("%s: no known fields", kind)
+		}
+		for _, f := range fields {
+			if knownFieldKinds[kind].field(f) == nil {
+				t.Errorf("%s: field %q is listed but not decoded", kind, f)
+			}
+		}
+	}
+}
@@ -1,67 +1,119 @@
 package openbindings
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
 	"strings"
 )
 
-// splitLossless separates unknown fields into:
-// - extensions: keys starting with "x-"
-// - unknown: all other keys not in known
-func splitLossless(raw map[string]json.RawMessage, known map[string]struct{}) (extensions, unknown map[string]json.RawMessage) {
+// unmarshalLossless decodes the JSON object b in a single pass over its entries.
+// For each key, field returns a pointer to the typed field that receives the value,
+// or nil for keys the type does not model. Known values are decoded from their
+// already-split raw bytes, so the object is never parsed a second time as a whole.
+// Unmodeled keys are returned as extensions ("x-" keys) and unknown (all others).
+func unmarshalLossless(b []byte, field func(key string) any) (extensions, unknown map[string]json.RawMessage, err error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return nil, nil, err
+	}
+	return unmarshalLosslessRaw(raw, field)
+}
+
+// unmarshalLosslessRaw is unmarshalLossless for an object already split into raw entries.
+// If several known values fail to decode, the error for the smallest key is returned
+// so the result does not depend on map iteration order.
+func unmarshalLosslessRaw(raw map[string]json.RawMessage, field func(key string) any) (extensions, unknown map[string]json.RawMessage, err error) {
+	var errKey string
 	for k, v := range raw {
-		if _, ok := known[k]; ok {
+		target := field(k)
+		if target == nil {
+			extensions, unknown = addLossless(extensions, unknown, k, v)
 			continue
 		}
-		if strings.HasPrefix(k, "x-") {
-			if extensions == nil {
-				extensions = map[string]json.RawMessage{}
-			}
-			extensions[k] = v
-			continue
-		}
-		if unknown == nil {
-			unknown = map[string]json.RawMessage{}
+		if e := json.Unmarshal(v, target); e != nil && (err == nil || k < errKey) {
+			err, errKey = fmt.Errorf("%s: %w", k, e), k
 		}
-		unknown[k] = v
 	}
-	return extensions, unknown
+	if err != nil {
+		return nil, nil, err
+	}
+	return extensions, unknown, nil
 }
 
-// knownSet builds a map for constant-time known-field checks in lossless unmarshaling.
-func knownSet(keys ...string) map[string]struct{} {
-	if len(keys) == 0 {
-		return map[string]struct{}{}
+// addLossless files an unmodeled key under extensions if it starts with "x-",
+// and under unknown otherwise, allocating the maps on first use.
+func addLossless(extensions, unknown map[string]json.RawMessage, k string, v json.RawMessage) (map[string]json.RawMessage, map[string]json.RawMessage) {
+	if strings.HasPrefix(k, "x-") {
+		if extensions == nil {
+			extensions = map[string]json.RawMessage{}
+		}
+		extensions[k] = v
+		return extensions, unknown
 	}
-	out := make(map[string]struct{}, len(keys))
-	for _, k := range keys {
-		out[k] = struct{}{}
+	if unknown == nil {
+		unknown = map[string]json.RawMessage{}
 	}
-	return out
+	unknown[k] = v
+	return extensions, unknown
 }
 
 // marshalLossless merges unknown + extensions with the typed view such that known fields win.
+// The object is emitted in a fixed order: the typed fields in declaration (spec) order,
+// then extensions sorted by key, then unknown fields sorted by key.
 func marshalLossless(unknown, extensions map[string]json.RawMessage, typed any) ([]byte, error) {
-	// Start from the lossless fields, then overwrite with the typed view so known fields win.
-	out := map[string]json.RawMessage{}
-	for k, v := range unknown {
-		out[k] = v
-	}
-	for k, v := range extensions {
-		out[k] = v
-	}
-
 	knownBytes, err := json.Marshal(typed)
 	if err != nil {
 		return nil, err
 	}
+	if len(unknown) == 0 && len(extensions) == 0 {
+		return knownBytes, nil
+	}
 	var known map[string]json.RawMessage
 	if err := json.Unmarshal(knownBytes, &known); err != nil {
 		return nil, err
 	}
-	for k, v := range known {
-		out[k] = v
-	}
 
-	return json.Marshal(out)
+	// Reopen the typed object and append the lossless fields it does not shadow.
+	// An extension also present in unknown is written once, from extensions.
+	buf := bytes.NewBuffer(knownBytes[:len(knownBytes)-1])
+	first := len(known) == 0
+	write := func(k string, v json.RawMessage) error {
+		if !first {
+			buf.WriteByte(',')
+		}
+		first = false
+		key, _ := json.Marshal(k)
+		buf.Write(key)
+		buf.WriteByte(':')
+		if len(v) == 0 {
+			buf.WriteString("null")
+			return nil
+		}
+		if err := json.Compact(buf, v); err != nil {
+			return fmt.Errorf("%s: %w", k, err)
+		}
+		return nil
+	}
+	for _, k := range sortedKeys(extensions) {
+		if _, ok := known[k]; ok {
+			continue
+		}
+		if err := write(k, extensions[k]); err != nil {
+			return nil, err
+		}
+	}
+	for _, k := range sortedKeys(unknown) {
+		if _, ok := known[k]; ok {
+			continue
+		}
+		if _, ok := extensions[k]; ok {
+			continue
+		}
+		if err := write(k, unknown[k]); err != nil {
+			return nil, err
+		}
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
 }
@@ -0,0 +1,145 @@
+package openbindings
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Merge combines an interface fragment overlay on top of base, as when a document
+// is composed from a base file plus overlays. Neither argument is modified, and
+// the result shares no memory with them.
+//
+// Collision rules:
+//   - openbindings: the versions must be equal if both are set; otherwise Merge
+//     returns an error. An unset version takes the other's.
+//   - name, version, description: overlay wins when set.
+//   - schemas, operations, roles, sources, bindings, security, transforms: the
+//     maps are unioned by key. On a key collision the overlay entry replaces the
+//     base entry whole; entries are not merged field by field. In particular an
+//     overlay operation's satisfies and aliases replace the base operation's,
+//     even when the overlay leaves them empty. Operation.Merge combines two
+//     definitions of one operation instead.
+//   - top-level extensions and unknown fields: unioned by key, overlay wins.
+//
+// Merge does not validate the result; call Validate on it if needed.
+func Merge(base, overlay Interface) (Interface, error) {
+	if base.OpenBindings != "" && overlay.OpenBindings != "" && base.OpenBindings != overlay.OpenBindings {
+		return Interface{}, fmt.Errorf("openbindings: merge: conflicting openbindings versions %q and %q", base.OpenBindings, overlay.OpenBindings)
+	}
+
+	out := base.Clone()
+	ov := overlay.Clone()
+
+	if ov.OpenBindings != "" {
+		out.OpenBindings = ov.OpenBindings
+	}
+	if ov.Name != "" {
+		out.Name = ov.Name
+	}
+	if ov.Version != "" {
+		out.Version = ov.Version
+	}
+	if ov.Description != "" {
+		out.Description = ov.Description
+	}
+
+	out.Schemas = mergeEntries(out.Schemas, ov.Schemas)
+	out.Operations = mergeEntries(out.Operations, ov.Operations)
+	out.Roles = mergeEntries(out.Roles, ov.Roles)
+	out.Sources = mergeEntries(out.Sources, ov.Sources)
+	out.Bindings = mergeEntries(out.Bindings, ov.Bindings)
+	out.Security = mergeEntries(out.Security, ov.Security)
+	out.Transforms = mergeEntries(out.Transforms, ov.Transforms)
+
+	out.Extensions = mergeEntries(out.Extensions, ov.Extensions)
+	out.Unknown = mergeEntries(out.Unknown, ov.Unknown)
+
+	return out, nil
+}
+
+// mergeEntries copies every entry of src into dst, replacing existing keys, and
+// returns dst (allocated if needed). A nil dst with a nil src stays nil.
+func mergeEntries[V any](dst, src map[string]V) map[string]V {
+	if src == nil {
+		return dst
+	}
+	if dst == nil {
+		dst = make(map[string]V, len(src))
+	}
+	for k, v := range src {
+		dst[k] = v
+	}
+	return dst
+}
+
+// Merge combines a second definition overlay of the same operation on top of o, as
+// when composing interfaces. Neither operation is modified, and the result shares
+// no memory with them.
+//
+// Collision rules:
+//   - tags, aliases: unioned, o's entries first, without duplicates.
+//   - satisfies: concatenated, without duplicate role and operation pairs; for a
+//     duplicate the overlay entry replaces o's in place.
+//   - idempotent: the values must be equal if both are set; otherwise Merge returns
+//     an error. An unset value takes the other's.
+//   - description, input, output, security: overlay wins when set.
+//   - deprecated: set if either is.
+//   - examples, extensions, unknown fields: unioned by key, overlay wins.
+func (o Operation) Merge(overlay Operation) (Operation, error) {
+	if o.Idempotent != nil && overlay.Idempotent != nil && *o.Idempotent != *overlay.Idempotent {
+		return Operation{}, fmt.Errorf("openbindings: merge: conflicting idempotent values %t and %t", *o.Idempotent, *overlay.Idempotent)
+	}
+
+	out := o.Clone()
+	ov := overlay.Clone()
+
+	if ov.Description != "" {
+		out.Description = ov.Description
+	}
+	out.Deprecated = out.Deprecated || ov.Deprecated
+	out.Tags = unionStrings(out.Tags, ov.Tags)
+	out.Aliases = unionStrings(out.Aliases, ov.Aliases)
+	for _, s := range ov.Satisfies {
+		idx := slices.IndexFunc(out.Satisfies, func(e Satisfies) bool {
+			return e.Role == s.Role && e.Operation == s.Operation
+		})
+		if idx >= 0 {
+			out.Satisfies[idx] = s
+		} else {
+			out.Satisfies = append(out.Satisfies, s)
+		}
+	}
+	if ov.Idempotent != nil {
+		out.Idempotent = ov.Idempotent
+	}
+	if ov.Input != nil {
+		out.Input = ov.Input
+	}
+	if ov.Output != nil {
+		out.Output = ov.Output
+	}
+	if ov.Security != nil {
+		out.Security = ov.Security
+	}
+	out.Examples = mergeEntries(out.Examples, ov.Examples)
+
+	out.Extensions = mergeEntries(out.Extensions, ov.Extensions)
+	out.Unknown = mergeEntries(out.Unknown, ov.Unknown)
+
+	return out, nil
+}
+
+// unionStrings returns the distinct strings of a and then b, in order of first
+// occurrence.
+func unionStrings(a, b []string) []string {
+	if len(a) == 0 && len(b) == 0 {
+		return a
+	}
+	out := make([]string, 0, len(a)+len(b))
+	for _, s := range slices.Concat(a, b) {
+		if !slices.Contains(out, s) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
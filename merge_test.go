@@ -0,0 +1,151 @@
+package openbindings
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestMerge_OverlayWinsOnOperationKeyCollision(t *testing.T) {
+	var base, overlay Interface
+	mustUnmarshalJSON(t, []byte(`{
+  "openbindings": "0.1.0",
+  "name": "Base",
+  "description": "Base description.",
+  "roles": {"store": "https://example.com/store.json"},
+  "operations": {
+    "getItem": {"description": "Base get.", "aliases": ["fetchItem"], "satisfies": [{"role": "store", "operation": "getItem"}]},
+    "listItems": {"description": "Base list."}
+  },
+  "sources": {"api": {"format": "openapi@3.1", "location": "./base.json"}},
+  "x-owner": "base",
+  "x-team": "core"
+}`), &base)
+	mustUnmarshalJSON(t, []byte(`{
+  "name": "Overlay",
+  "operations": {
+    "getItem": {"description": "Overlay get.", "aliases": ["readItem"]},
+    "deleteItem": {}
+  },
+  "sources": {"api": {"format": "openapi@3.1", "location": "./overlay.json"}},
+  "bindings": {"getItem.api": {"operation": "getItem", "source": "api"}},
+  "x-owner": "overlay"
+}`), &overlay)
+	baseBefore := base.Clone()
+
+	got, err := Merge(base, overlay)
+	if err != nil {
+		t.Fatalf("merge: %v", err)
+	}
+
+	if got.OpenBindings != "0.1.0" || got.Name != "Overlay" || got.Description != "Base description." {
+		t.Fatalf("unexpected scalars: %q %q %q", got.OpenBindings, got.Name, got.Description)
+	}
+	if keys := sortedKeys(got.Operations); !reflect.DeepEqual(keys, []string{"deleteItem", "getItem", "listItems"}) {
+		t.Fatalf("unexpected operations %v", keys)
+	}
+	op := got.Operations["getItem"]
+	if op.Description != "Overlay get." || !reflect.DeepEqual(op.Aliases, []string{"readItem"}) || op.Satisfies != nil {
+		t.Fatalf("expected overlay operation to replace base whole, got %+v", op)
+	}
+	if got.Sources["api"].Location != "./overlay.json" {
+		t.Fatalf("expected overlay source, got %+v", got.Sources["api"])
+	}
+	if len(got.Bindings) != 1 || len(got.Roles) != 1 {
+		t.Fatalf("expected bindings and roles unioned, got %v %v", got.Bindings, got.Roles)
+	}
+	if string(got.Extensions["x-owner"]) != `"overlay"` || string(got.Extensions["x-team"]) != `"core"` {
+		t.Fatalf("unexpected extensions %v", got.Extensions)
+	}
+
+	if !base.Equal(baseBefore) {
+		t.Fatal("merge modified base")
+	}
+	got.Operations["listItems"] = Operation{}
+	got.Extensions["x-team"] = json.RawMessage(`"changed"`)
+	if base.Operations["listItems"].Description != "Base list." || string(base.Extensions["x-team"]) != `"core"` {
+		t.Fatal("result shares memory with base")
+	}
+}
+
+func TestMerge_ConflictingVersions(t *testing.T) {
+	_, err := Merge(Interface{OpenBindings: "0.1.0"}, Interface{OpenBindings: "0.2.0"})
+	if err == nil {
+		t.Fatal("expected error for conflicting openbindings versions")
+	}
+	got, err := Merge(Interface{}, Interface{OpenBindings: "0.1.0"})
+	if err != nil || got.OpenBindings != "0.1.0" {
+		t.Fatalf("expected unset version to take the overlay's, got %q, %v", got.OpenBindings, err)
+	}
+}
+
+func TestOperationMerge(t *testing.T) {
+	var base, overlay Operation
+	mustUnmarshalJSON(t, []byte(`{
+  "description": "Base get.",
+  "tags": ["items", "read"],
+  "aliases": ["fetchItem"],
+  "satisfies": [{"role": "store", "operation": "getItem"}, {"role": "cache", "operation": "get"}],
+  "idempotent": true,
+  "input": {"type": "object"},
+  "output": {"type": "string"},
+  "examples": {"basic": {"input": {}}, "empty": {}},
+  "x-owner": "base",
+  "x-team": "core"
+}`), &base)
+	mustUnmarshalJSON(t, []byte(`{
+  "deprecated": true,
+  "tags": ["read", "v2", "v2"],
+  "aliases": ["readItem", "fetchItem"],
+  "satisfies": [{"role": "cache", "operation": "get", "x-note": "overlay"}, {"role": "store", "operation": "readItem"}],
+  "output": {"type": "integer"},
+  "examples": {"basic": {"input": {"id": 1}}},
+  "x-owner": "overlay"
+}`), &overlay)
+	baseBefore := base.Clone()
+
+	got, err := base.Merge(overlay)
+	if err != nil {
+		t.Fatalf("merge: %v", err)
+	}
+	if got.Description != "Base get." || !got.Deprecated || got.Idempotent == nil || !*got.Idempotent {
+		t.Fatalf("unexpected scalars: %+v", got)
+	}
+	if !reflect.DeepEqual(got.Tags, []string{"items", "read", "v2"}) || !reflect.DeepEqual(got.Aliases, []string{"fetchItem", "readItem"}) {
+		t.Fatalf("unexpected tags %v or aliases %v", got.Tags, got.Aliases)
+	}
+	pairs := make([]string, len(got.Satisfies))
+	for i, s := range got.Satisfies {
+		pairs[i] = s.Role + "/" + s.Operation
+	}
+	if !reflect.DeepEqual(pairs, []string{"store/getItem", "cache/get", "store/readItem"}) {
+		t.Fatalf("unexpected satisfies %v", pairs)
+	}
+	if string(got.Satisfies[1].Extensions["x-note"]) != `"overlay"` {
+		t.Fatalf("expected the overlay satisfies entry to win, got %+v", got.Satisfies[1])
+	}
+	if got.Input["type"] != "object" || got.Output["type"] != "integer" {
+		t.Fatalf("unexpected schemas %v %v", got.Input, got.Output)
+	}
+	if len(got.Examples) != 2 || got.Examples["basic"].Input == nil || len(got.Examples["basic"].Input.(map[string]any)) != 1 {
+		t.Fatalf("unexpected examples %+v", got.Examples)
+	}
+	if string(got.Extensions["x-owner"]) != `"overlay"` || string(got.Extensions["x-team"]) != `"core"` {
+		t.Fatalf("unexpected extensions %v", got.Extensions)
+	}
+
+	got.Tags[0] = "changed"
+	got.Input["type"] = "changed"
+	if !reflect.DeepEqual(base, baseBefore) {
+		t.Fatal("merge modified base or shares memory with it")
+	}
+
+	no := false
+	if _, err := base.Merge(Operation{Idempotent: &no}); err == nil {
+		t.Fatal("expected error for conflicting idempotent values")
+	}
+	empty, err := Operation{}.Merge(Operation{})
+	if err != nil || empty.Tags != nil || empty.Aliases != nil || empty.Satisfies != nil {
+		t.Fatalf("expected empty operations to merge to an empty one, got %+v, %v", empty, err)
+	}
+}
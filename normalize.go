@@ -0,0 +1,75 @@
+package openbindings
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/openbindings/openbindings-go/schemaprofile"
+)
+
+// NormalizeOption configures Interface.NormalizeSchemas.
+type NormalizeOption func(*normalizeOptions)
+
+type normalizeOptions struct {
+	rejectOutsideProfile bool
+}
+
+// WithRejectOutsideProfile makes NormalizeSchemas fail on a schema that uses
+// keywords outside the schema profile instead of leaving it unchanged.
+func WithRejectOutsideProfile() NormalizeOption {
+	return func(o *normalizeOptions) { o.rejectOutsideProfile = true }
+}
+
+// NormalizeSchemas returns a copy of i in which every schema (the entries of
+// Schemas and each operation's input and output) is replaced by its profile
+// normalization from n, so that Diff and Equal compare schemas by meaning rather
+// than spelling. Set n.Root to the decoded document so "#/schemas/..." references
+// resolve; they are inlined in the result. A schema outside the profile is kept
+// as is unless WithRejectOutsideProfile is given; other normalization errors are
+// always returned. i is not modified.
+func (i Interface) NormalizeSchemas(n *schemaprofile.Normalizer, opts ...NormalizeOption) (Interface, error) {
+	var o normalizeOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if n == nil {
+		return Interface{}, errors.New("openbindings: nil normalizer")
+	}
+
+	out := i.Clone()
+	normalize := func(l location, s JSONSchema) (JSONSchema, error) {
+		if s == nil {
+			return nil, nil
+		}
+		ns, err := n.Normalize(s)
+		if err != nil {
+			var ope *schemaprofile.OutsideProfileError
+			if errors.As(err, &ope) && !o.rejectOutsideProfile {
+				return s, nil
+			}
+			return nil, fmt.Errorf("openbindings: %s: %w", l.display, err)
+		}
+		return ns, nil
+	}
+
+	for _, k := range sortedKeys(out.Schemas) {
+		s, err := normalize(at("schemas", k), out.Schemas[k])
+		if err != nil {
+			return Interface{}, err
+		}
+		out.Schemas[k] = s
+	}
+	for _, k := range sortedKeys(out.Operations) {
+		op := out.Operations[k]
+		opAt := at("operations", k)
+		var err error
+		if op.Input, err = normalize(opAt.field("input"), op.Input); err != nil {
+			return Interface{}, err
+		}
+		if op.Output, err = normalize(opAt.field("output"), op.Output); err != nil {
+			return Interface{}, err
+		}
+		out.Operations[k] = op
+	}
+	return out, nil
+}
@@ -0,0 +1,64 @@
+package openbindings
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/openbindings/openbindings-go/schemaprofile"
+)
+
+func TestInterface_NormalizeSchemas_MakesEqualSchemaAware(t *testing.T) {
+	var a, b Interface
+	mustUnmarshalJSON(t, []byte(`{
+  "openbindings": "0.1.0",
+  "schemas": {"Id": {"type": "string", "description": "An id."}},
+  "operations": {"get": {"input": {"type": "object", "required": ["b", "a"], "properties": {"a": {"$ref": "#/schemas/Id"}}}}}
+}`), &a)
+	mustUnmarshalJSON(t, []byte(`{
+  "openbindings": "0.1.0",
+  "schemas": {"Id": {"type": ["string"]}},
+  "operations": {"get": {"input": {"type": "object", "required": ["a", "b"], "properties": {"a": {"type": "string"}}}}}
+}`), &b)
+	if a.Equal(b) {
+		t.Fatal("expected raw documents to differ")
+	}
+
+	na, err := a.NormalizeSchemas(&schemaprofile.Normalizer{Root: mustUnmarshalToMap(t, mustMarshalJSON(t, a))})
+	if err != nil {
+		t.Fatalf("normalize a: %v", err)
+	}
+	nb, err := b.NormalizeSchemas(&schemaprofile.Normalizer{Root: mustUnmarshalToMap(t, mustMarshalJSON(t, b))})
+	if err != nil {
+		t.Fatalf("normalize b: %v", err)
+	}
+	if !na.Equal(nb) {
+		t.Fatalf("expected normalized documents to be equal:\n%s\n%s", mustMarshalJSON(t, na), mustMarshalJSON(t, nb))
+	}
+	if _, ok := a.Schemas["Id"]["description"]; !ok {
+		t.Fatal("NormalizeSchemas modified its receiver")
+	}
+}
+
+func TestInterface_NormalizeSchemas_OutsideProfile(t *testing.T) {
+	i := Interface{
+		OpenBindings: "0.1.0",
+		Operations: map[string]Operation{
+			"op": {Input: JSONSchema{"type": "string", "contentEncoding": "base64"}},
+		},
+	}
+	n := &schemaprofile.Normalizer{Root: map[string]any{}}
+
+	out, err := i.NormalizeSchemas(n)
+	if err != nil {
+		t.Fatalf("normalize: %v", err)
+	}
+	if out.Operations["op"].Input["contentEncoding"] != "base64" {
+		t.Fatalf("expected out-of-profile schema to be kept, got %v", out.Operations["op"].Input)
+	}
+
+	_, err = i.NormalizeSchemas(n, WithRejectOutsideProfile())
+	var ope *schemaprofile.OutsideProfileError
+	if !errors.As(err, &ope) {
+		t.Fatalf("expected OutsideProfileError, got %v", err)
+	}
+}
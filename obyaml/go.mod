@@ -0,0 +1,8 @@
+module github.com/openbindings/openbindings-go/obyaml
+
+go 1.22
+
+require (
+	github.com/openbindings/openbindings-go v0.1.0
+	gopkg.in/yaml.v3 v3.0.1
+)
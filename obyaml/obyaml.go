@@ -0,0 +1,150 @@
+// Package obyaml reads and writes OpenBindings interface documents as YAML.
+//
+// YAML is converted to JSON and fed through the SDK's lossless JSON handling, so
+// extensions (x-*) and unknown fields are preserved exactly as they are for JSON
+// documents. Only the JSON-compatible subset of YAML is supported: mapping keys
+// are converted to strings, and values with no JSON representation (such as
+// NaN or infinities) are rejected.
+package obyaml
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+
+	"gopkg.in/yaml.v3"
+
+	openbindings "github.com/openbindings/openbindings-go"
+)
+
+// Unmarshal decodes a YAML document into iface.
+func Unmarshal(data []byte, iface *openbindings.Interface) error {
+	if iface == nil {
+		return openbindings.ErrNilInterface
+	}
+	b, err := ToJSON(data)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, iface)
+}
+
+// Marshal encodes iface as a YAML document. Field order follows the SDK's JSON
+// encoding, and numbers keep their JSON spelling.
+func Marshal(iface openbindings.Interface) ([]byte, error) {
+	b, err := json.Marshal(iface)
+	if err != nil {
+		return nil, err
+	}
+	return FromJSON(b)
+}
+
+// ToJSON converts a single YAML document to JSON bytes.
+func ToJSON(data []byte) ([]byte, error) {
+	var v any
+	if err := yaml.Unmarshal(data, &v); err != nil {
+		return nil, fmt.Errorf("obyaml: %w", err)
+	}
+	norm, err := normalize(v, "")
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(norm)
+}
+
+// FromJSON converts JSON bytes to a block-style YAML document, preserving key order.
+func FromJSON(data []byte) ([]byte, error) {
+	// JSON is valid YAML, so the YAML parser yields a node tree with the original
+	// key order and number spelling; only the flow/quoting styles need resetting.
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("obyaml: %w", err)
+	}
+	resetStyle(&doc)
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, fmt.Errorf("obyaml: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("obyaml: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// normalize converts YAML-decoded values into the shapes encoding/json produces,
+// so JSONSchema fields and lossless fields behave the same as for JSON input.
+func normalize(v any, path string) (any, error) {
+	switch x := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(x))
+		for k, item := range x {
+			nv, err := normalize(item, path+"/"+k)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = nv
+		}
+		return out, nil
+	case map[any]any:
+		out := make(map[string]any, len(x))
+		for k, item := range x {
+			key := fmt.Sprint(k)
+			if _, dup := out[key]; dup {
+				return nil, fmt.Errorf("obyaml: %s: duplicate key %q after string conversion", pathOrRoot(path), key)
+			}
+			nv, err := normalize(item, path+"/"+key)
+			if err != nil {
+				return nil, err
+			}
+			out[key] = nv
+		}
+		return out, nil
+	case []any:
+		out := make([]any, len(x))
+		for idx, item := range x {
+			nv, err := normalize(item, fmt.Sprintf("%s/%d", path, idx))
+			if err != nil {
+				return nil, err
+			}
+			out[idx] = nv
+		}
+		return out, nil
+	case float64:
+		if math.IsNaN(x) || math.IsInf(x, 0) {
+			return nil, fmt.Errorf("obyaml: %s: %w", pathOrRoot(path), errNonJSONNumber)
+		}
+		return x, nil
+	default:
+		return v, nil
+	}
+}
+
+var errNonJSONNumber = errors.New("number has no JSON representation")
+
+func resetStyle(n *yaml.Node) {
+	switch n.Kind {
+	case yaml.MappingNode, yaml.SequenceNode:
+		n.Style = 0
+	case yaml.ScalarNode:
+		// Double-quoted JSON strings become plain where YAML allows it; the encoder
+		// re-quotes strings that would otherwise resolve to a non-string tag.
+		if n.Style == yaml.DoubleQuotedStyle {
+			n.Style = 0
+		}
+	}
+	for _, c := range n.Content {
+		resetStyle(c)
+	}
+}
+
+func pathOrRoot(path string) string {
+	if path == "" {
+		return "<root>"
+	}
+	return path
+}
@@ -0,0 +1,111 @@
+package obyaml
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	openbindings "github.com/openbindings/openbindings-go"
+)
+
+const doc = `
+openbindings: 0.1.0
+name: demo
+x-team: platform
+unknownField:
+  value: unknownFieldValue
+operations:
+  getUser:
+    input:
+      type: object
+      properties:
+        id: {type: string}
+      maximum: 10
+    x-op: true
+sources:
+  api:
+    format: openapi@3.1
+    location: ./api.json
+bindings:
+  getUser.api:
+    operation: getUser
+    source: api
+`
+
+func TestUnmarshal_PreservesExtensionsAndUnknown(t *testing.T) {
+	var iface openbindings.Interface
+	if err := Unmarshal([]byte(doc), &iface); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if iface.OpenBindings != "0.1.0" || iface.Name != "demo" {
+		t.Fatalf("unexpected header: %q %q", iface.OpenBindings, iface.Name)
+	}
+	if err := iface.Validate(); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	props, ok := iface.Operations["getUser"].Input["properties"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected properties map, got %T", iface.Operations["getUser"].Input["properties"])
+	}
+	if _, ok := props["id"].(map[string]any); !ok {
+		t.Fatalf("expected nested schema map, got %T", props["id"])
+	}
+
+	out, err := json.Marshal(iface)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var m map[string]any
+	if err := json.Unmarshal(out, &m); err != nil {
+		t.Fatalf("unmarshal output: %v", err)
+	}
+	if m["x-team"] != "platform" {
+		t.Fatalf("expected x-team preserved, got %#v", m["x-team"])
+	}
+	if u, _ := m["unknownField"].(map[string]any); u["value"] != "unknownFieldValue" {
+		t.Fatalf("expected unknownField preserved, got %#v", m["unknownField"])
+	}
+	op, _ := m["operations"].(map[string]any)["getUser"].(map[string]any)
+	if op["x-op"] != true {
+		t.Fatalf("expected x-op preserved, got %#v", op["x-op"])
+	}
+}
+
+func TestUnmarshal_NonStringKeys(t *testing.T) {
+	var iface openbindings.Interface
+	in := "openbindings: 0.1.0\noperations:\n  op:\n    input:\n      enum: [1, 2]\n      x-codes: {200: ok}\n"
+	if err := Unmarshal([]byte(in), &iface); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	codes, ok := iface.Operations["op"].Input["x-codes"].(map[string]any)
+	if !ok || codes["200"] != "ok" {
+		t.Fatalf("expected string-keyed map, got %#v", iface.Operations["op"].Input["x-codes"])
+	}
+}
+
+func TestMarshal_RoundTrip(t *testing.T) {
+	var iface openbindings.Interface
+	if err := Unmarshal([]byte(doc), &iface); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	out, err := Marshal(iface)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	s := string(out)
+	for _, want := range []string{"openbindings: 0.1.0", "x-team: platform", "maximum: 10"} {
+		if !strings.Contains(s, want) {
+			t.Fatalf("expected YAML to contain %q, got:\n%s", want, s)
+		}
+	}
+	var again openbindings.Interface
+	if err := Unmarshal(out, &again); err != nil {
+		t.Fatalf("unmarshal round trip: %v", err)
+	}
+	before, _ := json.Marshal(iface)
+	after, _ := json.Marshal(again)
+	if !bytes.Equal(before, after) {
+		t.Fatalf("expected round trip to preserve document:\n got %s\nwant %s", after, before)
+	}
+}
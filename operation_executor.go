@@ -204,8 +204,13 @@ func (e *OperationExecutor) ExecuteOperation(ctx context.Context, in *OperationE
 		Options:     in.Options,
 		Interface:   in.Interface,
 	}
-	if source.Content != nil && source.Location == "" {
-		bindingIn.Source.Content = source.Content
+	if source.Location == "" {
+		// Sources decoded with WithRawSourceContent keep their content in ContentRaw.
+		content, err := source.DecodeContent()
+		if err != nil {
+			return nil, fmt.Errorf("openbindings: source %q content: %w", binding.Source, err)
+		}
+		bindingIn.Source.Content = content
 	}
 	if binding.Security != "" && in.Interface.Security != nil {
 		if methods, ok := in.Interface.Security[binding.Security]; ok {
@@ -317,13 +322,7 @@ func selectBinding(iface *Interface, opKey string, availableFormats map[string]b
 			}
 		}
 
-		// Binding priority overrides source priority.
-		bPri := math.MaxFloat64
-		if b.Priority != nil {
-			bPri = *b.Priority
-		} else if src, ok := iface.Sources[b.Source]; ok && src.Priority != nil {
-			bPri = *src.Priority
-		}
+		bPri := iface.bindingPriority(b)
 
 		betterDeprecation := bestDeprecated && !b.Deprecated
 		sameTier := b.Deprecated == bestDeprecated
@@ -381,12 +380,9 @@ func applyTransformRef(eval TransformEvaluator, transforms map[string]Transform,
 		return data, nil
 	}
 
-	t := tor.Resolve(transforms)
-	if t == nil {
-		if tor.IsRef() {
-			return nil, fmt.Errorf("%w: %q", ErrTransformRefNotFound, tor.Ref)
-		}
-		return nil, fmt.Errorf("openbindings: invalid transform: neither ref nor inline")
+	t, err := resolveTransform(tor, transforms)
+	if err != nil {
+		return nil, err
 	}
 
 	if t.Expression == "" {
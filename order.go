@@ -0,0 +1,114 @@
+package openbindings
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// OperationOrder returns the operation keys sorted, the order MarshalJSON writes
+// them in.
+func (i Interface) OperationOrder() []string {
+	return sortedKeys(i.Operations)
+}
+
+// OperationKeysInSourceOrder returns the operation keys in the order the document
+// listed them, when it was decoded by DecodeInterface with WithOperationOrder.
+// Recorded keys no longer in Operations are skipped and operations added since
+// follow, sorted; with no recorded order the result is OperationOrder.
+func (i Interface) OperationKeysInSourceOrder() []string {
+	keys := make([]string, 0, len(i.Operations))
+	seen := make(map[string]bool, len(i.Operations))
+	for _, k := range i.operationOrder {
+		if _, ok := i.Operations[k]; ok && !seen[k] {
+			keys = append(keys, k)
+			seen[k] = true
+		}
+	}
+	for _, k := range sortedKeys(i.Operations) {
+		if !seen[k] {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+// MarshalJSONInSourceOrder is like MarshalJSON but writes the operations in
+// OperationKeysInSourceOrder, so a document that is decoded, edited, and written
+// back keeps the order its authors chose and diffs only where it changed. The
+// output is not canonical: two documents equal under Equal may encode
+// differently, so use MarshalJSON for hashing and comparison.
+func (i Interface) MarshalJSONInSourceOrder() ([]byte, error) {
+	b, err := i.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	if len(i.operationOrder) == 0 || len(i.Operations) == 0 {
+		return b, nil
+	}
+	ops, err := marshalOperationsInOrder(i.Operations, i.OperationKeysInSourceOrder())
+	if err != nil {
+		return nil, err
+	}
+	return replaceMember(b, "operations", ops)
+}
+
+func marshalOperationsInOrder(ops map[string]Operation, keys []string) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for n, k := range keys {
+		if n > 0 {
+			buf.WriteByte(',')
+		}
+		kb, err := json.Marshal(k)
+		if err != nil {
+			return nil, err
+		}
+		vb, err := json.Marshal(ops[k])
+		if err != nil {
+			return nil, fmt.Errorf("operations %q: %w", k, err)
+		}
+		buf.Write(kb)
+		buf.WriteByte(':')
+		buf.Write(vb)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// replaceMember rewrites the JSON object obj with the value of member key
+// replaced by value, keeping every member in place.
+func replaceMember(obj []byte, key string, value []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(obj))
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for n := 0; dec.More(); n++ {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		var v json.RawMessage
+		if err := dec.Decode(&v); err != nil {
+			return nil, err
+		}
+		if n > 0 {
+			buf.WriteByte(',')
+		}
+		kb, err := json.Marshal(tok.(string))
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(kb)
+		buf.WriteByte(':')
+		if tok == key {
+			buf.Write(value)
+		} else {
+			buf.Write(v)
+		}
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,63 @@
+package openbindings
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestOperationKeysInSourceOrder(t *testing.T) {
+	doc := `{"openbindings":"0.1.0","operations":{"zeta":{},"alpha":{"description":"A"},"mid":{}},"x-team":"core"}`
+
+	plain, err := DecodeInterface(strings.NewReader(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sorted := []string{"alpha", "mid", "zeta"}
+	if got := plain.OperationOrder(); !reflect.DeepEqual(got, sorted) {
+		t.Fatalf("OperationOrder = %v", got)
+	}
+	if got := plain.OperationKeysInSourceOrder(); !reflect.DeepEqual(got, sorted) {
+		t.Fatalf("without WithOperationOrder, expected sorted keys, got %v", got)
+	}
+
+	iface, err := DecodeInterface(strings.NewReader(doc), WithOperationOrder())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := iface.OperationKeysInSourceOrder(), []string{"zeta", "alpha", "mid"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("OperationKeysInSourceOrder = %v, want %v", got, want)
+	}
+	if !iface.Equal(*plain) {
+		t.Fatal("recording the order should not affect Equal")
+	}
+
+	b, err := iface.MarshalJSONInSourceOrder()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"openbindings":"0.1.0","operations":{"zeta":{},"alpha":{"description":"A"},"mid":{}},"x-team":"core"}`
+	if string(b) != want {
+		t.Fatalf("MarshalJSONInSourceOrder:\n got %s\nwant %s", b, want)
+	}
+	canonical, err := json.Marshal(iface)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(canonical), `{"alpha":{"description":"A"},"mid":{},"zeta":{}}`) {
+		t.Fatalf("MarshalJSON should keep sorted operations, got %s", canonical)
+	}
+
+	// Edits: removed keys drop out, new ones follow sorted, and clones keep the order.
+	edited := iface.Clone()
+	delete(edited.Operations, "alpha")
+	edited.Operations["beta"] = Operation{}
+	edited.Operations["aaa"] = Operation{}
+	if got, want := edited.OperationKeysInSourceOrder(), []string{"zeta", "mid", "aaa", "beta"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("after edits, OperationKeysInSourceOrder = %v, want %v", got, want)
+	}
+	if got, want := iface.OperationKeysInSourceOrder(), []string{"zeta", "alpha", "mid"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("editing a clone changed the original order: %v", got)
+	}
+}
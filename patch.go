@@ -0,0 +1,177 @@
+package openbindings
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/openbindings/openbindings-go/canonicaljson"
+)
+
+// ApplyPatch applies an RFC 6902 JSON Patch document to i's JSON form and
+// decodes the result back into a lossless Interface, so extensions and unknown
+// fields survive. The operations add, remove, replace, move, copy, and test are
+// applied in order and atomically: on error i is returned unchanged, and the error
+// names the failing operation's index and path.
+func ApplyPatch(i Interface, patch []byte) (Interface, error) {
+	var ops []map[string]json.RawMessage
+	if err := json.Unmarshal(patch, &ops); err != nil {
+		return i, fmt.Errorf("openbindings: patch: %w", err)
+	}
+	doc, err := i.jsonValue()
+	if err != nil {
+		return i, err
+	}
+	for idx, raw := range ops {
+		var op patchOp
+		if err := op.parse(raw); err != nil {
+			return i, fmt.Errorf("openbindings: patch op %d: %w", idx, err)
+		}
+		if doc, err = op.apply(doc); err != nil {
+			return i, fmt.Errorf("openbindings: patch op %d (%s %q): %w", idx, op.op, op.path, err)
+		}
+	}
+	out, err := interfaceFromJSONValue(doc)
+	if err != nil {
+		return i, fmt.Errorf("openbindings: patch result: %w", err)
+	}
+	return out, nil
+}
+
+// patchOp is one decoded JSON Patch operation.
+type patchOp struct {
+	op, path, from string
+	value          any
+}
+
+func (p *patchOp) parse(raw map[string]json.RawMessage) error {
+	str := func(member string) (string, error) {
+		v, ok := raw[member]
+		if !ok {
+			return "", fmt.Errorf("missing %q", member)
+		}
+		var s string
+		if err := json.Unmarshal(v, &s); err != nil {
+			return "", fmt.Errorf("%q must be a string", member)
+		}
+		return s, nil
+	}
+
+	var err error
+	if p.op, err = str("op"); err != nil {
+		return err
+	}
+	if p.path, err = str("path"); err != nil {
+		return err
+	}
+	switch p.op {
+	case "add", "replace", "test":
+		v, ok := raw["value"]
+		if !ok {
+			return fmt.Errorf("%s: missing \"value\"", p.op)
+		}
+		return canonicaljson.Unmarshal(v, &p.value)
+	case "move", "copy":
+		p.from, err = str("from")
+		return err
+	case "remove":
+		return nil
+	}
+	return fmt.Errorf("unknown op %q", p.op)
+}
+
+func (p *patchOp) apply(doc any) (any, error) {
+	path, err := parsePointer(p.path)
+	if err != nil {
+		return nil, err
+	}
+	switch p.op {
+	case "add":
+		return pointerSet(doc, path, p.value, true)
+	case "remove":
+		doc, _, err := pointerRemove(doc, path)
+		return doc, err
+	case "replace":
+		if _, err := pointerGet(doc, path); err != nil {
+			return nil, err
+		}
+		return pointerSet(doc, path, p.value, false)
+	case "test":
+		v, err := pointerGet(doc, path)
+		if err != nil {
+			return nil, err
+		}
+		if same, err := canonicalEqual(v, p.value); err != nil || !same {
+			return nil, errors.New("test failed: value differs")
+		}
+		return doc, nil
+	}
+
+	// move and copy.
+	from, err := parsePointer(p.from)
+	if err != nil {
+		return nil, err
+	}
+	if p.op == "copy" {
+		v, err := pointerGet(doc, from)
+		if err != nil {
+			return nil, fmt.Errorf("from: %w", err)
+		}
+		return pointerSet(doc, path, cloneJSONValue(v), true)
+	}
+	if p.path == p.from {
+		return doc, nil
+	}
+	if strings.HasPrefix(p.path, p.from+"/") {
+		return nil, errors.New("cannot move a value into one of its children")
+	}
+	doc, v, err := pointerRemove(doc, from)
+	if err != nil {
+		return nil, fmt.Errorf("from: %w", err)
+	}
+	return pointerSet(doc, path, v, true)
+}
+
+// pointerRemove removes the value at toks within doc and returns the updated doc
+// and the removed value. The whole document cannot be removed.
+func pointerRemove(doc any, toks []string) (any, any, error) {
+	if len(toks) == 0 {
+		return nil, nil, errors.New("cannot remove the whole document")
+	}
+	tok, last := toks[0], len(toks) == 1
+	switch x := doc.(type) {
+	case map[string]any:
+		child, ok := x[tok]
+		if !ok {
+			return nil, nil, fmt.Errorf("member %q not found", tok)
+		}
+		if last {
+			delete(x, tok)
+			return x, child, nil
+		}
+		child, removed, err := pointerRemove(child, toks[1:])
+		if err != nil {
+			return nil, nil, err
+		}
+		x[tok] = child
+		return x, removed, nil
+	case []any:
+		idx, err := arrayIndex(tok, len(x), false)
+		if err != nil {
+			return nil, nil, err
+		}
+		if last {
+			removed := x[idx]
+			return append(x[:idx], x[idx+1:]...), removed, nil
+		}
+		child, removed, err := pointerRemove(x[idx], toks[1:])
+		if err != nil {
+			return nil, nil, err
+		}
+		x[idx] = child
+		return x, removed, nil
+	default:
+		return nil, nil, fmt.Errorf("cannot descend into %s at %q", jsonKind(doc), tok)
+	}
+}
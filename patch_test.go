@@ -0,0 +1,58 @@
+package openbindings
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestApplyPatch(t *testing.T) {
+	var i Interface
+	mustUnmarshalJSON(t, []byte(`{
+  "openbindings": "0.1.0",
+  "x-owner": "core",
+  "operations": {
+    "getUser": {"description": "Get a user.", "tags": ["users"]},
+    "old": {"description": "Old."}
+  }
+}`), &i)
+
+	out, err := ApplyPatch(i, []byte(`[
+  {"op": "test", "path": "/openbindings", "value": "0.1.0"},
+  {"op": "replace", "path": "/operations/getUser/description", "value": "Fetch a user."},
+  {"op": "add", "path": "/operations/getUser/tags/0", "value": "public"},
+  {"op": "move", "from": "/operations/old", "path": "/operations/legacy"},
+  {"op": "copy", "from": "/operations/getUser/tags", "path": "/operations/legacy/tags"},
+  {"op": "remove", "path": "/operations/legacy/description"},
+  {"op": "add", "path": "/operations/getUser/x-rate", "value": 10}
+]`))
+	if err != nil {
+		t.Fatalf("ApplyPatch: %v", err)
+	}
+	got := string(mustMarshalJSON(t, out))
+	want := `{"openbindings":"0.1.0","operations":{"getUser":{"description":"Fetch a user.","tags":["public","users"],"x-rate":10},"legacy":{"tags":["public","users"]}},"x-owner":"core"}`
+	if got != want {
+		t.Fatalf("got  %s\nwant %s", got, want)
+	}
+	if i.Operations["getUser"].Description != "Get a user." {
+		t.Fatal("ApplyPatch modified its input")
+	}
+
+	for patch, wantErr := range map[string]string{
+		`[{"op": "test", "path": "/openbindings", "value": "0.2.0"}]`:                       `patch op 0 (test "/openbindings"): test failed`,
+		`[{"op": "add", "path": "/name", "value": "x"}, {"op": "remove", "path": "/nope"}]`: `patch op 1 (remove "/nope"): member "nope" not found`,
+		`[{"op": "replace", "path": "/operations/missing", "value": {}}]`:                   `patch op 0 (replace "/operations/missing")`,
+		`[{"op": "move", "from": "/operations", "path": "/operations/x"}]`:                  `cannot move a value into one of its children`,
+		`[{"op": "frobnicate", "path": ""}]`:                                                `patch op 0: unknown op "frobnicate"`,
+		`[{"op": "add", "path": "/name"}]`:                                                  `patch op 0: add: missing "value"`,
+		`[{"op": "replace", "path": "/operations", "value": 5}]`:                            `patch result`,
+		`{"op": "add"}`: `openbindings: patch:`,
+	} {
+		res, err := ApplyPatch(i, []byte(patch))
+		if err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Fatalf("%s: err = %v, want %q", patch, err, wantErr)
+		}
+		if !res.Equal(i) {
+			t.Fatalf("%s: expected the input back on error", patch)
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package openbindings
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/openbindings/openbindings-go/canonicaljson"
+)
+
+// GetPointer returns the value at the RFC 6901 JSON Pointer ptr in i's JSON form,
+// e.g. "/operations/getUser/description" ("" is the whole document). Objects and
+// arrays are returned as map[string]any and []any, and numbers as json.Number so
+// they keep their exact value.
+func (i Interface) GetPointer(ptr string) (any, error) {
+	toks, err := parsePointer(ptr)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := i.jsonValue()
+	if err != nil {
+		return nil, err
+	}
+	v, err := pointerGet(doc, toks)
+	if err != nil {
+		return nil, fmt.Errorf("openbindings: pointer %q: %w", ptr, err)
+	}
+	return v, nil
+}
+
+// SetPointer sets the value at ptr in i's JSON form to v's JSON encoding and
+// decodes the result back into i, so extensions and unknown fields survive. An
+// object member is added if absent; an array element is replaced, or appended
+// with the index "-". The parent must exist. i is unchanged on error, including
+// when the result is not a valid interface document.
+func (i *Interface) SetPointer(ptr string, v any) error {
+	toks, err := parsePointer(ptr)
+	if err != nil {
+		return err
+	}
+	value, err := toJSONValue(v)
+	if err != nil {
+		return fmt.Errorf("openbindings: pointer %q: %w", ptr, err)
+	}
+	doc, err := i.jsonValue()
+	if err != nil {
+		return err
+	}
+	if doc, err = pointerSet(doc, toks, value, false); err != nil {
+		return fmt.Errorf("openbindings: pointer %q: %w", ptr, err)
+	}
+	out, err := interfaceFromJSONValue(doc)
+	if err != nil {
+		return err
+	}
+	*i = out
+	return nil
+}
+
+// jsonValue returns i decoded as generic JSON, with numbers as json.Number.
+func (i Interface) jsonValue() (any, error) {
+	return toJSONValue(i)
+}
+
+func toJSONValue(v any) (any, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var out any
+	if err := canonicaljson.Unmarshal(b, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func interfaceFromJSONValue(doc any) (Interface, error) {
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return Interface{}, err
+	}
+	var out Interface
+	if err := json.Unmarshal(b, &out); err != nil {
+		return Interface{}, err
+	}
+	return out, nil
+}
+
+// parsePointer splits an RFC 6901 JSON Pointer into unescaped reference tokens.
+func parsePointer(ptr string) ([]string, error) {
+	if ptr == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(ptr, "/") {
+		return nil, fmt.Errorf("openbindings: invalid JSON Pointer %q: must be empty or start with \"/\"", ptr)
+	}
+	toks := strings.Split(ptr[1:], "/")
+	for idx, tok := range toks {
+		toks[idx] = unescapePointerToken(tok)
+	}
+	return toks, nil
+}
+
+// pointerGet returns the value at toks within doc.
+func pointerGet(doc any, toks []string) (any, error) {
+	cur := doc
+	for _, tok := range toks {
+		switch x := cur.(type) {
+		case map[string]any:
+			next, ok := x[tok]
+			if !ok {
+				return nil, fmt.Errorf("member %q not found", tok)
+			}
+			cur = next
+		case []any:
+			idx, err := arrayIndex(tok, len(x), false)
+			if err != nil {
+				return nil, err
+			}
+			cur = x[idx]
+		default:
+			return nil, fmt.Errorf("cannot descend into %s at %q", jsonKind(cur), tok)
+		}
+	}
+	return cur, nil
+}
+
+// pointerSet stores v at toks within doc and returns the updated doc. With insert
+// (JSON Patch "add"), an array index inserts before the element there instead of
+// replacing it. Either way "-" appends.
+func pointerSet(doc any, toks []string, v any, insert bool) (any, error) {
+	if len(toks) == 0 {
+		return v, nil
+	}
+	tok, last := toks[0], len(toks) == 1
+	switch x := doc.(type) {
+	case map[string]any:
+		if last {
+			x[tok] = v
+			return x, nil
+		}
+		child, ok := x[tok]
+		if !ok {
+			return nil, fmt.Errorf("member %q not found", tok)
+		}
+		child, err := pointerSet(child, toks[1:], v, insert)
+		if err != nil {
+			return nil, err
+		}
+		x[tok] = child
+		return x, nil
+	case []any:
+		idx, err := arrayIndex(tok, len(x), last)
+		if err != nil {
+			return nil, err
+		}
+		if !last {
+			child, err := pointerSet(x[idx], toks[1:], v, insert)
+			if err != nil {
+				return nil, err
+			}
+			x[idx] = child
+			return x, nil
+		}
+		if idx == len(x) {
+			return append(x, v), nil
+		}
+		if !insert {
+			x[idx] = v
+			return x, nil
+		}
+		x = append(x, nil)
+		copy(x[idx+1:], x[idx:])
+		x[idx] = v
+		return x, nil
+	default:
+		return nil, fmt.Errorf("cannot descend into %s at %q", jsonKind(doc), tok)
+	}
+}
+
+// arrayIndex parses an array reference token for an array of length n. "-", the
+// position past the end, is only accepted with allowEnd.
+func arrayIndex(tok string, n int, allowEnd bool) (int, error) {
+	if tok == "-" {
+		if !allowEnd {
+			return 0, errors.New(`index "-" is past the end of the array`)
+		}
+		return n, nil
+	}
+	// RFC 6901 indexes are decimal without leading zeros or a sign.
+	idx, err := strconv.Atoi(tok)
+	if err != nil || idx < 0 || (len(tok) > 1 && tok[0] == '0') || tok[0] == '+' {
+		return 0, fmt.Errorf("invalid array index %q", tok)
+	}
+	if idx >= n && !(allowEnd && idx == n) {
+		return 0, fmt.Errorf("array index %d out of range", idx)
+	}
+	return idx, nil
+}
+
+func jsonKind(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "a string"
+	case bool:
+		return "a boolean"
+	case json.Number, float64:
+		return "a number"
+	}
+	return "a value"
+}
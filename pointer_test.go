@@ -0,0 +1,84 @@
+package openbindings
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestInterface_GetPointer(t *testing.T) {
+	var i Interface
+	mustUnmarshalJSON(t, []byte(`{
+  "openbindings": "0.1.0",
+  "operations": {"get/user": {"description": "Get a user.", "tags": ["a", "b"], "x-limit": 9007199254740993}}
+}`), &i)
+
+	cases := []struct {
+		ptr  string
+		want string
+	}{
+		{ptr: "/openbindings", want: `"0.1.0"`},
+		{ptr: "/operations/get~1user/description", want: `"Get a user."`},
+		{ptr: "/operations/get~1user/tags/1", want: `"b"`},
+		{ptr: "/operations/get~1user/x-limit", want: `9007199254740993`},
+	}
+	for _, tc := range cases {
+		v, err := i.GetPointer(tc.ptr)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.ptr, err)
+		}
+		if got := string(mustMarshalJSON(t, v)); got != tc.want {
+			t.Fatalf("%s: got %s, want %s", tc.ptr, got, tc.want)
+		}
+	}
+
+	for _, ptr := range []string{"operations", "/missing", "/operations/get~1user/tags/2", "/operations/get~1user/tags/-", "/operations/get~1user/tags/01", "/openbindings/x"} {
+		if _, err := i.GetPointer(ptr); err == nil {
+			t.Fatalf("%s: expected error", ptr)
+		}
+	}
+}
+
+func TestInterface_SetPointer(t *testing.T) {
+	var i Interface
+	mustUnmarshalJSON(t, []byte(`{
+  "openbindings": "0.1.0",
+  "operations": {"op": {"tags": ["a"], "x-keep": {"n": 1}}}
+}`), &i)
+
+	if err := i.SetPointer("/operations/op/description", "Does things."); err != nil {
+		t.Fatalf("SetPointer: %v", err)
+	}
+	if err := i.SetPointer("/operations/op/tags/-", "b"); err != nil {
+		t.Fatalf("SetPointer append: %v", err)
+	}
+	if err := i.SetPointer("/operations/new", Operation{Description: "New."}); err != nil {
+		t.Fatalf("SetPointer typed value: %v", err)
+	}
+	op := i.Operations["op"]
+	if op.Description != "Does things." || strings.Join(op.Tags, ",") != "a,b" {
+		t.Fatalf("unexpected operation: %+v", op)
+	}
+	if string(op.Extensions["x-keep"]) != `{"n":1}` {
+		t.Fatalf("expected extension to survive, got %s", op.Extensions["x-keep"])
+	}
+	if i.Operations["new"].Description != "New." {
+		t.Fatalf("expected new operation, got %+v", i.Operations)
+	}
+
+	before := mustMarshalJSON(t, i)
+	for _, tc := range []struct {
+		ptr string
+		v   any
+	}{
+		{ptr: "/missing/child", v: 1},
+		{ptr: "/operations", v: "not an object"},
+		{ptr: "x", v: 1},
+	} {
+		if err := i.SetPointer(tc.ptr, tc.v); err == nil {
+			t.Fatalf("%s: expected error", tc.ptr)
+		}
+	}
+	if after := mustMarshalJSON(t, i); string(after) != string(before) {
+		t.Fatalf("failed SetPointer modified the interface:\n%s\n%s", before, after)
+	}
+}
@@ -0,0 +1,145 @@
+package openbindings
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// Problem codes identify the kind of a validation Problem. Codes are stable
+// across releases; messages are not.
+const (
+	ProblemRequired             = "required"
+	ProblemInvalidValue         = "invalid_value"
+	ProblemUnsupportedVersion   = "unsupported_version"
+	ProblemLimitExceeded        = "limit_exceeded"
+	ProblemInvalidAlias         = "invalid_alias"
+	ProblemAliasConflict        = "alias_conflict"
+	ProblemUnknownRole          = "unknown_role"
+	ProblemUnresolvedRole       = "unresolved_role"
+	ProblemUnknownRoleOperation = "unknown_role_operation"
+	ProblemUnknownField         = "unknown_field"
+	ProblemInvalidFormat        = "invalid_format"
+	ProblemInvalidSource        = "invalid_source"
+	ProblemFormatMismatch       = "format_mismatch"
+	ProblemInvalidTransform     = "invalid_transform"
+	ProblemExpressionParse      = "expression_parse_error"
+	ProblemUnknownOperation     = "unknown_operation"
+	ProblemUnknownSource        = "unknown_source"
+	ProblemUnknownSecurity      = "unknown_security"
+	ProblemInvalidTransformRef  = "invalid_transform_ref"
+	ProblemAmbiguousBinding     = "ambiguous_binding"
+	ProblemUnknownSchema        = "unknown_schema"
+	ProblemUnresolvedRef        = "unresolved_ref"
+	ProblemRefCycle             = "ref_cycle"
+	ProblemUnboundOperation     = "unbound_operation"
+	ProblemExampleMismatch      = "example_mismatch"
+	ProblemBindingKeyConvention = "binding_key_convention"
+
+	// Advisory codes, reported as warnings by Interface.Check.
+	ProblemMissingDescription  = "missing_description"
+	ProblemUnknownFormat       = "unknown_format"
+	ProblemDeprecatedOperation = "deprecated_operation"
+	ProblemPartialVersion      = "partial_version"
+	ProblemUnused              = "unused"
+)
+
+// Problem is the structured form of one validation problem, for tools that map
+// problems back to document locations (editors, CI annotations).
+type Problem struct {
+	// Pointer is the RFC 6901 JSON Pointer of the offending value
+	// (e.g. "/bindings/op.api/source"); "" refers to the whole document.
+	Pointer string `json:"pointer"`
+	// Code is one of the Problem* constants.
+	Code string `json:"code"`
+	// Message describes the problem without its location.
+	Message string `json:"message"`
+}
+
+// location addresses a value in the document in both notations used by
+// validation: the display form of ValidationError.Problems and a JSON Pointer.
+type location struct {
+	display string
+	pointer string
+}
+
+// at returns the location of the top-level field name, or of its entry key when
+// keys are given (e.g. at("bindings", "op.api")).
+func at(field string, keys ...string) location {
+	l := location{display: field, pointer: "/" + escapePointerToken(field)}
+	for _, k := range keys {
+		l = l.key(k)
+	}
+	return l
+}
+
+// field addresses a named member: .name in display form.
+func (l location) field(name string) location {
+	return location{display: l.display + "." + name, pointer: l.pointer + "/" + escapePointerToken(name)}
+}
+
+// key addresses a user-chosen map key: ["name"] in display form.
+func (l location) key(k string) location {
+	return location{display: l.display + fmt.Sprintf("[%q]", k), pointer: l.pointer + "/" + escapePointerToken(k)}
+}
+
+// index addresses an array element: [n] in display form.
+func (l location) index(n int) location {
+	return location{display: l.display + fmt.Sprintf("[%d]", n), pointer: l.pointer + "/" + strconv.Itoa(n)}
+}
+
+// problemList collects validation problems in both forms, index for index.
+type problemList struct {
+	problems   []string
+	structured []Problem
+}
+
+// add records a problem at l. Problems at the document root (the zero location)
+// are displayed without a location prefix.
+func (p *problemList) add(l location, code, format string, args ...any) {
+	msg := fmt.Sprintf(format, args...)
+	display := msg
+	if l.display != "" {
+		display = l.display + ": " + msg
+	}
+	p.problems = append(p.problems, display)
+	p.structured = append(p.structured, Problem{Pointer: l.pointer, Code: code, Message: msg})
+}
+
+// sortUnique sorts the problems by their display form and drops repeated ones,
+// keeping the two forms aligned, so output does not depend on traversal order.
+func (p *problemList) sortUnique() {
+	idx := make([]int, len(p.problems))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(a, b int) bool { return p.problems[idx[a]] < p.problems[idx[b]] })
+	var problems []string
+	var structured []Problem
+	for n, i := range idx {
+		if n > 0 && p.problems[i] == p.problems[idx[n-1]] {
+			continue
+		}
+		problems = append(problems, p.problems[i])
+		structured = append(structured, p.structured[i])
+	}
+	p.problems, p.structured = problems, structured
+}
+
+// truncate keeps the first max problems and records how many were dropped as a
+// final problem. max <= 0 keeps them all.
+func (p *problemList) truncate(max int) {
+	if max <= 0 || len(p.problems) <= max {
+		return
+	}
+	omitted := len(p.problems) - max
+	p.problems, p.structured = p.problems[:max], p.structured[:max]
+	p.add(location{}, ProblemLimitExceeded, "... %d more problems omitted", omitted)
+}
+
+func (p *problemList) err() error {
+	if len(p.problems) == 0 {
+		return nil
+	}
+	return &ValidationError{Problems: p.problems, Structured: p.structured}
+}
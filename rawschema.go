@@ -0,0 +1,39 @@
+package openbindings
+
+import "encoding/json"
+
+// RawSchema holds a JSON Schema as raw JSON. Unlike JSONSchema it preserves boolean
+// schemas (true accepts every value, false none), which JSON Schema 2020-12 allows
+// anywhere a schema is expected, and it keeps the original bytes.
+type RawSchema json.RawMessage
+
+// MarshalJSON returns s as is; an empty RawSchema encodes as null.
+func (s RawSchema) MarshalJSON() ([]byte, error) {
+	if len(s) == 0 {
+		return []byte("null"), nil
+	}
+	return s, nil
+}
+
+// UnmarshalJSON stores a copy of data.
+func (s *RawSchema) UnmarshalJSON(data []byte) error {
+	*s = append((*s)[:0], data...)
+	return nil
+}
+
+// AsObject decodes s as an object schema. ok is false if s is not a JSON object.
+func (s RawSchema) AsObject() (map[string]any, bool) {
+	var m map[string]any
+	if err := json.Unmarshal(s, &m); err != nil || m == nil {
+		return nil, false
+	}
+	return m, true
+}
+
+// IsBool reports whether s is a boolean schema and, if so, its value.
+func (s RawSchema) IsBool() (value bool, ok bool) {
+	if err := json.Unmarshal(s, &value); err != nil {
+		return false, false
+	}
+	return value, true
+}
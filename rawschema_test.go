@@ -0,0 +1,38 @@
+package openbindings
+
+import "testing"
+
+func TestRawSchema_RoundTripsBooleanAndObjectSchemas(t *testing.T) {
+	var doc struct {
+		Input  RawSchema `json:"input"`
+		Output RawSchema `json:"output"`
+		Absent RawSchema `json:"absent"`
+	}
+	in := `{"input":true,"output":{"type":"object","required":["id"]},"absent":null}`
+	mustUnmarshalJSON(t, []byte(in), &doc)
+	if got := string(mustMarshalJSON(t, doc)); got != in {
+		t.Fatalf("expected lossless round trip:\n got: %s\nwant: %s", got, in)
+	}
+
+	if v, ok := doc.Input.IsBool(); !ok || !v {
+		t.Fatalf("expected boolean schema true, got %v, %v", v, ok)
+	}
+	if _, ok := doc.Input.AsObject(); ok {
+		t.Fatal("boolean schema must not decode as an object")
+	}
+	obj, ok := doc.Output.AsObject()
+	if !ok || obj["type"] != "object" {
+		t.Fatalf("expected object schema, got %v, %v", obj, ok)
+	}
+	if _, ok := doc.Output.IsBool(); ok {
+		t.Fatal("object schema must not report as boolean")
+	}
+	if _, ok := doc.Absent.AsObject(); ok {
+		t.Fatal("null must not decode as an object")
+	}
+
+	var empty RawSchema
+	if got := string(mustMarshalJSON(t, empty)); got != "null" {
+		t.Fatalf("expected empty RawSchema to encode as null, got %s", got)
+	}
+}
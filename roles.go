@@ -0,0 +1,98 @@
+package openbindings
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// ResolveRoles loads the interface behind every role of i, and recursively the
+// roles of those interfaces. load receives each role reference; a relative
+// reference in a loaded interface is first resolved against the reference that
+// interface was loaded from. Each document is loaded once.
+//
+// The result is keyed by alias path: i's own aliases as written, and the roles of
+// a role interface as "alias/nested" (each alias escaped as a JSON Pointer token).
+// A role that leads back to an interface on its own path is a cycle and an error;
+// errors name the alias path at which they occur.
+func ResolveRoles(i Interface, load func(ref string) ([]byte, error)) (map[string]Interface, error) {
+	if load == nil {
+		return nil, errors.New("openbindings: nil role loader")
+	}
+	r := roleResolver{load: load, loaded: map[string]Interface{}, out: map[string]Interface{}}
+	if err := r.resolve(i, "", "", map[string]bool{}); err != nil {
+		return nil, err
+	}
+	return r.out, nil
+}
+
+type roleResolver struct {
+	load   func(ref string) ([]byte, error)
+	loaded map[string]Interface
+	out    map[string]Interface
+}
+
+// resolve loads the roles of iface, which was loaded from ref ("" for the root) and
+// sits at alias path prefix. active holds the references on the current path.
+func (r *roleResolver) resolve(iface Interface, ref, prefix string, active map[string]bool) error {
+	for _, alias := range sortedKeys(iface.Roles) {
+		aliasPath := escapePointerToken(alias)
+		if prefix != "" {
+			aliasPath = prefix + "/" + aliasPath
+		}
+		target := resolveRoleRef(ref, iface.Roles[alias])
+		if active[target] {
+			return fmt.Errorf("openbindings: role %q: cycle through %q", aliasPath, target)
+		}
+
+		role, ok := r.loaded[target]
+		if !ok {
+			b, err := r.load(target)
+			if err != nil {
+				return fmt.Errorf("openbindings: role %q: %w", aliasPath, err)
+			}
+			if err := json.Unmarshal(b, &role); err != nil {
+				return fmt.Errorf("openbindings: role %q: %w", aliasPath, err)
+			}
+			r.loaded[target] = role
+		}
+		r.out[aliasPath] = role
+
+		active[target] = true
+		err := r.resolve(role, target, aliasPath, active)
+		delete(active, target)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolveRoleRef resolves ref against the reference of the interface declaring it.
+// References that do not parse as URLs are used as written.
+func resolveRoleRef(base, ref string) string {
+	if base == "" {
+		return ref
+	}
+	b, err := url.Parse(base)
+	if err != nil {
+		return ref
+	}
+	u, err := url.Parse(ref)
+	if err != nil || u.IsAbs() {
+		return ref
+	}
+	if b.IsAbs() {
+		return b.ResolveReference(u).String()
+	}
+	// A relative base, such as a file path: ResolveReference would root the
+	// result, so join the paths instead.
+	if u.Path == "" || strings.HasPrefix(u.Path, "/") {
+		return ref
+	}
+	u.Path = path.Join(path.Dir(b.Path), u.Path)
+	return u.String()
+}
@@ -0,0 +1,81 @@
+package openbindings
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestResolveRoles_Recursive(t *testing.T) {
+	docs := map[string]string{
+		"https://example.com/roles/a.json": `{"openbindings": "0.1.0", "name": "A", "roles": {"b": "./b.json"}}`,
+		"https://example.com/roles/b.json": `{"openbindings": "0.1.0", "name": "B"}`,
+	}
+	var loads []string
+	load := func(ref string) ([]byte, error) {
+		loads = append(loads, ref)
+		d, ok := docs[ref]
+		if !ok {
+			return nil, errors.New("not found")
+		}
+		return []byte(d), nil
+	}
+
+	iface := Interface{OpenBindings: "0.1.0", Roles: map[string]string{
+		"a":    "https://example.com/roles/a.json",
+		"also": "https://example.com/roles/b.json",
+	}}
+	got, err := ResolveRoles(iface, load)
+	if err != nil {
+		t.Fatalf("ResolveRoles: %v", err)
+	}
+	var keys []string
+	for k := range got {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	if want := []string{"a", "a/b", "also"}; !reflect.DeepEqual(keys, want) {
+		t.Fatalf("keys = %v, want %v", keys, want)
+	}
+	if got["a"].Name != "A" || got["a/b"].Name != "B" || got["also"].Name != "B" {
+		t.Fatalf("unexpected names: %q %q %q", got["a"].Name, got["a/b"].Name, got["also"].Name)
+	}
+	if len(loads) != 2 {
+		t.Fatalf("expected each document to load once, got %v", loads)
+	}
+}
+
+func TestResolveRoles_Errors(t *testing.T) {
+	docs := map[string]string{
+		"a.json": `{"openbindings": "0.1.0", "roles": {"back": "b.json"}}`,
+		"b.json": `{"openbindings": "0.1.0", "roles": {"loop": "a.json"}}`,
+		"bad":    `{"openbindings": 1}`,
+	}
+	load := func(ref string) ([]byte, error) {
+		d, ok := docs[ref]
+		if !ok {
+			return nil, errors.New("not found")
+		}
+		return []byte(d), nil
+	}
+
+	cases := []struct {
+		name  string
+		roles map[string]string
+		want  string
+	}{
+		{name: "cycle", roles: map[string]string{"a": "a.json"}, want: `role "a/back/loop": cycle through "a.json"`},
+		{name: "missing", roles: map[string]string{"gone": "missing.json"}, want: `role "gone": not found`},
+		{name: "malformed", roles: map[string]string{"x": "bad"}, want: `role "x":`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ResolveRoles(Interface{OpenBindings: "0.1.0", Roles: tc.roles}, load)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("err = %v, want %q", err, tc.want)
+			}
+		})
+	}
+}
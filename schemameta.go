@@ -0,0 +1,101 @@
+package openbindings
+
+import "strings"
+
+// schemaMetaKeywords identify a schema resource and its dialect rather than
+// constrain values. See StripSchemaMeta.
+var schemaMetaKeywords = map[string]struct{}{
+	"$schema":     {},
+	"$vocabulary": {},
+	"$id":         {},
+	"$anchor":     {},
+}
+
+// schemaValueKeywords hold instance values rather than subschemas, as do the
+// values of x- extensions.
+var schemaValueKeywords = map[string]struct{}{
+	"const":    {},
+	"enum":     {},
+	"default":  {},
+	"examples": {},
+}
+
+// schemaMapKeywords hold objects whose keys are names (of properties, patterns,
+// definitions) and whose values are schemas.
+var schemaMapKeywords = map[string]struct{}{
+	"properties":        {},
+	"patternProperties": {},
+	"dependentSchemas":  {},
+	"$defs":             {},
+	"definitions":       {},
+}
+
+// StripSchemaMeta returns a copy of schema without the $schema, $vocabulary, $id,
+// and $anchor keywords, at the top level and in every nested subschema, for tools
+// that reject them. Everything else is kept as is, including members with those
+// names that are not keywords, such as a property named "$id", a const value, or
+// an x- extension.
+// Unlike schemaprofile normalization it changes no constraints or annotations, but
+// references to a removed $id or $anchor no longer resolve.
+func StripSchemaMeta(schema JSONSchema) JSONSchema {
+	if schema == nil {
+		return nil
+	}
+	return JSONSchema(stripSchemaMeta(map[string]any(schema)))
+}
+
+// StripSchemaMeta returns a copy of i with StripSchemaMeta applied to every embedded
+// schema: the entries of Schemas and each operation's input and output.
+func (i Interface) StripSchemaMeta() Interface {
+	out := i.Clone()
+	for k, s := range out.Schemas {
+		out.Schemas[k] = StripSchemaMeta(s)
+	}
+	for k, op := range out.Operations {
+		op.Input = StripSchemaMeta(op.Input)
+		op.Output = StripSchemaMeta(op.Output)
+		out.Operations[k] = op
+	}
+	return out
+}
+
+func stripSchemaMeta(schema map[string]any) map[string]any {
+	out := make(map[string]any, len(schema))
+	for k, v := range schema {
+		if _, ok := schemaMetaKeywords[k]; ok {
+			continue
+		}
+		if _, ok := schemaValueKeywords[k]; ok || strings.HasPrefix(k, "x-") {
+			out[k] = cloneJSONValue(v)
+			continue
+		}
+		if m, ok := v.(map[string]any); ok {
+			if _, named := schemaMapKeywords[k]; named {
+				entries := make(map[string]any, len(m))
+				for name, sub := range m {
+					entries[name] = stripSubschemas(sub)
+				}
+				out[k] = entries
+				continue
+			}
+		}
+		out[k] = stripSubschemas(v)
+	}
+	return out
+}
+
+// stripSubschemas applies stripSchemaMeta to the schema v or, in an array such as
+// allOf or prefixItems, to each schema in it.
+func stripSubschemas(v any) any {
+	switch x := v.(type) {
+	case map[string]any:
+		return stripSchemaMeta(x)
+	case []any:
+		out := make([]any, len(x))
+		for idx, item := range x {
+			out[idx] = stripSubschemas(item)
+		}
+		return out
+	}
+	return v
+}
@@ -0,0 +1,72 @@
+package openbindings
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStripSchemaMeta(t *testing.T) {
+	var schema JSONSchema
+	mustUnmarshalJSON(t, []byte(`{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "$id": "https://example.com/user.json",
+  "$vocabulary": {"https://json-schema.org/draft/2020-12/vocab/core": true},
+  "type": "object",
+  "properties": {
+    "$id": {"type": "string", "$anchor": "userId"},
+    "tags": {"type": "array", "prefixItems": [{"$id": "tag", "type": "string"}]}
+  },
+  "$defs": {"Name": {"$anchor": "name", "type": "string", "minLength": 1}},
+  "allOf": [{"$schema": "https://json-schema.org/draft/2020-12/schema", "required": ["$id"]}],
+  "const": {"$id": "kept"},
+  "x-meta": {"$schema": "kept"}
+}`), &schema)
+	before := schema.Clone()
+
+	var want JSONSchema
+	mustUnmarshalJSON(t, []byte(`{
+  "type": "object",
+  "properties": {
+    "$id": {"type": "string"},
+    "tags": {"type": "array", "prefixItems": [{"type": "string"}]}
+  },
+  "$defs": {"Name": {"type": "string", "minLength": 1}},
+  "allOf": [{"required": ["$id"]}],
+  "const": {"$id": "kept"},
+  "x-meta": {"$schema": "kept"}
+}`), &want)
+
+	got := StripSchemaMeta(schema)
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected result:\n got %v\nwant %v", got, want)
+	}
+	got["const"].(map[string]any)["$id"] = "changed"
+	if !reflect.DeepEqual(schema, before) {
+		t.Fatal("StripSchemaMeta modified or shares memory with its input")
+	}
+	if StripSchemaMeta(nil) != nil {
+		t.Fatal("expected nil for a nil schema")
+	}
+
+	i := Interface{
+		OpenBindings: "0.1.0",
+		Schemas:      map[string]JSONSchema{"User": schema},
+		Operations: map[string]Operation{
+			"getUser": {Input: JSONSchema{"$id": "in", "type": "object"}, Output: JSONSchema{"$ref": "#/schemas/User"}},
+			"ping":    {},
+		},
+	}
+	out := i.StripSchemaMeta()
+	if !reflect.DeepEqual(out.Schemas["User"], want) {
+		t.Fatalf("unexpected schema %v", out.Schemas["User"])
+	}
+	if op := out.Operations["getUser"]; !reflect.DeepEqual(op.Input, JSONSchema{"type": "object"}) || op.Output["$ref"] != "#/schemas/User" {
+		t.Fatalf("unexpected operation schemas %v %v", op.Input, op.Output)
+	}
+	if out.Operations["ping"].Input != nil {
+		t.Fatal("expected absent schemas to stay absent")
+	}
+	if i.Operations["getUser"].Input["$id"] != "in" {
+		t.Fatal("Interface.StripSchemaMeta modified its receiver")
+	}
+}
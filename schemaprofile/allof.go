@@ -8,7 +8,7 @@ import (
 )
 
 // flattenAllOf merges all branches of an allOf into a single schema.
-func (n *Normalizer) flattenAllOf(allOf any, path string) (map[string]any, error) {
+func (n *Normalizer) flattenAllOf(refs *refStack, allOf any, path string) (map[string]any, error) {
 	arr, ok := asSlice(allOf)
 	if !ok {
 		return nil, fmt.Errorf("%s.allOf: must be array", pathOrRoot(path))
@@ -17,8 +17,27 @@ func (n *Normalizer) flattenAllOf(allOf any, path string) (map[string]any, error
 		return map[string]any{}, nil
 	}
 
-	merged := map[string]any{}
+	// A $ref branch with constraining siblings contributes both as separate branches.
+	type allOfItem struct {
+		idx  int
+		item any
+	}
+	items := make([]allOfItem, 0, len(arr))
 	for idx, item := range arr {
+		if m, ok := asMap(item); ok {
+			if branches, ok := n.refAllOf(m); ok {
+				for _, b := range branches {
+					items = append(items, allOfItem{idx, b})
+				}
+				continue
+			}
+		}
+		items = append(items, allOfItem{idx, item})
+	}
+
+	merged := map[string]any{}
+	for _, it := range items {
+		idx, item := it.idx, it.item
 		branch, ok := asMap(item)
 		if !ok {
 			return nil, fmt.Errorf("%s.allOf[%d]: must be object", pathOrRoot(path), idx)
@@ -29,7 +48,8 @@ func (n *Normalizer) flattenAllOf(allOf any, path string) (map[string]any, error
 		branch = applyNullable(branch)
 
 		// Check for out-of-profile keywords in branch.
-		if err := assertProfileKeywords(branch, branchPath); err != nil {
+		branch, err := n.profileKeywords(refs, branch, branchPath)
+		if err != nil {
 			return nil, err
 		}
 
@@ -43,19 +63,33 @@ func (n *Normalizer) flattenAllOf(allOf any, path string) (map[string]any, error
 
 		// Resolve $ref in branch first.
 		if ref, ok := branch["$ref"].(string); ok && strings.TrimSpace(ref) != "" {
-			resolved, cleanup, err := n.resolveRef(ref, branchPath)
+			t, err := n.targetOf(refs, ref, branchPath)
+			if err != nil {
+				return nil, err
+			}
+			resolved, cleanup, err := n.resolveRef(refs, ref, t, branchPath)
 			if err != nil {
 				return nil, err
 			}
 			cleanup() // allOf branches are merged, not recursively normalized via this ref
-			rm, ok := asMap(resolved)
+			rm, ok := asSchema(resolved)
 			if !ok {
-				return nil, &RefError{Path: branchPath, Ref: ref, Err: errors.New("resolved $ref is not an object")}
+				return nil, &RefError{Path: branchPath, Ref: ref, Err: errors.New("resolved $ref is not a schema")}
 			}
 			branch = rm
 		}
 
-		if err := mergeAllOfBranch(merged, branch, branchPath); err != nil {
+		if n.NormalizeValue != nil {
+			branch = cloneMap(branch)
+			n.applyValueNormalizer(branch)
+		}
+
+		branch, err = applyTupleItems(branch, branchPath)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := n.mergeAllOfBranch(merged, branch, branchPath); err != nil {
 			return nil, err
 		}
 	}
@@ -63,18 +97,58 @@ func (n *Normalizer) flattenAllOf(allOf any, path string) (map[string]any, error
 	return merged, nil
 }
 
+// refAllOf returns the allOf branches equivalent to a schema holding $ref and
+// sibling keywords: the bare $ref, the branches of a sibling allOf, and the remaining
+// siblings. ok is false when no sibling constrains values (annotations, $defs, and
+// extensions do not), in which case the $ref stands alone.
+func (n *Normalizer) refAllOf(schema map[string]any) ([]any, bool) {
+	if ref, ok := schema["$ref"].(string); !ok || strings.TrimSpace(ref) == "" {
+		return nil, false
+	}
+	siblings := map[string]any{}
+	for k, v := range schema {
+		if k == "$ref" || k == "$defs" || k == "$id" || strings.HasPrefix(k, "x-") {
+			continue
+		}
+		if _, isAnnotation := annotationKeywords[k]; isAnnotation && !(k == "format" && n.FormatAsConstraint) {
+			continue
+		}
+		siblings[k] = v
+	}
+	if len(siblings) == 0 {
+		return nil, false
+	}
+	branches := []any{map[string]any{"$ref": schema["$ref"]}}
+	if nested, ok := asSlice(siblings["allOf"]); ok {
+		branches = append(branches, nested...)
+		delete(siblings, "allOf")
+	}
+	if len(siblings) > 0 {
+		branches = append(branches, siblings)
+	}
+	return branches, true
+}
+
 // mergeAllOfBranch merges a single allOf branch into the accumulator.
 //
 // Keywords handled (in order):
 //   - type:                  intersection (with integer ⊆ number subtype rule)
 //   - properties:            union of keys; recursive merge for overlapping keys
+//   - patternProperties:     union of patterns; recursive merge for identical patterns
 //   - required:              union
+//   - dependentRequired:     union of the lists per property
 //   - additionalProperties:  false wins; schemas merge recursively
 //   - enum:                  intersection (empty → SchemaError)
 //   - const:                 conflict → SchemaError
+//   - prefixItems:           positional recursive merge
 //   - items:                 recursive merge
+//   - not:                   union of the excluded schemas
+//   - if/then/else:          identical blocks only (differing → OutsideProfileError)
+//   - format:                with FormatAsConstraint, the narrower of two related formats
+//     (unrelated → OutsideProfileError)
 //   - bounds:                most restrictive wins (min↑, max↓)
-func mergeAllOfBranch(acc, branch map[string]any, path string) error {
+//   - multipleOf:            least common multiple
+func (n *Normalizer) mergeAllOfBranch(acc, branch map[string]any, path string) error {
 	// type: intersection
 	if bt, ok := branch["type"]; ok {
 		bTypes, err := normalizeType(bt)
@@ -118,7 +192,7 @@ func mergeAllOfBranch(acc, branch map[string]any, path string) error {
 					bvm = map[string]any{}
 				}
 				merged := cloneMap(avm)
-				if err := mergeAllOfBranch(merged, bvm, path+".properties[\""+k+"\"]"); err != nil {
+				if err := n.mergeAllOfBranch(merged, bvm, path+".properties[\""+k+"\"]"); err != nil {
 					return err
 				}
 				aProps[k] = merged
@@ -129,6 +203,33 @@ func mergeAllOfBranch(acc, branch map[string]any, path string) error {
 		acc["properties"] = aProps
 	}
 
+	// patternProperties: union, recursive merge for identical patterns
+	if bp, ok := branch["patternProperties"]; ok {
+		bPatterns, ok := asMap(bp)
+		if !ok {
+			return fmt.Errorf("%s.patternProperties: must be object", path)
+		}
+		aPatterns, _ := asMap(acc["patternProperties"])
+		if aPatterns == nil {
+			aPatterns = map[string]any{}
+		}
+		for k, bv := range bPatterns {
+			av, exists := aPatterns[k]
+			if !exists {
+				aPatterns[k] = bv
+				continue
+			}
+			avm, _ := asSchema(av)
+			bvm, _ := asSchema(bv)
+			merged := cloneMap(avm)
+			if err := n.mergeAllOfBranch(merged, bvm, path+".patternProperties[\""+k+"\"]"); err != nil {
+				return err
+			}
+			aPatterns[k] = merged
+		}
+		acc["patternProperties"] = aPatterns
+	}
+
 	// required: union
 	if br, ok := branch["required"]; ok {
 		bReq, err := normalizeStringSet(br)
@@ -147,6 +248,28 @@ func mergeAllOfBranch(acc, branch map[string]any, path string) error {
 		}
 	}
 
+	// dependentRequired: union per property
+	if bd, ok := branch["dependentRequired"]; ok {
+		bDeps, err := normalizeDependentRequired(bd)
+		if err != nil {
+			return fmt.Errorf("%s.dependentRequired: %w", path, err)
+		}
+		aDeps := map[string]any{}
+		if ad, ok := acc["dependentRequired"]; ok {
+			if aDeps, err = normalizeDependentRequired(ad); err != nil {
+				return fmt.Errorf("%s.dependentRequired: %w", path, err)
+			}
+		}
+		for k, bReq := range bDeps {
+			if aReq, ok := aDeps[k].([]any); ok {
+				aDeps[k] = unionStringSlices(aReq, bReq.([]any))
+			} else {
+				aDeps[k] = bReq
+			}
+		}
+		acc["dependentRequired"] = aDeps
+	}
+
 	// additionalProperties: false wins; schemas merge recursively
 	if bap, ok := branch["additionalProperties"]; ok {
 		switch bv := bap.(type) {
@@ -167,7 +290,7 @@ func mergeAllOfBranch(acc, branch map[string]any, path string) error {
 					}
 				case map[string]any:
 					merged := cloneMap(av)
-					if err := mergeAllOfBranch(merged, bv, path+".additionalProperties"); err != nil {
+					if err := n.mergeAllOfBranch(merged, bv, path+".additionalProperties"); err != nil {
 						return err
 					}
 					acc["additionalProperties"] = merged
@@ -206,6 +329,55 @@ func mergeAllOfBranch(acc, branch map[string]any, path string) error {
 		}
 	}
 
+	// prefixItems: positional merge. Past the end of its own prefix, each side
+	// constrains positions with its items schema, so that is what gets merged there.
+	// This runs before items are merged so acc["items"] is still acc's own.
+	_, accHasPrefix := acc["prefixItems"]
+	_, branchHasPrefix := branch["prefixItems"]
+	if accHasPrefix || branchHasPrefix {
+		aArr, ok := asSlice(acc["prefixItems"])
+		if accHasPrefix && !ok {
+			return fmt.Errorf("%s.prefixItems: must be array", path)
+		}
+		bArr, ok := asSlice(branch["prefixItems"])
+		if branchHasPrefix && !ok {
+			return fmt.Errorf("%s.prefixItems: must be array", path)
+		}
+		merged := make([]any, max(len(aArr), len(bArr)))
+		for idx := range merged {
+			av, bv := acc["items"], branch["items"]
+			if idx < len(aArr) {
+				av = aArr[idx]
+			}
+			if idx < len(bArr) {
+				bv = bArr[idx]
+			}
+			itemPath := fmt.Sprintf("%s.prefixItems[%d]", path, idx)
+			if av == nil {
+				merged[idx] = bv
+				continue
+			}
+			if bv == nil {
+				merged[idx] = av
+				continue
+			}
+			am, ok := asSchema(av)
+			if !ok {
+				return fmt.Errorf("%s: must be boolean or object", itemPath)
+			}
+			bm, ok := asSchema(bv)
+			if !ok {
+				return fmt.Errorf("%s: must be boolean or object", itemPath)
+			}
+			m := cloneMap(am)
+			if err := n.mergeAllOfBranch(m, bm, itemPath); err != nil {
+				return err
+			}
+			merged[idx] = m
+		}
+		acc["prefixItems"] = merged
+	}
+
 	// items: recursive merge
 	if bi, ok := branch["items"]; ok {
 		bItems, ok := asMap(bi)
@@ -218,7 +390,7 @@ func mergeAllOfBranch(acc, branch map[string]any, path string) error {
 				aItems = map[string]any{}
 			}
 			merged := cloneMap(aItems)
-			if err := mergeAllOfBranch(merged, bItems, path+".items"); err != nil {
+			if err := n.mergeAllOfBranch(merged, bItems, path+".items"); err != nil {
 				return err
 			}
 			acc["items"] = merged
@@ -227,14 +399,65 @@ func mergeAllOfBranch(acc, branch map[string]any, path string) error {
 		}
 	}
 
+	// not: excluding both sets is excluding their union.
+	if bn, ok := branch["not"]; ok {
+		if an, ok := acc["not"]; ok {
+			if canonicalKey(an) != canonicalKey(bn) {
+				if m, ok := asSchema(an); ok {
+					an = m
+				}
+				if m, ok := asSchema(bn); ok {
+					bn = m
+				}
+				acc["not"] = map[string]any{"anyOf": []any{an, bn}}
+			}
+		} else {
+			acc["not"] = bn
+		}
+	}
+
+	// if/then/else: a schema holds one conditional block, so branches may only
+	// repeat the same one.
+	if _, ok := branch["if"]; ok {
+		bc, _ := conditionalBlock(branch)
+		if ac, ok := conditionalBlock(acc); ok {
+			if canonicalKey(ac) != canonicalKey(bc) {
+				return &OutsideProfileError{Path: path, Keyword: "differing if/then/else inside allOf"}
+			}
+		} else {
+			for k, v := range bc {
+				acc[k] = v
+			}
+		}
+	}
+
+	// format: the narrower wins; unrelated formats have no expressible intersection.
+	if bf, ok := branch["format"]; ok && n.FormatAsConstraint {
+		bs, ok := bf.(string)
+		if !ok {
+			return fmt.Errorf("%s.format: must be string", path)
+		}
+		if as, ok := acc["format"].(string); ok {
+			subsets := n.formatSubsets()
+			switch {
+			case formatWithin(subsets, as, bs):
+				// acc already within the branch format
+			case formatWithin(subsets, bs, as):
+				acc["format"] = bs
+			default:
+				return &OutsideProfileError{Path: path, Keyword: "differing format inside allOf"}
+			}
+		} else {
+			acc["format"] = bs
+		}
+	}
+
 	// Numeric/string/array bounds: most restrictive wins.
 	// Lower bounds: take the highest (most restrictive)
 	for _, k := range []string{"minimum", "exclusiveMinimum", "minLength", "minItems"} {
 		if bv, ok := branch[k]; ok {
-			bf := toFloat64(bv)
 			if av, ok := acc[k]; ok {
-				af := toFloat64(av)
-				if bf > af {
+				if numRat(bv).Cmp(numRat(av)) > 0 {
 					acc[k] = bv
 				}
 			} else {
@@ -245,10 +468,8 @@ func mergeAllOfBranch(acc, branch map[string]any, path string) error {
 	// Upper bounds: take the lowest (most restrictive)
 	for _, k := range []string{"maximum", "exclusiveMaximum", "maxLength", "maxItems"} {
 		if bv, ok := branch[k]; ok {
-			bf := toFloat64(bv)
 			if av, ok := acc[k]; ok {
-				af := toFloat64(av)
-				if bf < af {
+				if numRat(bv).Cmp(numRat(av)) < 0 {
 					acc[k] = bv
 				}
 			} else {
@@ -257,6 +478,30 @@ func mergeAllOfBranch(acc, branch map[string]any, path string) error {
 		}
 	}
 
+	// multipleOf: least common multiple, computed exactly.
+	if bv, ok := branch["multipleOf"]; ok {
+		br, ok := positiveRat(bv)
+		if !ok {
+			return fmt.Errorf("%s.multipleOf: must be a positive number", path)
+		}
+		if av, ok := acc["multipleOf"]; ok {
+			ar, ok := positiveRat(av)
+			if !ok {
+				return fmt.Errorf("%s.multipleOf: must be a positive number", path)
+			}
+			switch lcm := lcmRat(ar, br); {
+			case lcm.Cmp(ar) == 0:
+				// acc already a multiple of the branch value
+			case lcm.Cmp(br) == 0:
+				acc["multipleOf"] = bv
+			default:
+				acc["multipleOf"] = ratNumber(lcm)
+			}
+		} else {
+			acc["multipleOf"] = bv
+		}
+	}
+
 	return nil
 }
 
@@ -0,0 +1,156 @@
+package schemaprofile
+
+import (
+	"reflect"
+	"sync"
+)
+
+// normalizeCache memoizes normalized schemas for a Normalizer with CacheEnabled.
+// Only successful results are stored: errors carry the path at which they occurred.
+//
+// A cached result is valid under any cycle-detection state. If normalizing a schema
+// would reach a $ref already on the current stack, that $ref reaches itself, so the
+// same schema fails with a cycle error from an empty stack too and is never cached.
+type normalizeCache struct {
+	mu      sync.Mutex
+	scope   cacheScope
+	entries map[string]map[string]any
+}
+
+// cacheScope identifies the Normalizer settings that cached results depend on.
+// NumericTolerance only affects comparison, so it is not part of the scope. Nor is
+// MaxDepth: it only guards recursion, which a cache hit does not perform.
+type cacheScope struct {
+	root                 any
+	base                 string
+	disallowExternalRefs bool
+	formatAsConstraint   bool
+	formatSubsets        uintptr
+	onOutsideProfile     OutsideProfilePolicy
+}
+
+// scope returns the current cacheScope of n. Reference-typed roots (the usual
+// decoded-JSON map) are identified by address; other values by their canonical JSON.
+// FormatSubsets is identified by address too.
+func (n *Normalizer) scope() cacheScope {
+	s := cacheScope{disallowExternalRefs: n.DisallowExternalRefs, formatAsConstraint: n.FormatAsConstraint, onOutsideProfile: n.OnOutsideProfile}
+	if n.Base != nil {
+		s.base = n.Base.String()
+	}
+	if n.FormatSubsets != nil {
+		s.formatSubsets = reflect.ValueOf(n.FormatSubsets).Pointer()
+	}
+	switch v := reflect.ValueOf(n.Root); v.Kind() {
+	case reflect.Map, reflect.Slice, reflect.Pointer:
+		s.root = v.Pointer()
+	case reflect.Invalid:
+		s.root = nil
+	default:
+		s.root = canonicalKey(n.Root)
+	}
+	return s
+}
+
+// caching reports whether results are cached. Under CollectAndContinue they are
+// not: a cache hit would skip recording the out-of-profile keywords.
+func (n *Normalizer) caching() bool {
+	return n.CacheEnabled && n.OnOutsideProfile != CollectAndContinue
+}
+
+// cacheGet returns a copy of the cached normalization for key, if any.
+func (n *Normalizer) cacheGet(key string) (map[string]any, bool) {
+	if !n.caching() {
+		return nil, false
+	}
+	scope := n.scope()
+	c := &n.cache
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.scope != scope {
+		c.scope = scope
+		c.entries = nil
+		return nil, false
+	}
+	v, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	return cloneJSON(v).(map[string]any), true
+}
+
+// cachePut stores a copy of schema under key.
+func (n *Normalizer) cachePut(key string, schema map[string]any) {
+	if !n.caching() {
+		return
+	}
+	scope := n.scope()
+	stored := cloneJSON(schema).(map[string]any)
+	c := &n.cache
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.scope != scope || c.entries == nil {
+		c.scope = scope
+		c.entries = map[string]map[string]any{}
+	}
+	c.entries[key] = stored
+}
+
+// ResetCache discards all cached normalizations. The cache is discarded automatically
+// when Root, Base, DisallowExternalRefs, FormatAsConstraint, FormatSubsets, or
+// OnOutsideProfile is replaced, but changes made in place to the contents of Root or
+// FormatSubsets, or to Fetch or NormalizeValue, are not detected.
+func (n *Normalizer) ResetCache() {
+	if n == nil {
+		return
+	}
+	n.cache.mu.Lock()
+	n.cache.entries = nil
+	n.cache.mu.Unlock()
+}
+
+// normalizeRoot normalizes a schema passed to a public method, consulting the cache
+// keyed by the schema's canonical JSON.
+func (n *Normalizer) normalizeRoot(refs *refStack, schema map[string]any) (map[string]any, error) {
+	if !n.caching() {
+		return n.normalizeAt(refs, schema, "")
+	}
+	key, err := CanonicalString(schema)
+	if err != nil {
+		return n.normalizeAt(refs, schema, "")
+	}
+	if out, ok := n.cacheGet(key); ok {
+		return out, nil
+	}
+	out, err := n.normalizeAt(refs, schema, "")
+	if err != nil {
+		return nil, err
+	}
+	n.cachePut(key, out)
+	return out, nil
+}
+
+// refCacheKey is the cache key for the normalized target of ref. The "$ref " prefix
+// cannot begin a canonical JSON object, so it never collides with a schema key.
+func refCacheKey(ref string) string {
+	return "$ref " + ref
+}
+
+// cloneJSON deep-copies the maps and slices of a decoded JSON value.
+func cloneJSON(v any) any {
+	switch x := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(x))
+		for k, e := range x {
+			out[k] = cloneJSON(e)
+		}
+		return out
+	case []any:
+		out := make([]any, len(x))
+		for i, e := range x {
+			out[i] = cloneJSON(e)
+		}
+		return out
+	default:
+		return v
+	}
+}
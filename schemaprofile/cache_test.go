@@ -0,0 +1,177 @@
+package schemaprofile
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestCache_ReturnsIndependentCopies(t *testing.T) {
+	n := &Normalizer{
+		Root:         map[string]any{"schemas": map[string]any{"Name": map[string]any{"type": "string"}}},
+		CacheEnabled: true,
+	}
+	schema := map[string]any{"type": "object", "properties": map[string]any{"name": map[string]any{"$ref": "#/schemas/Name"}}}
+
+	first, err := n.Normalize(schema)
+	if err != nil {
+		t.Fatalf("normalize: %v", err)
+	}
+	first["properties"].(map[string]any)["name"].(map[string]any)["type"] = "mutated"
+
+	second, err := n.Normalize(schema)
+	if err != nil {
+		t.Fatalf("normalize: %v", err)
+	}
+	if got := canonicalKey(second); got != `{"properties":{"name":{"type":["string"]}},"type":["object"]}` {
+		t.Fatalf("cached result was affected by caller mutation: %s", got)
+	}
+}
+
+func TestCache_InvalidatedWhenRootReplaced(t *testing.T) {
+	n := &Normalizer{
+		Root:         map[string]any{"schemas": map[string]any{"Id": map[string]any{"type": "string"}}},
+		CacheEnabled: true,
+	}
+	ref := map[string]any{"$ref": "#/schemas/Id"}
+	if out, err := n.Normalize(ref); err != nil || canonicalKey(out) != `{"type":["string"]}` {
+		t.Fatalf("normalize: %v %v", out, err)
+	}
+
+	n.Root = map[string]any{"schemas": map[string]any{"Id": map[string]any{"type": "integer"}}}
+	if out, err := n.Normalize(ref); err != nil || canonicalKey(out) != `{"type":["integer"]}` {
+		t.Fatalf("expected new Root to be used, got %v %v", out, err)
+	}
+
+	// In-place edits are not detected until ResetCache.
+	n.Root.(map[string]any)["schemas"].(map[string]any)["Id"] = map[string]any{"type": "boolean"}
+	if out, _ := n.Normalize(ref); canonicalKey(out) != `{"type":["integer"]}` {
+		t.Fatalf("expected cached result before reset, got %v", out)
+	}
+	n.ResetCache()
+	if out, err := n.Normalize(ref); err != nil || canonicalKey(out) != `{"type":["boolean"]}` {
+		t.Fatalf("expected reset cache to pick up edit, got %v %v", out, err)
+	}
+}
+
+func TestCache_InvalidatedWhenFormatSubsetsReplaced(t *testing.T) {
+	n := &Normalizer{FormatAsConstraint: true, CacheEnabled: true, Root: map[string]any{}}
+	schema := map[string]any{"allOf": []any{
+		map[string]any{"type": "string", "format": "uuid"},
+		map[string]any{"format": "string-id"},
+	}}
+	if _, err := n.Normalize(schema); err == nil {
+		t.Fatal("expected unrelated formats to fail")
+	}
+
+	n.FormatSubsets = map[string][]string{"uuid": {"string-id"}}
+	out, err := n.Normalize(schema)
+	if err != nil || out["format"] != "uuid" {
+		t.Fatalf("expected new FormatSubsets to be used, got %v %v", out, err)
+	}
+
+	n.FormatSubsets = map[string][]string{"string-id": {"uuid"}}
+	out, err = n.Normalize(schema)
+	if err != nil || out["format"] != "string-id" {
+		t.Fatalf("expected replaced FormatSubsets to be used, got %v %v", out, err)
+	}
+}
+
+func TestCache_CycleStillDetected(t *testing.T) {
+	n := &Normalizer{
+		Root: map[string]any{"schemas": map[string]any{
+			"Node": map[string]any{"type": "object", "properties": map[string]any{"next": map[string]any{"$ref": "#/schemas/Node"}}},
+		}},
+		CacheEnabled: true,
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := n.Normalize(map[string]any{"$ref": "#/schemas/Node"}); err == nil {
+			t.Fatalf("call %d: expected cycle error", i)
+		}
+	}
+}
+
+func TestCache_ConcurrentUse(t *testing.T) {
+	root, ops := sharedSchemaDocument()
+	want := make([]string, len(ops))
+	for i, op := range ops {
+		out, err := (&Normalizer{Root: root}).Normalize(op)
+		if err != nil {
+			t.Fatalf("normalize: %v", err)
+		}
+		want[i] = canonicalKey(out)
+	}
+
+	n := &Normalizer{Root: root, CacheEnabled: true}
+	var wg sync.WaitGroup
+	failures := make(chan string, len(ops))
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i, op := range ops {
+				out, err := n.Normalize(op)
+				if err != nil || canonicalKey(out) != want[i] {
+					failures <- fmt.Sprintf("op %d: err=%v", i, err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(failures)
+	for f := range failures {
+		t.Fatal(f)
+	}
+}
+
+// sharedSchemaDocument returns a root with 10 shared schemas and 50 operation
+// schemas that reference them.
+func sharedSchemaDocument() (map[string]any, []map[string]any) {
+	schemas := map[string]any{}
+	for i := 0; i < 10; i++ {
+		props := map[string]any{}
+		for j := 0; j < 8; j++ {
+			props[fmt.Sprintf("f%d", j)] = map[string]any{
+				"oneOf": []any{
+					map[string]any{"type": "string", "maxLength": 64, "description": "text"},
+					map[string]any{"type": "integer", "minimum": 0},
+				},
+			}
+		}
+		schemas[fmt.Sprintf("S%d", i)] = map[string]any{
+			"allOf": []any{
+				map[string]any{"type": "object", "properties": props},
+				map[string]any{"required": []any{"f0", "f1"}},
+			},
+		}
+	}
+	ops := make([]map[string]any, 50)
+	for i := range ops {
+		ops[i] = map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"a": map[string]any{"$ref": fmt.Sprintf("#/schemas/S%d", i%10)},
+				"b": map[string]any{"$ref": fmt.Sprintf("#/schemas/S%d", (i+3)%10)},
+			},
+		}
+	}
+	return map[string]any{"schemas": schemas}, ops
+}
+
+func BenchmarkNormalize_SharedSchemas(b *testing.B) {
+	root, ops := sharedSchemaDocument()
+	for _, cached := range []bool{false, true} {
+		b.Run(fmt.Sprintf("cache=%v", cached), func(b *testing.B) {
+			n := &Normalizer{Root: root, CacheEnabled: cached}
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				for _, op := range ops {
+					if _, err := n.Normalize(op); err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+		})
+	}
+}
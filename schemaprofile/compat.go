@@ -1,18 +1,72 @@
 package schemaprofile
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+	"math/big"
+)
+
+// comparer carries per-call comparison settings derived from a Normalizer.
+type comparer struct {
+	// ctx aborts the comparison once done; compat checks it at every level.
+	ctx context.Context
+	// epsilon is the tolerance within which numeric bounds are treated as equal
+	// and a multipleOf as a multiple of another.
+	epsilon float64
+	// formats enables the format rules, using the formatSubsets hierarchy.
+	formats       bool
+	formatSubsets map[string][]string
+}
+
+// snap returns b when a is within the tolerance of b, and a otherwise.
+func (c *comparer) snap(a, b *big.Rat) *big.Rat {
+	if c.within(a, b) {
+		return b
+	}
+	return a
+}
+
+// within reports whether a and b differ by no more than the tolerance.
+func (c *comparer) within(a, b *big.Rat) bool {
+	if c.epsilon <= 0 {
+		return false
+	}
+	eps := new(big.Rat)
+	if eps.SetFloat64(c.epsilon) == nil {
+		return false
+	}
+	return new(big.Rat).Abs(new(big.Rat).Sub(a, b)).Cmp(eps) <= 0
+}
+
+// isMultiple reports whether x is an integer multiple of of, or within the
+// tolerance of one.
+func (c *comparer) isMultiple(x, of *big.Rat) bool {
+	q := new(big.Rat).Quo(x, of)
+	if q.IsInt() {
+		return true
+	}
+	// x and of are positive, so the nearest multiples are floor(q) and floor(q)+1
+	// times of; a zero multiple is not a multiple of a positive multipleOf.
+	k := new(big.Int).Quo(q.Num(), q.Denom())
+	for _, n := range []*big.Int{k, new(big.Int).Add(k, big.NewInt(1))} {
+		if n.Sign() > 0 && c.within(x, new(big.Rat).Mul(new(big.Rat).SetInt(n), of)) {
+			return true
+		}
+	}
+	return false
+}
 
 // inputCompatible implements profile v0.1 input rules (interface schema <= candidate schema).
-func inputCompatible(tgt, cand map[string]any) (bool, string, error) {
+func (c *comparer) inputCompatible(tgt, cand map[string]any) (bool, string, error) {
 	// Trivial schema: {} is Top.
 	if len(cand) == 0 {
 		return true, "", nil
 	}
-	return compat(tgt, cand, true)
+	return c.compat(tgt, cand, true)
 }
 
 // outputCompatible implements profile v0.1 output/payload rules (candidate schema <= interface schema).
-func outputCompatible(tgt, cand map[string]any) (bool, string, error) {
+func (c *comparer) outputCompatible(tgt, cand map[string]any) (bool, string, error) {
 	// Trivial schema: {} is Top; allowed only if interface is also Top.
 	if len(cand) == 0 {
 		if len(tgt) == 0 {
@@ -20,10 +74,31 @@ func outputCompatible(tgt, cand map[string]any) (bool, string, error) {
 		}
 		return false, "candidate is unconstrained but target is not", nil
 	}
-	return compat(tgt, cand, false)
+	return c.compat(tgt, cand, false)
 }
 
-func compat(tgt, cand map[string]any, isInput bool) (bool, string, error) {
+func (c *comparer) compat(tgt, cand map[string]any, isInput bool) (bool, string, error) {
+	if err := c.ctx.Err(); err != nil {
+		return false, "", err
+	}
+	// Bottom (no admissible values) is handled before Top: an input target that
+	// sends nothing, or an output candidate that emits nothing, is always compatible.
+	if isInput {
+		if isBottom(tgt) {
+			return true, "", nil
+		}
+		if isBottom(cand) {
+			return false, "candidate accepts no values", nil
+		}
+	} else {
+		if isBottom(cand) {
+			return true, "", nil
+		}
+		if isBottom(tgt) {
+			return false, "target allows no values but candidate does", nil
+		}
+	}
+
 	// If either side is Top, handle per direction.
 	if len(tgt) == 0 {
 		// Empty target ({}) is Top — "could send/receive anything".
@@ -47,9 +122,9 @@ func compat(tgt, cand map[string]any, isInput bool) (bool, string, error) {
 		return false, "candidate is unconstrained but target is not", nil
 	}
 
-	// Type set rules.
-	tgtTypes := typeSet(tgt)
-	candTypes := typeSet(cand)
+	// Type set rules, with the types a const or enum implies.
+	tgtTypes := effectiveTypeSet(tgt)
+	candTypes := effectiveTypeSet(cand)
 	if tgtTypes != nil || candTypes != nil {
 		// Missing type means unconstrained; treat as all types.
 		if isInput {
@@ -73,31 +148,35 @@ func compat(tgt, cand map[string]any, isInput bool) (bool, string, error) {
 	}
 
 	// Object rules if type includes object.
-	if hasType(tgt, "object") || hasType(cand, "object") {
-		ok, reason := compatObject(tgt, cand, isInput)
+	if constrains(tgt, cand, isInput, "object") {
+		ok, reason := c.compatObject(tgt, cand, isInput)
 		if !ok {
 			return false, reason, nil
 		}
 	}
 
 	// Array rules if type includes array.
-	if hasType(tgt, "array") || hasType(cand, "array") {
-		ok, reason := compatArray(tgt, cand, isInput)
+	if constrains(tgt, cand, isInput, "array") {
+		ok, reason := c.compatArray(tgt, cand, isInput)
 		if !ok {
 			return false, reason, nil
 		}
 	}
 
 	// Numeric bounds rules (when type includes number or integer).
-	if hasType(tgt, "number") || hasType(tgt, "integer") || hasType(cand, "number") || hasType(cand, "integer") {
-		ok, reason := compatNumericBounds(tgt, cand, isInput)
+	if constrains(tgt, cand, isInput, "number") {
+		ok, reason := c.compatNumericBounds(tgt, cand, isInput)
+		if !ok {
+			return false, reason, nil
+		}
+		ok, reason = c.compatMultipleOf(tgt, cand, isInput)
 		if !ok {
 			return false, reason, nil
 		}
 	}
 
 	// String bounds rules (when type includes string).
-	if hasType(tgt, "string") || hasType(cand, "string") {
+	if constrains(tgt, cand, isInput, "string") {
 		ok, reason := compatStringBounds(tgt, cand, isInput)
 		if !ok {
 			return false, reason, nil
@@ -105,7 +184,7 @@ func compat(tgt, cand map[string]any, isInput bool) (bool, string, error) {
 	}
 
 	// Array bounds rules (when type includes array).
-	if hasType(tgt, "array") || hasType(cand, "array") {
+	if constrains(tgt, cand, isInput, "array") {
 		ok, reason := compatArrayBounds(tgt, cand, isInput)
 		if !ok {
 			return false, reason, nil
@@ -114,7 +193,39 @@ func compat(tgt, cand map[string]any, isInput bool) (bool, string, error) {
 
 	// Union rules.
 	if hasUnion(tgt) || hasUnion(cand) {
-		ok, reason := compatUnion(tgt, cand, isInput)
+		ok, reason := c.compatUnion(tgt, cand, isInput)
+		if !ok {
+			return false, reason, nil
+		}
+	}
+
+	// Exclusion rules.
+	if hasKey(tgt, "not") || hasKey(cand, "not") {
+		ok, reason := c.compatNot(tgt, cand, isInput)
+		if !ok {
+			return false, reason, nil
+		}
+	}
+
+	// Format rules, only when format is treated as a constraint.
+	if c.formats && (hasKey(tgt, "format") || hasKey(cand, "format")) {
+		ok, reason := c.compatFormat(tgt, cand, isInput)
+		if !ok {
+			return false, reason, nil
+		}
+	}
+
+	// Dependent requirement rules.
+	if hasKey(tgt, "dependentRequired") || hasKey(cand, "dependentRequired") {
+		ok, reason := compatDependentRequired(tgt, cand, isInput)
+		if !ok {
+			return false, reason, nil
+		}
+	}
+
+	// Conditional rules.
+	if hasKey(tgt, "if") || hasKey(cand, "if") {
+		ok, reason := compatConditional(tgt, cand, isInput)
 		if !ok {
 			return false, reason, nil
 		}
@@ -123,6 +234,126 @@ func compat(tgt, cand map[string]any, isInput bool) (bool, string, error) {
 	return true, "", nil
 }
 
+// compatConditional checks if/then/else. A conditional block only narrows the
+// schema it appears in, and the profile does not reason about its branches, so
+// blocks are compared whole by canonical equality:
+//   - input:  the candidate may omit the target's conditional, or carry the same one.
+//   - output: the target may omit the candidate's conditional, or carry the same one.
+//
+// With equal blocks, the remaining keywords decide compatibility on their own.
+func compatConditional(tgt, cand map[string]any, isInput bool) (bool, string) {
+	tc, tgtHas := conditionalBlock(tgt)
+	cc, candHas := conditionalBlock(cand)
+	if tgtHas && candHas {
+		if canonicalKey(tc) != canonicalKey(cc) {
+			return false, "if/then/else: conditionals differ"
+		}
+		return true, ""
+	}
+	if isInput && candHas {
+		return false, "if/then/else: candidate applies a conditional the target does not"
+	}
+	if !isInput && tgtHas {
+		return false, "if/then/else: target applies a conditional the candidate does not"
+	}
+	return true, ""
+}
+
+// compatDependentRequired checks dependentRequired. A dependency A -> [B] only
+// narrows the schema it appears in, so the narrower side must guarantee every
+// dependency of the wider side:
+//   - input:  each candidate dependency A -> B must hold for the target, because the
+//     target requires B outright or lists B under its own dependency for A.
+//   - output: each target dependency A -> B must hold for the candidate, in the same way.
+//
+// Conservatively, dependencies are not chained, and a dependency on a property the
+// narrower side never emits still needs to be declared.
+func compatDependentRequired(tgt, cand map[string]any, isInput bool) (bool, string) {
+	wide, narrow := tgt, cand
+	side := "candidate"
+	if isInput {
+		wide, narrow = cand, tgt
+		side = "target"
+	}
+	wideDeps, _ := asMap(wide["dependentRequired"])
+	narrowDeps, _ := asMap(narrow["dependentRequired"])
+	required := stringSet(narrow["required"])
+	for _, k := range sortedKeys(wideDeps) {
+		declared := stringSet(narrowDeps[k])
+		list, _ := asSlice(wideDeps[k])
+		for _, it := range list {
+			b, _ := it.(string)
+			_, req := required[b]
+			_, dep := declared[b]
+			if !req && !dep {
+				return false, fmt.Sprintf("dependentRequired[%q]: %s does not require %q", k, side, b)
+			}
+		}
+	}
+	return true, ""
+}
+
+// conditionalBlock returns the if/then/else keywords of a normalized schema.
+func conditionalBlock(schema map[string]any) (map[string]any, bool) {
+	if !hasKey(schema, "if") {
+		return nil, false
+	}
+	block := map[string]any{}
+	for _, k := range []string{"if", "then", "else"} {
+		if v, ok := schema[k]; ok {
+			block[k] = v
+		}
+	}
+	return block, true
+}
+
+// compatNot checks the not keyword. The other rules ignore not, which only ever
+// narrows a schema, so this check accounts for each side's exclusions:
+//   - input:  the candidate may only exclude values the target also excludes,
+//     i.e. not(cand) <= not(tgt).
+//   - output: the candidate must exclude at least what the target excludes,
+//     i.e. not(tgt) <= not(cand).
+//
+// A side without not excludes nothing. Exclusions are compared as value sets
+// using the output rules, so the check is sound but conservative.
+func (c *comparer) compatNot(tgt, cand map[string]any, isInput bool) (bool, string) {
+	tn, tgtHas := asMap(tgt["not"])
+	cn, candHas := asMap(cand["not"])
+	if isInput {
+		if !candHas {
+			return true, ""
+		}
+		if !tgtHas {
+			return false, "not: candidate excludes values the target may send"
+		}
+		// not(cand) <= not(tgt)
+		ok, reason, err := c.compat(tn, cn, false)
+		if err != nil {
+			return false, fmt.Sprintf("not: error: %v", err)
+		}
+		if !ok {
+			return false, fmt.Sprintf("not: candidate excludes more than target: %s", reason)
+		}
+		return true, ""
+	}
+
+	if !tgtHas {
+		return true, ""
+	}
+	if !candHas {
+		return false, "not: target excludes values the candidate does not"
+	}
+	// not(tgt) <= not(cand)
+	ok, reason, err := c.compat(cn, tn, false)
+	if err != nil {
+		return false, fmt.Sprintf("not: error: %v", err)
+	}
+	if !ok {
+		return false, fmt.Sprintf("not: candidate excludes less than target: %s", reason)
+	}
+	return true, ""
+}
+
 // missingTypes returns a quoted comma-separated list of types in a that are not in b.
 func missingTypes(a, b map[string]struct{}) string {
 	if a == nil {
@@ -175,6 +406,67 @@ func typeSet(schema map[string]any) map[string]struct{} {
 	return set
 }
 
+// effectiveTypeSet is typeSet narrowed to the JSON types of the schema's const or
+// enum values, if it has either: {"enum": ["a", "b"]} admits only strings whatever
+// its type says. nil means all types.
+func effectiveTypeSet(schema map[string]any) map[string]struct{} {
+	types := typeSet(schema)
+	var values []any
+	if c, ok := schema["const"]; ok {
+		values = []any{c}
+	} else if e, ok := asSlice(schema["enum"]); ok {
+		values = e
+	} else {
+		return types
+	}
+	implied := map[string]struct{}{}
+	for _, v := range values {
+		t := valueType(v)
+		if t == "" {
+			return types // not a JSON value; leave the type rules to the explicit type
+		}
+		implied[t] = struct{}{}
+	}
+	if types == nil {
+		return implied
+	}
+	out := map[string]struct{}{}
+	for t := range implied {
+		if _, ok := types[t]; ok {
+			out[t] = struct{}{}
+		} else if _, ok := types["number"]; ok && t == "integer" {
+			out[t] = struct{}{}
+		}
+	}
+	return out
+}
+
+// valueType returns the narrowest JSON Schema type of the JSON value v: "integer"
+// for integral numbers, "number" for the others. It returns "" for other Go values.
+func valueType(v any) string {
+	switch x := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	default:
+		r, ok := toRat(x)
+		if !ok {
+			return ""
+		}
+		if r.IsInt() {
+			return "integer"
+		}
+		return "number"
+	}
+}
+
 func subsetTypes(a, b map[string]struct{}) bool {
 	// nil means "all types".
 	if a == nil {
@@ -200,13 +492,55 @@ func subsetTypes(a, b map[string]struct{}) bool {
 	return true
 }
 
-func hasType(schema map[string]any, t string) bool {
-	set := typeSet(schema)
-	if set == nil {
+// typeKeywords lists, for each JSON type, the keywords that constrain only values
+// of that type ("number" covers integers too).
+var typeKeywords = map[string][]string{
+	"object": {"properties", "required", "additionalProperties", "patternProperties"},
+	"array":  {"items", "prefixItems", "minItems", "maxItems"},
+	"number": {"minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum", "multipleOf"},
+	"string": {"minLength", "maxLength"},
+}
+
+// constrains reports whether the rules for values of type t apply to a comparison.
+// They do when either side declares t, and also when either side uses t's keywords
+// without declaring it, as {"properties": ...} does, provided the side producing
+// values (the target for inputs, the candidate for outputs) admits t: a schema that
+// declares no type still constrains the objects, arrays, ... it accepts.
+func constrains(tgt, cand map[string]any, isInput bool, t string) bool {
+	if hasTypeFamily(tgt, t) || hasTypeFamily(cand, t) {
+		return true
+	}
+	producer := cand
+	if isInput {
+		producer = tgt
+	}
+	if types := effectiveTypeSet(producer); types != nil && !hasFamily(types, t) {
 		return false
 	}
-	_, ok := set[t]
-	return ok
+	for _, k := range typeKeywords[t] {
+		if hasKey(tgt, k) || hasKey(cand, k) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasTypeFamily reports whether schema declares type t, with "number" also
+// matching "integer".
+func hasTypeFamily(schema map[string]any, t string) bool {
+	types := typeSet(schema)
+	return types != nil && hasFamily(types, t)
+}
+
+func hasFamily(types map[string]struct{}, t string) bool {
+	if _, ok := types[t]; ok {
+		return true
+	}
+	if t == "number" {
+		_, ok := types["integer"]
+		return ok
+	}
+	return false
 }
 
 func hasUnion(schema map[string]any) bool {
@@ -328,13 +662,26 @@ func enumSet(schema map[string]any) (map[string]struct{}, bool) {
 	return set, true
 }
 
-func compatObject(tgt, cand map[string]any, isInput bool) (bool, string) {
+func (c *comparer) compatObject(tgt, cand map[string]any, isInput bool) (bool, string) {
 	tgtReq := stringSet(tgt["required"])
 	candReq := stringSet(cand["required"])
 
 	tgtProps, _ := asMap(tgt["properties"])
 	candProps, _ := asMap(cand["properties"])
 
+	tgtPatternList, err := patternSchemas(tgt)
+	if err != nil {
+		return false, err.Error()
+	}
+	candPatterns, err := patternSchemas(cand)
+	if err != nil {
+		return false, err.Error()
+	}
+	tgtPatterns := make(map[string]map[string]any, len(tgtPatternList))
+	for _, tp := range tgtPatternList {
+		tgtPatterns[tp.pattern] = tp.schema
+	}
+
 	if isInput {
 		// required(cand) <= required(tgt)
 		for k := range candReq {
@@ -353,7 +700,7 @@ func compatObject(tgt, cand map[string]any, isInput bool) (bool, string) {
 				if !ok {
 					continue
 				}
-				ok2, reason, err := compat(tvm, cvm, true)
+				ok2, reason, err := c.compat(tvm, cvm, true)
 				if err != nil {
 					// Wrap error as reason (should not happen in practice).
 					return false, fmt.Sprintf("properties[%q]: error: %v", p, err)
@@ -364,6 +711,31 @@ func compatObject(tgt, cand map[string]any, isInput bool) (bool, string) {
 			}
 			// If cand lacks property schema, treated as unconstrained (compatible).
 		}
+		// patternProperties: cand must accept what tgt accepts for keys its patterns match.
+		for _, cp := range candPatterns {
+			if tv, ok := tgtPatterns[cp.pattern]; ok {
+				ok2, reason, err := c.compat(tv, cp.schema, true)
+				if err != nil {
+					return false, fmt.Sprintf("patternProperties[%q]: error: %v", cp.pattern, err)
+				}
+				if !ok2 {
+					return false, fmt.Sprintf("patternProperties[%q]: %s", cp.pattern, reason)
+				}
+			}
+			for _, p := range sortedKeys(tgtProps) {
+				tvm, ok := asMap(tgtProps[p])
+				if !ok || !cp.re.MatchString(p) {
+					continue
+				}
+				ok2, reason, err := c.compat(tvm, cp.schema, true)
+				if err != nil {
+					return false, fmt.Sprintf("properties[%q]: error: %v", p, err)
+				}
+				if !ok2 {
+					return false, fmt.Sprintf("properties[%q]: patternProperties[%q]: %s", p, cp.pattern, reason)
+				}
+			}
+		}
 		// additionalProperties does not restrict input compatibility in v0.1.
 		return true, ""
 	}
@@ -380,8 +752,28 @@ func compatObject(tgt, cand map[string]any, isInput bool) (bool, string) {
 
 	// For each property p in properties(cand):
 	for p, cv := range candProps {
-		// If p not in properties(tgt), then additionalProperties(tgt) MUST NOT be false.
-		if _, ok := tgtProps[p]; !ok {
+		// Every target pattern matching p constrains it, whether or not p is a target property.
+		matched := false
+		for _, tp := range tgtPatternList {
+			if !tp.re.MatchString(p) {
+				continue
+			}
+			matched = true
+			cvm, ok := asMap(cv)
+			if !ok {
+				continue
+			}
+			ok2, reason, err := c.compat(tp.schema, cvm, false)
+			if err != nil {
+				return false, fmt.Sprintf("properties[%q]: error: %v", p, err)
+			}
+			if !ok2 {
+				return false, fmt.Sprintf("properties[%q]: patternProperties[%q]: %s", p, tp.pattern, reason)
+			}
+		}
+		// If p is in neither properties(tgt) nor matched by patternProperties(tgt),
+		// then additionalProperties(tgt) MUST NOT be false.
+		if _, ok := tgtProps[p]; !ok && !matched {
 			if b, ok := tgtAP.(bool); ok && b == false {
 				return false, fmt.Sprintf("properties[%q]: target forbids additional properties", p)
 			}
@@ -396,7 +788,7 @@ func compatObject(tgt, cand map[string]any, isInput bool) (bool, string) {
 			if !ok {
 				continue
 			}
-			ok2, reason, err := compat(tvm, cvm, false)
+			ok2, reason, err := c.compat(tvm, cvm, false)
 			if err != nil {
 				return false, fmt.Sprintf("properties[%q]: error: %v", p, err)
 			}
@@ -406,6 +798,40 @@ func compatObject(tgt, cand map[string]any, isInput bool) (bool, string) {
 		}
 	}
 
+	// patternProperties(cand): keys matching a candidate pattern may be returned with
+	// that pattern's schema. Regex containment is not decided, so the target must
+	// have the identical pattern, or no patterns at all and an additionalProperties
+	// that admits the pattern's schema.
+	for _, cp := range candPatterns {
+		if tv, ok := tgtPatterns[cp.pattern]; ok {
+			ok2, reason, err := c.compat(tv, cp.schema, false)
+			if err != nil {
+				return false, fmt.Sprintf("patternProperties[%q]: error: %v", cp.pattern, err)
+			}
+			if !ok2 {
+				return false, fmt.Sprintf("patternProperties[%q]: %s", cp.pattern, reason)
+			}
+			continue
+		}
+		if len(tgtPatternList) > 0 {
+			return false, fmt.Sprintf("patternProperties[%q]: target has no identical pattern", cp.pattern)
+		}
+		switch apTgt := tgtAP.(type) {
+		case bool:
+			if !apTgt {
+				return false, fmt.Sprintf("patternProperties[%q]: target forbids additional properties", cp.pattern)
+			}
+		case map[string]any:
+			ok2, reason, err := c.compat(apTgt, cp.schema, false)
+			if err != nil {
+				return false, fmt.Sprintf("patternProperties[%q]: error: %v", cp.pattern, err)
+			}
+			if !ok2 {
+				return false, fmt.Sprintf("patternProperties[%q]: additionalProperties: %s", cp.pattern, reason)
+			}
+		}
+	}
+
 	// additionalProperties constraint:
 	switch apTgt := tgtAP.(type) {
 	case bool:
@@ -421,7 +847,7 @@ func compatObject(tgt, cand map[string]any, isInput bool) (bool, string) {
 		}
 	case map[string]any:
 		if apCand, ok := cand["additionalProperties"].(map[string]any); ok {
-			ok2, reason, err := compat(apTgt, apCand, false)
+			ok2, reason, err := c.compat(apTgt, apCand, false)
 			if err != nil {
 				return false, fmt.Sprintf("additionalProperties: error: %v", err)
 			}
@@ -440,7 +866,21 @@ func compatObject(tgt, cand map[string]any, isInput bool) (bool, string) {
 	return true, ""
 }
 
-func compatArray(tgt, cand map[string]any, isInput bool) (bool, string) {
+func (c *comparer) compatArray(tgt, cand map[string]any, isInput bool) (bool, string) {
+	// Positional rules: compare each position covered by either side's prefixItems.
+	// Past the end of its prefix, a side constrains positions with items (or Top).
+	tgtPrefix, _ := asSlice(tgt["prefixItems"])
+	candPrefix, _ := asSlice(cand["prefixItems"])
+	for idx := 0; idx < max(len(tgtPrefix), len(candPrefix)); idx++ {
+		ok, reason, err := c.compat(itemSchemaAt(tgt, tgtPrefix, idx), itemSchemaAt(cand, candPrefix, idx), isInput)
+		if err != nil {
+			return false, fmt.Sprintf("prefixItems[%d]: error: %v", idx, err)
+		}
+		if !ok {
+			return false, fmt.Sprintf("prefixItems[%d]: %s", idx, reason)
+		}
+	}
+
 	tv, okTgt := asMap(tgt["items"])
 	cv, okCand := asMap(cand["items"])
 	if !okTgt || !okCand {
@@ -452,7 +892,7 @@ func compatArray(tgt, cand map[string]any, isInput bool) (bool, string) {
 			cv = map[string]any{}
 		}
 	}
-	ok, reason, err := compat(tv, cv, isInput)
+	ok, reason, err := c.compat(tv, cv, isInput)
 	if err != nil {
 		return false, fmt.Sprintf("items: error: %v", err)
 	}
@@ -462,7 +902,21 @@ func compatArray(tgt, cand map[string]any, isInput bool) (bool, string) {
 	return true, ""
 }
 
-func compatUnion(tgt, cand map[string]any, isInput bool) (bool, string) {
+// itemSchemaAt returns the schema that constrains array position idx.
+func itemSchemaAt(schema map[string]any, prefix []any, idx int) map[string]any {
+	if idx < len(prefix) {
+		if m, ok := asMap(prefix[idx]); ok {
+			return m
+		}
+		return map[string]any{}
+	}
+	if m, ok := asMap(schema["items"]); ok {
+		return m
+	}
+	return map[string]any{}
+}
+
+func (c *comparer) compatUnion(tgt, cand map[string]any, isInput bool) (bool, string) {
 	tgtVars, okTgt := unionVariants(tgt)
 	candVars, okCand := unionVariants(cand)
 	if !okTgt || !okCand {
@@ -483,7 +937,7 @@ func compatUnion(tgt, cand map[string]any, isInput bool) (bool, string) {
 		for i, v := range tgtVars {
 			found := false
 			for _, w := range candVars {
-				ok, _, err := compat(v, w, true)
+				ok, _, err := c.compat(v, w, true)
 				if err != nil {
 					return false, fmt.Sprintf("%s: error: %v", unionKey, err)
 				}
@@ -504,7 +958,7 @@ func compatUnion(tgt, cand map[string]any, isInput bool) (bool, string) {
 	for i, w := range candVars {
 		found := false
 		for _, v := range tgtVars {
-			ok, _, err := compat(v, w, false)
+			ok, _, err := c.compat(v, w, false)
 			if err != nil {
 				return false, fmt.Sprintf("%s: error: %v", unionKey, err)
 			}
@@ -575,23 +1029,36 @@ func equalJSONValue(a, b any) bool {
 }
 
 // compatNumericBounds checks minimum/maximum/exclusiveMinimum/exclusiveMaximum rules.
-func compatNumericBounds(tgt, cand map[string]any, isInput bool) (bool, string) {
+// Bounds are compared as exact rationals, so json.Number bounds beyond float64
+// precision keep their order.
+func (c *comparer) compatNumericBounds(tgt, cand map[string]any, isInput bool) (bool, string) {
 	// Lower bounds: minimum / exclusiveMinimum
 	tgtLo, tgtLoExcl := effectiveLowerBound(tgt)
 	candLo, candLoExcl := effectiveLowerBound(cand)
 	tgtHi, tgtHiExcl := effectiveUpperBound(tgt)
 	candHi, candHiExcl := effectiveUpperBound(cand)
+	// Over the integers an exclusive bound is an inclusive one a step inward, so
+	// {exclusiveMaximum: 5} and {maximum: 4} admit the same values. Convert only
+	// when both sides admit nothing but integers.
+	if onlyIntegers(tgt) && onlyIntegers(cand) {
+		tgtLo, tgtLoExcl = integerLowerBound(tgtLo, tgtLoExcl), false
+		candLo, candLoExcl = integerLowerBound(candLo, candLoExcl), false
+		tgtHi, tgtHiExcl = integerUpperBound(tgtHi, tgtHiExcl), false
+		candHi, candHiExcl = integerUpperBound(candHi, candHiExcl), false
+	}
+	candLo = c.snap(candLo, tgtLo)
+	candHi = c.snap(candHi, tgtHi)
 
 	tgtHasLo := hasKey(tgt, "minimum") || hasKey(tgt, "exclusiveMinimum")
 	tgtHasHi := hasKey(tgt, "maximum") || hasKey(tgt, "exclusiveMaximum")
 	candHasLo := hasKey(cand, "minimum") || hasKey(cand, "exclusiveMinimum")
 	candHasHi := hasKey(cand, "maximum") || hasKey(cand, "exclusiveMaximum")
 
-	fmtBound := func(v float64, excl bool) string {
+	fmtBound := func(v *big.Rat, excl bool) string {
 		if excl {
-			return fmt.Sprintf("exclusive %g", v)
+			return "exclusive " + ratString(v)
 		}
-		return fmt.Sprintf("%g", v)
+		return ratString(v)
 	}
 
 	if isInput {
@@ -630,46 +1097,136 @@ func compatNumericBounds(tgt, cand map[string]any, isInput bool) (bool, string)
 	return true, ""
 }
 
+// compatMultipleOf checks multipleOf rules with exact rational arithmetic, up to
+// the numeric tolerance:
+//   - input:  the candidate's multipleOf must divide the target's, so every value the
+//     target sends is accepted.
+//   - output: the candidate's multipleOf must be a multiple of the target's, so every
+//     value the candidate returns is allowed.
+func (c *comparer) compatMultipleOf(tgt, cand map[string]any, isInput bool) (bool, string) {
+	tv, tgtHas := tgt["multipleOf"]
+	cv, candHas := cand["multipleOf"]
+	tr, _ := positiveRat(tv)
+	cr, _ := positiveRat(cv)
+	if isInput {
+		if !candHas {
+			return true, ""
+		}
+		if !tgtHas || tr == nil || cr == nil {
+			return false, fmt.Sprintf("multipleOf: candidate requires multipleOf %s but target does not", canonicalKey(cv))
+		}
+		if !c.isMultiple(tr, cr) {
+			return false, fmt.Sprintf("multipleOf: candidate multipleOf %s does not divide target multipleOf %s", canonicalKey(cv), canonicalKey(tv))
+		}
+		return true, ""
+	}
+
+	if !tgtHas {
+		return true, ""
+	}
+	if !candHas || tr == nil || cr == nil {
+		return false, fmt.Sprintf("multipleOf: target has multipleOf %s but candidate has none", canonicalKey(tv))
+	}
+	if !c.isMultiple(cr, tr) {
+		return false, fmt.Sprintf("multipleOf: candidate multipleOf %s is not a multiple of target multipleOf %s", canonicalKey(cv), canonicalKey(tv))
+	}
+	return true, ""
+}
+
+// EffectiveBounds returns the numeric bounds a schema places on its values, taking
+// the tighter of minimum and exclusiveMinimum (and of maximum and exclusiveMaximum);
+// of two equal bounds the exclusive one wins. lo or hi is nil if the schema sets no
+// bound on that side, and loExcl and hiExcl report whether the bound excludes its
+// value. Pass a normalized schema, so allOf branches are already merged; bounds are
+// read as written and are not rounded to integers for integer schemas.
+func EffectiveBounds(schema map[string]any) (lo, hi *float64, loExcl, hiExcl bool) {
+	if hasKey(schema, "minimum") || hasKey(schema, "exclusiveMinimum") {
+		r, excl := effectiveLowerBound(schema)
+		f, _ := r.Float64()
+		lo, loExcl = &f, excl
+	}
+	if hasKey(schema, "maximum") || hasKey(schema, "exclusiveMaximum") {
+		r, excl := effectiveUpperBound(schema)
+		f, _ := r.Float64()
+		hi, hiExcl = &f, excl
+	}
+	return lo, hi, loExcl, hiExcl
+}
+
 // effectiveLowerBound returns the effective lower bound value and whether it's exclusive.
-func effectiveLowerBound(schema map[string]any) (float64, bool) {
+func effectiveLowerBound(schema map[string]any) (*big.Rat, bool) {
 	min, hasMin := schema["minimum"]
 	eMin, hasEMin := schema["exclusiveMinimum"]
 	if hasMin && hasEMin {
-		mv := toFloat64(min)
-		ev := toFloat64(eMin)
-		if ev >= mv {
+		mv := numRat(min)
+		ev := numRat(eMin)
+		if ev.Cmp(mv) >= 0 {
 			return ev, true
 		}
 		return mv, false
 	}
 	if hasEMin {
-		return toFloat64(eMin), true
+		return numRat(eMin), true
 	}
 	if hasMin {
-		return toFloat64(min), false
+		return numRat(min), false
 	}
-	return 0, false
+	return new(big.Rat), false
 }
 
 // effectiveUpperBound returns the effective upper bound value and whether it's exclusive.
-func effectiveUpperBound(schema map[string]any) (float64, bool) {
+func effectiveUpperBound(schema map[string]any) (*big.Rat, bool) {
 	max, hasMax := schema["maximum"]
 	eMax, hasEMax := schema["exclusiveMaximum"]
 	if hasMax && hasEMax {
-		mv := toFloat64(max)
-		ev := toFloat64(eMax)
-		if ev <= mv {
+		mv := numRat(max)
+		ev := numRat(eMax)
+		if ev.Cmp(mv) <= 0 {
 			return ev, true
 		}
 		return mv, false
 	}
 	if hasEMax {
-		return toFloat64(eMax), true
+		return numRat(eMax), true
 	}
 	if hasMax {
-		return toFloat64(max), false
+		return numRat(max), false
+	}
+	return new(big.Rat), false
+}
+
+// onlyIntegers reports whether schema's type set is exactly {"integer"}.
+func onlyIntegers(schema map[string]any) bool {
+	types := typeSet(schema)
+	_, ok := types["integer"]
+	return ok && len(types) == 1
+}
+
+// integerLowerBound returns the least integer satisfying lower bound r.
+func integerLowerBound(r *big.Rat, excl bool) *big.Rat {
+	if excl {
+		return new(big.Rat).SetInt(new(big.Int).Add(floorRat(r), big.NewInt(1)))
 	}
-	return 0, false
+	return new(big.Rat).SetInt(ceilRat(r))
+}
+
+// integerUpperBound returns the greatest integer satisfying upper bound r.
+func integerUpperBound(r *big.Rat, excl bool) *big.Rat {
+	if excl {
+		return new(big.Rat).SetInt(new(big.Int).Sub(ceilRat(r), big.NewInt(1)))
+	}
+	return new(big.Rat).SetInt(floorRat(r))
+}
+
+// floorRat returns the greatest integer <= r. Denominators are positive, so
+// Euclidean division rounds toward negative infinity.
+func floorRat(r *big.Rat) *big.Int {
+	return new(big.Int).Div(r.Num(), r.Denom())
+}
+
+// ceilRat returns the least integer >= r.
+func ceilRat(r *big.Rat) *big.Int {
+	return new(big.Int).Neg(floorRat(new(big.Rat).Neg(r)))
 }
 
 // Lower bound comparisons:
@@ -678,11 +1235,11 @@ func effectiveUpperBound(schema map[string]any) (float64, bool) {
 // So at equal values: exclusive > non-exclusive.
 
 // lowerBoundLessOrEqual returns true if lower bound a <= lower bound b.
-func lowerBoundLessOrEqual(a float64, aExcl bool, b float64, bExcl bool) bool {
-	if a < b {
+func lowerBoundLessOrEqual(a *big.Rat, aExcl bool, b *big.Rat, bExcl bool) bool {
+	switch a.Cmp(b) {
+	case -1:
 		return true
-	}
-	if a > b {
+	case 1:
 		return false
 	}
 	// Equal values: exclusive is stricter (higher)
@@ -693,11 +1250,11 @@ func lowerBoundLessOrEqual(a float64, aExcl bool, b float64, bExcl bool) bool {
 }
 
 // lowerBoundGreaterOrEqual returns true if lower bound a >= lower bound b.
-func lowerBoundGreaterOrEqual(a float64, aExcl bool, b float64, bExcl bool) bool {
-	if a > b {
+func lowerBoundGreaterOrEqual(a *big.Rat, aExcl bool, b *big.Rat, bExcl bool) bool {
+	switch a.Cmp(b) {
+	case 1:
 		return true
-	}
-	if a < b {
+	case -1:
 		return false
 	}
 	// Equal values: exclusive is stricter (higher)
@@ -713,11 +1270,11 @@ func lowerBoundGreaterOrEqual(a float64, aExcl bool, b float64, bExcl bool) bool
 // So at equal values: exclusive < non-exclusive.
 
 // upperBoundLessOrEqual returns true if upper bound a <= upper bound b.
-func upperBoundLessOrEqual(a float64, aExcl bool, b float64, bExcl bool) bool {
-	if a < b {
+func upperBoundLessOrEqual(a *big.Rat, aExcl bool, b *big.Rat, bExcl bool) bool {
+	switch a.Cmp(b) {
+	case -1:
 		return true
-	}
-	if a > b {
+	case 1:
 		return false
 	}
 	// Equal values: exclusive is stricter (lower)
@@ -728,11 +1285,11 @@ func upperBoundLessOrEqual(a float64, aExcl bool, b float64, bExcl bool) bool {
 }
 
 // upperBoundGreaterOrEqual returns true if upper bound a >= upper bound b.
-func upperBoundGreaterOrEqual(a float64, aExcl bool, b float64, bExcl bool) bool {
-	if a > b {
+func upperBoundGreaterOrEqual(a *big.Rat, aExcl bool, b *big.Rat, bExcl bool) bool {
+	switch a.Cmp(b) {
+	case 1:
 		return true
-	}
-	if a < b {
+	case -1:
 		return false
 	}
 	// Equal values: exclusive is stricter (lower)
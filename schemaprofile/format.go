@@ -0,0 +1,72 @@
+package schemaprofile
+
+import "fmt"
+
+// DefaultFormatSubsets is the format hierarchy used when Normalizer.FormatSubsets is
+// nil. Each format maps to the formats that accept all of its values: an ASCII email
+// or hostname is also a valid internationalized one, a URI is also an IRI, and an
+// absolute reference is also a relative-or-absolute one.
+var DefaultFormatSubsets = map[string][]string{
+	"email":         {"idn-email"},
+	"hostname":      {"idn-hostname"},
+	"uri":           {"iri", "uri-reference"},
+	"uri-reference": {"iri-reference"},
+	"iri":           {"iri-reference"},
+}
+
+func (n *Normalizer) formatSubsets() map[string][]string {
+	if n.FormatSubsets != nil {
+		return n.FormatSubsets
+	}
+	return DefaultFormatSubsets
+}
+
+// formatWithin reports whether every value of format narrow is also a value of
+// format wide: the formats are equal, or wide is reachable from narrow in subsets.
+func formatWithin(subsets map[string][]string, narrow, wide string) bool {
+	seen := map[string]bool{}
+	queue := []string{narrow}
+	for len(queue) > 0 {
+		f := queue[0]
+		queue = queue[1:]
+		if f == wide {
+			return true
+		}
+		if seen[f] {
+			continue
+		}
+		seen[f] = true
+		queue = append(queue, subsets[f]...)
+	}
+	return false
+}
+
+// compatFormat checks the format keyword when it is treated as a constraint. The
+// side that must be narrower (the candidate for outputs, the target for inputs) has
+// to declare a format within the other side's; an absent format is the widest.
+func (c *comparer) compatFormat(tgt, cand map[string]any, isInput bool) (bool, string) {
+	tf, tgtHas := tgt["format"].(string)
+	cf, candHas := cand["format"].(string)
+	if isInput {
+		if !candHas {
+			return true, ""
+		}
+		if !tgtHas {
+			return false, fmt.Sprintf("format: candidate requires %q but target does not", cf)
+		}
+		if !formatWithin(c.formatSubsets, tf, cf) {
+			return false, fmt.Sprintf("format: target %q is not within candidate %q", tf, cf)
+		}
+		return true, ""
+	}
+	if !tgtHas {
+		return true, ""
+	}
+	if !candHas {
+		return false, fmt.Sprintf("format: target requires %q but candidate does not declare a format", tf)
+	}
+	if !formatWithin(c.formatSubsets, cf, tf) {
+		return false, fmt.Sprintf("format: candidate %q is not within target %q", cf, tf)
+	}
+	return true, ""
+}
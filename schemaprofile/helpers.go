@@ -5,13 +5,20 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
+	"math/big"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
 )
 
-func assertProfileKeywords(schema map[string]any, path string) error {
-	for k := range schema {
+// profileKeywords applies the OnOutsideProfile policy to the keywords of schema. It
+// returns schema itself when every keyword is in the profile, and otherwise an error
+// or, when the policy continues, a copy without the out-of-profile keywords.
+func (n *Normalizer) profileKeywords(refs *refStack, schema map[string]any, path string) (map[string]any, error) {
+	var out map[string]any
+	for _, k := range sortedKeys(schema) {
 		if _, ok := inScopeKeywords[k]; ok {
 			continue
 		}
@@ -21,9 +28,23 @@ func assertProfileKeywords(schema map[string]any, path string) error {
 		if strings.HasPrefix(k, "x-") {
 			continue
 		}
-		return &OutsideProfileError{Path: pathOrRoot(path), Keyword: k}
+		switch n.OnOutsideProfile {
+		case StripAndContinue, CollectAndContinue:
+		default:
+			return nil, &OutsideProfileError{Path: pathOrRoot(path), Keyword: k}
+		}
+		if n.OnOutsideProfile == CollectAndContinue {
+			refs.outside = append(refs.outside, OutsideProfileError{Path: pathOrRoot(path), Keyword: k})
+		}
+		if out == nil {
+			out = cloneMap(schema)
+		}
+		delete(out, k)
+	}
+	if out == nil {
+		return schema, nil
 	}
-	return nil
+	return out, nil
 }
 
 // applyNullable converts OpenAPI 3.0 "nullable: true" to a JSON Schema type
@@ -69,6 +90,24 @@ func applyNullable(schema map[string]any) map[string]any {
 	return out
 }
 
+// applyTupleItems rewrites the pre-2020-12 tuple form, where items is an array of
+// per-position schemas, to prefixItems. Without additionalItems (outside the profile)
+// positions past the tuple are unconstrained in that form, which is also what
+// prefixItems alone means.
+func applyTupleItems(schema map[string]any, path string) (map[string]any, error) {
+	arr, ok := asSlice(schema["items"])
+	if !ok {
+		return schema, nil
+	}
+	if _, ok := schema["prefixItems"]; ok {
+		return nil, fmt.Errorf("%s.items: array form cannot be combined with prefixItems", pathOrRoot(path))
+	}
+	out := cloneMap(schema)
+	delete(out, "items")
+	out["prefixItems"] = arr
+	return out, nil
+}
+
 func pathOrRoot(path string) string {
 	if path == "" {
 		return "<root>"
@@ -125,6 +164,26 @@ func normalizeStringSet(v any) ([]any, error) {
 	return out, nil
 }
 
+// normalizeDependentRequired normalizes each dependency list of a dependentRequired
+// value as a string set and drops the entries whose list is empty.
+func normalizeDependentRequired(v any) (map[string]any, error) {
+	deps, ok := asMap(v)
+	if !ok {
+		return nil, errors.New("must be object")
+	}
+	out := make(map[string]any, len(deps))
+	for k, list := range deps {
+		req, err := normalizeStringSet(list)
+		if err != nil {
+			return nil, fmt.Errorf("[%q]: %w", k, err)
+		}
+		if len(req) > 0 {
+			out[k] = req
+		}
+	}
+	return out, nil
+}
+
 func resolveJSONPointer(doc any, fragment string) (any, error) {
 	// fragment is the part after '#'. JSON Pointer starts with '/'.
 	if fragment == "" {
@@ -177,6 +236,38 @@ func asMap(v any) (map[string]any, bool) {
 	return m, ok
 }
 
+// asSchema converts a resolved $ref target into schema object form. The boolean
+// schemas true and false are mapped to Top ({}) and Bottom (see bottomSchema).
+func asSchema(v any) (map[string]any, bool) {
+	switch x := v.(type) {
+	case map[string]any:
+		return x, true
+	case bool:
+		if x {
+			return map[string]any{}, true
+		}
+		return bottomSchema(), true
+	default:
+		return nil, false
+	}
+}
+
+// bottomSchema returns the object form of the boolean schema false. An empty
+// enum admits no value, and it stays within the profile keyword set.
+func bottomSchema() map[string]any {
+	return map[string]any{"enum": []any{}}
+}
+
+// isBottom reports whether a normalized schema admits no value.
+func isBottom(schema map[string]any) bool {
+	v, ok := schema["enum"]
+	if !ok {
+		return false
+	}
+	arr, ok := asSlice(v)
+	return ok && len(arr) == 0
+}
+
 func asSlice(v any) ([]any, bool) {
 	s, ok := v.([]any)
 	return s, ok
@@ -229,3 +320,122 @@ func toFloat64(v any) float64 {
 		return 0
 	}
 }
+
+// toRat converts a JSON numeric value to an exact rational. A float64 is read through
+// its shortest decimal form, so 0.01 becomes 1/100 rather than the nearest binary
+// fraction. Returns false for non-numeric values.
+func toRat(v any) (*big.Rat, bool) {
+	var s string
+	switch x := v.(type) {
+	case float64:
+		if math.IsNaN(x) || math.IsInf(x, 0) {
+			return nil, false
+		}
+		s = strconv.FormatFloat(x, 'g', -1, 64)
+	case int:
+		s = strconv.Itoa(x)
+	case int64:
+		s = strconv.FormatInt(x, 10)
+	case json.Number:
+		s = x.String()
+	default:
+		return nil, false
+	}
+	return new(big.Rat).SetString(s)
+}
+
+// numRat is toRat for values already known to be numeric, returning zero otherwise,
+// like toFloat64.
+func numRat(v any) *big.Rat {
+	if r, ok := toRat(v); ok {
+		return r
+	}
+	return new(big.Rat)
+}
+
+// ratNumber returns r as a json.Number written exactly in decimal. Values derived
+// from decimal inputs, such as the lcm of two multipleOf values, always have a
+// finite decimal expansion; any other r falls back to the nearest float64.
+func ratNumber(r *big.Rat) any {
+	den := new(big.Int).Set(r.Denom())
+	digits := 0
+	for _, p := range []int64{2, 5} {
+		prime, mod := big.NewInt(p), new(big.Int)
+		n := 0
+		for {
+			q, m := new(big.Int).QuoRem(den, prime, mod)
+			if m.Sign() != 0 {
+				break
+			}
+			den, n = q, n+1
+		}
+		digits = max(digits, n)
+	}
+	if !den.IsInt64() || den.Int64() != 1 {
+		f, _ := r.Float64()
+		return f
+	}
+	return json.Number(r.FloatString(digits))
+}
+
+// ratString formats r for messages: exactly if it is an integer, else as the
+// nearest float64.
+func ratString(r *big.Rat) string {
+	if r.IsInt() {
+		return r.Num().String()
+	}
+	f, _ := r.Float64()
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// positiveRat is toRat restricted to values greater than zero, the valid range of multipleOf.
+func positiveRat(v any) (*big.Rat, bool) {
+	r, ok := toRat(v)
+	if !ok || r.Sign() <= 0 {
+		return nil, false
+	}
+	return r, true
+}
+
+// lcmRat returns the least common multiple of two positive rationals:
+// lcm(a/b, c/d) = lcm(a, c) / gcd(b, d) with both fractions in lowest terms.
+func lcmRat(x, y *big.Rat) *big.Rat {
+	a, b := x.Num(), x.Denom()
+	c, d := y.Num(), y.Denom()
+	g := new(big.Int).GCD(nil, nil, a, c)
+	num := new(big.Int).Mul(a, new(big.Int).Quo(c, g))
+	den := new(big.Int).GCD(nil, nil, b, d)
+	return new(big.Rat).SetFrac(num, den)
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// patternSchema is one patternProperties entry with its compiled pattern.
+type patternSchema struct {
+	pattern string
+	re      *regexp.Regexp
+	schema  map[string]any
+}
+
+// patternSchemas returns the patternProperties of a normalized schema sorted by
+// pattern. Patterns are validated during normalization.
+func patternSchemas(schema map[string]any) ([]patternSchema, error) {
+	pp, _ := asMap(schema["patternProperties"])
+	out := make([]patternSchema, 0, len(pp))
+	for _, k := range sortedKeys(pp) {
+		re, err := regexp.Compile(k)
+		if err != nil {
+			return nil, fmt.Errorf("patternProperties[%q]: invalid pattern: %w", k, err)
+		}
+		vm, _ := asSchema(pp[k])
+		out = append(out, patternSchema{pattern: k, re: re, schema: vm})
+	}
+	return out, nil
+}
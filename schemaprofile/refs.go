@@ -0,0 +1,208 @@
+package schemaprofile
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+)
+
+// refStack tracks $ref resolution within a single call: the references being
+// resolved, to detect cycles, and the base URI in effect for relative references,
+// which $id and followed references change. Each public method creates its own and
+// threads it through normalization, so concurrent calls never share one. It also
+// carries the call's context, which normalization checks at every level.
+type refStack struct {
+	ctx    context.Context
+	active map[string]bool
+	// depth counts the normalizeAt calls in progress, for Normalizer.MaxDepth.
+	depth int
+	// base is the base URI of the schema resource being normalized; nil means
+	// Root itself, whose base is Normalizer.Base.
+	base *url.URL
+	// ids indexes the $id resources embedded in Root by absolute URI; built on first use.
+	ids map[string]any
+	// outside records the keywords dropped under CollectAndContinue.
+	outside []OutsideProfileError
+}
+
+func newRefStack(ctx context.Context) *refStack {
+	return &refStack{ctx: ctx, active: map[string]bool{}}
+}
+
+// refTarget is a $ref resolved against the base in effect.
+type refTarget struct {
+	u *url.URL
+	// key identifies the target within a call, for cycle detection and caching.
+	key string
+	// doc is the base URI of the resource holding the target; nil for Root.
+	doc *url.URL
+}
+
+// enterID switches the base to the $id of the schema being normalized and returns
+// a function restoring the previous base.
+func (n *Normalizer) enterID(refs *refStack, id string, path string) (func(), error) {
+	u, err := url.Parse(id)
+	if err != nil {
+		return nil, &RefError{Path: pathOrRoot(path), Ref: id, Err: err}
+	}
+	if base := n.baseOf(refs); base != nil {
+		u = base.ResolveReference(u)
+	} else if !u.IsAbs() {
+		return nil, &RefError{Path: pathOrRoot(path), Ref: id, Err: errors.New("relative $id with no base")}
+	}
+	u.Fragment = ""
+	prev := refs.base
+	refs.base = u
+	return func() { refs.base = prev }, nil
+}
+
+func (n *Normalizer) baseOf(refs *refStack) *url.URL {
+	if refs.base != nil {
+		return refs.base
+	}
+	return n.Base
+}
+
+// targetOf resolves ref against the base in effect.
+func (n *Normalizer) targetOf(refs *refStack, ref string, path string) (refTarget, error) {
+	if n.noRoot {
+		return refTarget{}, &RefError{Path: pathOrRoot(path), Ref: ref, Err: errors.New("no document to resolve against; use a Normalizer with Root set")}
+	}
+	u, err := url.Parse(ref)
+	if err != nil {
+		return refTarget{}, &RefError{Path: pathOrRoot(path), Ref: ref, Err: err}
+	}
+
+	// A fragment-only ref points into the current resource: Root, unless an $id
+	// or a followed reference has moved the base elsewhere.
+	if u.Scheme == "" && u.Host == "" && u.Path == "" && (u.Fragment != "" || strings.HasPrefix(ref, "#")) {
+		if refs.base == nil {
+			return refTarget{u: u, key: u.String()}, nil
+		}
+		abs := *refs.base
+		abs.Fragment = u.Fragment
+		return refTarget{u: &abs, key: abs.String(), doc: refs.base}, nil
+	}
+
+	// Resolve against base if needed.
+	if !u.IsAbs() && u.Path != "" {
+		base := n.baseOf(refs)
+		if base == nil {
+			return refTarget{}, &RefError{Path: pathOrRoot(path), Ref: ref, Err: errors.New("relative $ref with no base")}
+		}
+		u = base.ResolveReference(u)
+	}
+	doc := *u
+	doc.Fragment = ""
+	t := refTarget{u: u, key: u.String(), doc: &doc}
+	if n.Base != nil {
+		root := *n.Base
+		root.Fragment = ""
+		if doc.String() == root.String() {
+			// Root's own URI: resolve within Root, keyed like a fragment-only ref.
+			t.key = "#" + u.Fragment
+			t.doc = nil
+		}
+	}
+	return t, nil
+}
+
+// resolveRef resolves a $ref and returns the resolved value plus a cleanup function.
+// The cleanup function MUST be called when the caller is done normalizing the resolved schema,
+// to remove the ref from the cycle-detection stack. This ensures that recursive $refs
+// within the resolved schema are properly detected as cycles.
+func (n *Normalizer) resolveRef(refs *refStack, ref string, t refTarget, path string) (any, func(), error) {
+	noop := func() {}
+
+	// Cycle detection: if this ref is already being resolved on the current stack, it's a cycle.
+	if refs.active[t.key] {
+		return nil, noop, &RefError{Path: pathOrRoot(path), Ref: ref, Err: errors.New("cycle detected")}
+	}
+
+	refs.active[t.key] = true
+	cleanup := func() { delete(refs.active, t.key) }
+
+	doc, err := n.document(refs, t)
+	if err != nil {
+		cleanup()
+		return nil, noop, &RefError{Path: pathOrRoot(path), Ref: ref, Err: err}
+	}
+
+	v, err := resolveJSONPointer(doc, t.u.Fragment)
+	if err != nil {
+		cleanup()
+		return nil, noop, &RefError{Path: pathOrRoot(path), Ref: ref, Err: err}
+	}
+
+	return v, cleanup, nil
+}
+
+// document returns the schema resource holding t: Root, an $id resource embedded
+// in Root, or an external document from Fetch, which receives the full reference.
+func (n *Normalizer) document(refs *refStack, t refTarget) (any, error) {
+	if t.doc == nil {
+		return n.Root, nil
+	}
+	if refs.ids == nil {
+		refs.ids = map[string]any{}
+		indexIDs(refs.ids, n.Root, n.Base)
+	}
+	if doc, ok := refs.ids[t.doc.String()]; ok {
+		return doc, nil
+	}
+
+	// External document (optional).
+	if n.DisallowExternalRefs {
+		return nil, errors.New("external $ref disallowed")
+	}
+	if n.Fetch == nil {
+		return nil, errors.New("external $ref unsupported (no fetcher)")
+	}
+	fetched, err := n.Fetch.Fetch(t.u)
+	if err != nil {
+		return nil, err
+	}
+	return decodeJSON(fetched)
+}
+
+// valueKeywords hold instance values rather than schemas, so an "$id" inside them
+// does not declare a resource.
+var valueKeywords = map[string]struct{}{
+	"const":    {},
+	"enum":     {},
+	"default":  {},
+	"examples": {},
+}
+
+// indexIDs records every object in v that declares an $id, keyed by the absolute
+// URI it identifies, resolving each $id against the one enclosing it.
+func indexIDs(ids map[string]any, v any, base *url.URL) {
+	switch x := v.(type) {
+	case map[string]any:
+		if id, ok := x["$id"].(string); ok {
+			if u, err := url.Parse(id); err == nil {
+				if base != nil {
+					u = base.ResolveReference(u)
+				}
+				if u.IsAbs() {
+					u.Fragment = ""
+					base = u
+					if _, seen := ids[u.String()]; !seen {
+						ids[u.String()] = x
+					}
+				}
+			}
+		}
+		for _, k := range sortedKeys(x) {
+			if _, ok := valueKeywords[k]; ok {
+				continue
+			}
+			indexIDs(ids, x[k], base)
+		}
+	case []any:
+		for _, child := range x {
+			indexIDs(ids, child, base)
+		}
+	}
+}
@@ -1,9 +1,12 @@
 package schemaprofile
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/url"
+	"regexp"
 	"sort"
 	"strings"
 
@@ -19,8 +22,9 @@ type Fetcher interface {
 // Normalizer normalizes schemas deterministically per the OpenBindings Schema Compatibility Profile (v0.1).
 // It also provides directional compatibility checks (InputCompatible / OutputCompatible).
 //
-// A Normalizer is not safe for concurrent use. Create a separate instance for each goroutine,
-// or protect access with external synchronization.
+// A Normalizer is safe for concurrent use by multiple goroutines as long as its fields
+// are not modified after first use. Fetch and NormalizeValue are called concurrently in
+// that case and must be safe for concurrent use themselves.
 type Normalizer struct {
 	// Root is the containing document against which JSON Pointer fragments (e.g. "#/schemas/Foo")
 	// are resolved. In OpenBindings, this is typically the full interface document decoded as JSON.
@@ -28,59 +32,321 @@ type Normalizer struct {
 
 	// Base is an optional base URL used to resolve relative $ref references.
 	// If Base is nil and a relative reference is encountered, normalization fails.
+	// Within a schema declaring $id, and within a document reached through a $ref,
+	// relative references resolve against that schema's or document's URI instead;
+	// $id resources embedded in Root are found without fetching.
 	Base *url.URL
 
 	// Fetch is optional. If nil, external $ref resolution is not supported.
 	Fetch Fetcher
 
-	// refStack tracks $ref resolution to detect cycles within a single call.
-	// It is created fresh on each public method invocation.
-	refStack map[string]bool
+	// DisallowExternalRefs rejects every non-fragment $ref with a RefError, even when
+	// Fetch is set. Use it as an explicit policy for untrusted input so a later change
+	// that wires up a Fetcher cannot cause schema fetches.
+	DisallowExternalRefs bool
+
+	// NormalizeValue optionally maps enum and const values to a domain-canonical form
+	// before they are compared, so that values with several valid encodings (for example
+	// "2020-01-01" and "2020-01-01T00:00:00Z") compare equal. Normalized output carries
+	// the mapped values. The function must be idempotent, since a value may pass
+	// through it more than once. If nil, values are compared as-is.
+	NormalizeValue func(v any) any
+
+	// NumericTolerance is the absolute difference within which two numeric bounds
+	// (minimum/maximum and their exclusive forms) are treated as equal, and within
+	// which a multipleOf counts as an integer multiple of another. It absorbs float
+	// representation noise, e.g. a bound or multipleOf authored as 0.3 against one
+	// computed as 0.1+0.2. The tradeoff is that genuinely different values closer
+	// than the tolerance also compare equal, so keep it small. Zero (the default)
+	// compares exactly.
+	NumericTolerance float64
+
+	// FormatAsConstraint treats the "format" keyword as a constraint instead of an
+	// annotation: normalized output keeps it, and an output candidate must declare the
+	// target's format or a narrower one (inputs mirror this). Formats match by exact
+	// equality unless FormatSubsets relates them. The default (false) strips format.
+	FormatAsConstraint bool
+
+	// FormatSubsets lists, for each format, the formats whose values are a superset of
+	// its own (e.g. "email" -> ["idn-email"]). The relation is transitive. It is only
+	// consulted with FormatAsConstraint; if nil, DefaultFormatSubsets is used.
+	FormatSubsets map[string][]string
+
+	// CacheEnabled memoizes normalized schemas: each $ref target, keyed by the reference,
+	// and each schema passed to a public method, keyed by its canonical JSON. This pays
+	// off when many schemas share the same referenced definitions. Results are copied in
+	// and out of the cache, so callers may modify them. See ResetCache for invalidation.
+	CacheEnabled bool
+
+	// MaxDepth bounds how deeply normalization recurses through nested schemas
+	// (properties, items, allOf branches, union variants, followed $refs, ...). A schema
+	// nested deeper fails with a SchemaError rather than exhausting the stack, which
+	// matters when normalizing third-party schemas. Zero means DefaultMaxDepth; a
+	// negative value disables the limit.
+	MaxDepth int
+
+	// OnOutsideProfile decides what happens when a schema uses a keyword outside the
+	// profile. The default, FailClosed, fails with an OutsideProfileError. Structural
+	// limits of the profile, such as oneOf inside allOf, fail under every policy.
+	OnOutsideProfile OutsideProfilePolicy
+
+	// noRoot fails every $ref, for the package-level compatibility checks, which
+	// have no document to resolve against.
+	noRoot bool
+
+	cache normalizeCache
+}
+
+// OutsideProfilePolicy is the handling of out-of-profile keywords; see
+// Normalizer.OnOutsideProfile.
+type OutsideProfilePolicy int
+
+const (
+	// FailClosed fails normalization with an OutsideProfileError.
+	FailClosed OutsideProfilePolicy = iota
+	// StripAndContinue drops the keyword and normalizes the rest of the schema.
+	// This allows partial analysis of real-world schemas but is potentially
+	// unsound for compatibility decisions: a dropped keyword may have constrained
+	// the schema, so two schemas can be reported compatible when they are not.
+	StripAndContinue
+	// CollectAndContinue is StripAndContinue that also records each dropped keyword,
+	// so callers can tell an exact result from a partial one. NormalizeWithReport
+	// returns the records; other methods drop them. Results are not cached under
+	// this policy, since a cache hit would not record the keywords again.
+	CollectAndContinue
+)
+
+// NormalizeResult is the result of Normalizer.NormalizeWithReport.
+type NormalizeResult struct {
+	// Schema is the normalized schema.
+	Schema map[string]any
+	// OutsideProfile lists the out-of-profile keywords dropped under
+	// CollectAndContinue, sorted by path and keyword. It is nil under other policies.
+	OutsideProfile []OutsideProfileError
+}
+
+// DefaultMaxDepth is the nesting limit used when Normalizer.MaxDepth is zero.
+const DefaultMaxDepth = 256
+
+func (n *Normalizer) maxDepth() int {
+	if n.MaxDepth == 0 {
+		return DefaultMaxDepth
+	}
+	return n.MaxDepth
+}
+
+// WithRoot returns a Normalizer with n's settings that resolves references
+// against root, for comparing schemas that live in different documents. The
+// copy starts with an empty cache.
+func (n *Normalizer) WithRoot(root any) *Normalizer {
+	return &Normalizer{
+		Root:                 root,
+		Base:                 n.Base,
+		Fetch:                n.Fetch,
+		DisallowExternalRefs: n.DisallowExternalRefs,
+		NormalizeValue:       n.NormalizeValue,
+		NumericTolerance:     n.NumericTolerance,
+		FormatAsConstraint:   n.FormatAsConstraint,
+		FormatSubsets:        n.FormatSubsets,
+		CacheEnabled:         n.CacheEnabled,
+		MaxDepth:             n.MaxDepth,
+		OnOutsideProfile:     n.OnOutsideProfile,
+	}
 }
 
 // Normalize returns a normalized copy of schema per the v0.1 profile.
 func (n *Normalizer) Normalize(schema map[string]any) (map[string]any, error) {
+	return n.NormalizeCtx(context.Background(), schema)
+}
+
+// NormalizeCtx is Normalize, abandoning the work with ctx's error once ctx is done.
+// Use it to bound the time spent on untrusted schemas, whose nesting is otherwise
+// limited only by their size.
+func (n *Normalizer) NormalizeCtx(ctx context.Context, schema map[string]any) (map[string]any, error) {
 	if n == nil {
 		return nil, errors.New("schemaprofile: nil normalizer")
 	}
-	n.refStack = map[string]bool{}
-	return n.normalizeAt(schema, "")
+	return n.normalizeRoot(newRefStack(ctx), schema)
 }
 
-// InputCompatible reports whether candidate can stand in for target as an input schema.
-// When compatible is false and err is nil, reason describes why the schemas are incompatible.
-func (n *Normalizer) InputCompatible(target, candidate map[string]any) (bool, string, error) {
+// NormalizeWithReport is Normalize, also returning the out-of-profile keywords
+// dropped under the CollectAndContinue policy.
+func (n *Normalizer) NormalizeWithReport(schema map[string]any) (*NormalizeResult, error) {
 	if n == nil {
-		return false, "", errors.New("schemaprofile: nil normalizer")
+		return nil, errors.New("schemaprofile: nil normalizer")
 	}
-	n.refStack = map[string]bool{}
-	ti, err := n.normalizeAt(target, "")
+	refs := newRefStack(context.Background())
+	out, err := n.normalizeRoot(refs, schema)
 	if err != nil {
-		return false, "", err
+		return nil, err
+	}
+	sort.Slice(refs.outside, func(i, j int) bool {
+		a, b := refs.outside[i], refs.outside[j]
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		return a.Keyword < b.Keyword
+	})
+	// A keyword can be reached more than once at one path, e.g. through the allOf
+	// branches a $ref's siblings expand to; report it once.
+	outside := refs.outside[:0]
+	for i, o := range refs.outside {
+		if i > 0 && o.Path == refs.outside[i-1].Path && o.Keyword == refs.outside[i-1].Keyword {
+			continue
+		}
+		outside = append(outside, o)
 	}
-	tc, err := n.normalizeAt(candidate, "")
+	return &NormalizeResult{Schema: out, OutsideProfile: outside}, nil
+}
+
+// NormalizeCanonical returns the RFC 8785 canonical JSON bytes of the normalized schema.
+// Two schemas that normalize to the same form produce identical bytes whatever their
+// key order, union variant order, or annotations, which makes the result suitable as
+// a stored canonical identity or, hashed, as a fingerprint for deduplicating schemas.
+// As a json.RawMessage it embeds as JSON, not base64, when marshaled.
+func (n *Normalizer) NormalizeCanonical(schema map[string]any) (json.RawMessage, error) {
+	out, err := n.Normalize(schema)
 	if err != nil {
-		return false, "", err
+		return nil, err
 	}
-	return inputCompatible(ti, tc)
+	return canonicaljson.Marshal(out)
+}
+
+// NormalizeBatch normalizes each schema in schemas independently, so one failure does
+// not discard the others. results holds every schema that normalized successfully and
+// errs holds the error for each key that failed; errs is nil when all succeed.
+func (n *Normalizer) NormalizeBatch(schemas map[string]map[string]any) (results map[string]map[string]any, errs map[string]error) {
+	results = make(map[string]map[string]any, len(schemas))
+	if n == nil {
+		errs = make(map[string]error, len(schemas))
+		for k := range schemas {
+			errs[k] = errors.New("schemaprofile: nil normalizer")
+		}
+		return results, errs
+	}
+	for k, schema := range schemas {
+		out, err := n.Normalize(schema)
+		if err != nil {
+			if errs == nil {
+				errs = map[string]error{}
+			}
+			errs[k] = err
+			continue
+		}
+		results[k] = out
+	}
+	return results, errs
+}
+
+// InputCompatible reports whether candidate can stand in for target as an input schema.
+// When compatible is false and err is nil, reason describes why the schemas are incompatible.
+func (n *Normalizer) InputCompatible(target, candidate map[string]any) (bool, string, error) {
+	return n.InputCompatibleCtx(context.Background(), target, candidate)
+}
+
+// InputCompatibleCtx is InputCompatible, abandoning the work with ctx's error once
+// ctx is done. Union comparisons grow with the product of their variants, so this
+// bounds checks over untrusted schemas.
+func (n *Normalizer) InputCompatibleCtx(ctx context.Context, target, candidate map[string]any) (bool, string, error) {
+	return n.compatible(ctx, target, candidate, true)
 }
 
 // OutputCompatible reports whether candidate can stand in for target as an output/payload schema.
 // When compatible is false and err is nil, reason describes why the schemas are incompatible.
 func (n *Normalizer) OutputCompatible(target, candidate map[string]any) (bool, string, error) {
+	return n.OutputCompatibleCtx(context.Background(), target, candidate)
+}
+
+// OutputCompatibleCtx is OutputCompatible, abandoning the work with ctx's error once
+// ctx is done.
+func (n *Normalizer) OutputCompatibleCtx(ctx context.Context, target, candidate map[string]any) (bool, string, error) {
+	return n.compatible(ctx, target, candidate, false)
+}
+
+// InputCompatible is Normalizer.InputCompatible for self-contained schemas, using a
+// Normalizer with an empty Root and no Fetch. A schema containing a $ref fails with
+// a RefError; resolve references with a Normalizer whose Root is the containing
+// document. The reason for an incompatibility is not returned.
+func InputCompatible(target, candidate map[string]any) (bool, error) {
+	ok, _, err := standalone().InputCompatible(target, candidate)
+	return ok, err
+}
+
+// OutputCompatible is Normalizer.OutputCompatible for self-contained schemas, with
+// the same limits as InputCompatible.
+func OutputCompatible(target, candidate map[string]any) (bool, error) {
+	ok, _, err := standalone().OutputCompatible(target, candidate)
+	return ok, err
+}
+
+func standalone() *Normalizer {
+	return &Normalizer{Root: map[string]any{}, DisallowExternalRefs: true, noRoot: true}
+}
+
+func (n *Normalizer) compatible(ctx context.Context, target, candidate map[string]any, isInput bool) (bool, string, error) {
 	if n == nil {
 		return false, "", errors.New("schemaprofile: nil normalizer")
 	}
-	n.refStack = map[string]bool{}
-	ti, err := n.normalizeAt(target, "")
+	refs := newRefStack(ctx)
+	ti, err := n.normalizeRoot(refs, target)
 	if err != nil {
 		return false, "", err
 	}
-	tc, err := n.normalizeAt(candidate, "")
+	tc, err := n.normalizeRoot(refs, candidate)
 	if err != nil {
 		return false, "", err
 	}
-	return outputCompatible(ti, tc)
+	c := n.comparer(ctx)
+	var ok bool
+	var reason string
+	if isInput {
+		ok, reason, err = c.inputCompatible(ti, tc)
+	} else {
+		ok, reason, err = c.outputCompatible(ti, tc)
+	}
+	// Nested comparisons report errors as reasons; surface cancellation as such.
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return false, "", ctxErr
+	}
+	return ok, reason, err
+}
+
+// Equivalent reports whether a and b accept exactly the same values under the profile,
+// i.e. each is input-compatible with the other.
+func (n *Normalizer) Equivalent(a, b map[string]any) (bool, error) {
+	ab, _, err := n.InputCompatible(a, b)
+	if err != nil || !ab {
+		return false, err
+	}
+	ba, _, err := n.InputCompatible(b, a)
+	if err != nil {
+		return false, err
+	}
+	return ba, nil
+}
+
+// StrictlyMorePermissive reports whether candidate accepts every value target accepts
+// and more: InputCompatible(target, candidate) holds but the schemas are not Equivalent.
+// Tooling can use it to flag source schemas that are looser than the operation contract.
+func (n *Normalizer) StrictlyMorePermissive(target, candidate map[string]any) (bool, error) {
+	ok, _, err := n.InputCompatible(target, candidate)
+	if err != nil || !ok {
+		return false, err
+	}
+	eq, err := n.Equivalent(target, candidate)
+	if err != nil {
+		return false, err
+	}
+	return !eq, nil
+}
+
+func (n *Normalizer) comparer(ctx context.Context) *comparer {
+	c := &comparer{ctx: ctx, epsilon: n.NumericTolerance}
+	if n.FormatAsConstraint {
+		c.formats = true
+		c.formatSubsets = n.formatSubsets()
+	}
+	return c
 }
 
 // CanonicalString returns the RFC 8785 (JCS) canonical JSON string of v.
@@ -148,16 +414,24 @@ var (
 	inScopeKeywords = map[string]struct{}{
 		"$ref":                 {},
 		"$defs":                {},
+		"$id":                  {},
 		"allOf":                {},
 		"type":                 {},
 		"enum":                 {},
 		"const":                {},
 		"properties":           {},
 		"required":             {},
+		"dependentRequired":    {},
 		"additionalProperties": {},
+		"patternProperties":    {},
 		"items":                {},
+		"prefixItems":          {},
 		"oneOf":                {},
 		"anyOf":                {},
+		"not":                  {},
+		"if":                   {},
+		"then":                 {},
+		"else":                 {},
 		"minimum":              {},
 		"maximum":              {},
 		"exclusiveMinimum":     {},
@@ -166,6 +440,7 @@ var (
 		"maxLength":            {},
 		"minItems":             {},
 		"maxItems":             {},
+		"multipleOf":           {},
 	}
 
 	// annotation-only keywords (ignored for compatibility decisions, stripped during normalization)
@@ -190,7 +465,15 @@ var (
 	}
 )
 
-func (n *Normalizer) normalizeAt(schema map[string]any, path string) (map[string]any, error) {
+func (n *Normalizer) normalizeAt(refs *refStack, schema map[string]any, path string) (map[string]any, error) {
+	if err := refs.ctx.Err(); err != nil {
+		return nil, err
+	}
+	refs.depth++
+	defer func() { refs.depth-- }()
+	if limit := n.maxDepth(); limit > 0 && refs.depth > limit {
+		return nil, &SchemaError{Path: path, Message: fmt.Sprintf("schema nesting exceeds maximum depth %d", limit)}
+	}
 	if schema == nil {
 		// treat nil as Top: return empty object
 		return map[string]any{}, nil
@@ -200,35 +483,73 @@ func (n *Normalizer) normalizeAt(schema map[string]any, path string) (map[string
 	// profile keyword check. This is structural (affects compatibility)
 	// so it must happen before annotations are stripped.
 	schema = applyNullable(schema)
+	schema, err := applyTupleItems(schema, path)
+	if err != nil {
+		return nil, err
+	}
 
-	if err := assertProfileKeywords(schema, path); err != nil {
+	schema, err = n.profileKeywords(refs, schema, path)
+	if err != nil {
 		return nil, err
 	}
 
+	// $id sets the base for relative references in this subtree, including a sibling $ref.
+	if v, ok := schema["$id"]; ok {
+		id, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s.$id: must be string", pathOrRoot(path))
+		}
+		restore, err := n.enterID(refs, id, path)
+		if err != nil {
+			return nil, err
+		}
+		defer restore()
+	}
+
 	// Inline $ref for comparison.
 	if ref, ok := schema["$ref"].(string); ok && strings.TrimSpace(ref) != "" {
-		resolved, cleanup, err := n.resolveRef(ref, path)
+		// 2020-12 applies constraining siblings of $ref too: evaluate both as an allOf.
+		if branches, ok := n.refAllOf(schema); ok {
+			return n.normalizeAt(refs, map[string]any{"allOf": branches}, path)
+		}
+		t, err := n.targetOf(refs, ref, path)
+		if err != nil {
+			return nil, err
+		}
+		if out, ok := n.cacheGet(refCacheKey(t.key)); ok {
+			return out, nil
+		}
+		resolved, cleanup, err := n.resolveRef(refs, ref, t, path)
 		if err != nil {
 			return nil, err
 		}
 		// Keep the ref on the stack during normalization to detect cycles.
 		defer cleanup()
-		rm, ok := asMap(resolved)
+		rm, ok := asSchema(resolved)
 		if !ok {
-			return nil, &RefError{Path: path, Ref: ref, Err: errors.New("resolved $ref is not an object")}
+			return nil, &RefError{Path: path, Ref: ref, Err: errors.New("resolved $ref is not a schema")}
 		}
-		// The profile defines evaluation equivalent to inlining. We normalize the resolved schema.
-		return n.normalizeAt(rm, path)
+		// The profile defines evaluation equivalent to inlining. We normalize the resolved
+		// schema, with relative references in it resolved against its own resource.
+		prev := refs.base
+		refs.base = t.doc
+		out, err := n.normalizeAt(refs, rm, path)
+		refs.base = prev
+		if err != nil {
+			return nil, err
+		}
+		n.cachePut(refCacheKey(t.key), out)
+		return out, nil
 	}
 
 	// Strip annotation-only keywords, $defs, and x- extensions from the output.
 	out := make(map[string]any, len(schema))
 	for k, v := range schema {
-		if _, isAnnotation := annotationKeywords[k]; isAnnotation {
+		if _, isAnnotation := annotationKeywords[k]; isAnnotation && !(k == "format" && n.FormatAsConstraint) {
 			continue
 		}
-		if k == "$defs" {
-			continue // $defs are only needed for $ref resolution; after inlining they're dead weight
+		if k == "$defs" || k == "$id" {
+			continue // only needed for $ref resolution; after inlining they're dead weight
 		}
 		if strings.HasPrefix(k, "x-") {
 			continue
@@ -236,14 +557,16 @@ func (n *Normalizer) normalizeAt(schema map[string]any, path string) (map[string
 		out[k] = v
 	}
 
+	n.applyValueNormalizer(out)
+
 	// Flatten allOf before anything else.
 	if allOf, ok := out["allOf"]; ok {
-		merged, err := n.flattenAllOf(allOf, path)
+		merged, err := n.flattenAllOf(refs, allOf, path)
 		if err != nil {
 			return nil, err
 		}
 		// Replace out with the merged result and re-normalize.
-		return n.normalizeAt(merged, path)
+		return n.normalizeAt(refs, merged, path)
 	}
 
 	// Normalize type (absent type is unconstrained per spec — do NOT infer).
@@ -255,6 +578,19 @@ func (n *Normalizer) normalizeAt(schema map[string]any, path string) (map[string
 		out["type"] = types
 	}
 
+	// Normalize dependentRequired: sorted lists, and no entries that require nothing.
+	if v, ok := out["dependentRequired"]; ok {
+		deps, err := normalizeDependentRequired(v)
+		if err != nil {
+			return nil, fmt.Errorf("%s.dependentRequired: %w", pathOrRoot(path), err)
+		}
+		if len(deps) == 0 {
+			delete(out, "dependentRequired")
+		} else {
+			out["dependentRequired"] = deps
+		}
+	}
+
 	// Normalize required.
 	if v, ok := out["required"]; ok {
 		req, err := normalizeStringSet(v)
@@ -264,6 +600,18 @@ func (n *Normalizer) normalizeAt(schema map[string]any, path string) (map[string
 		out["required"] = req
 	}
 
+	if v, ok := out["multipleOf"]; ok {
+		if _, ok := positiveRat(v); !ok {
+			return nil, fmt.Errorf("%s.multipleOf: must be a positive number", pathOrRoot(path))
+		}
+	}
+
+	if v, ok := out["format"]; ok {
+		if _, ok := v.(string); !ok {
+			return nil, fmt.Errorf("%s.format: must be string", pathOrRoot(path))
+		}
+	}
+
 	// Recurse into nested schemas.
 	if props, ok := out["properties"]; ok {
 		propsMap, ok := asMap(props)
@@ -276,7 +624,7 @@ func (n *Normalizer) normalizeAt(schema map[string]any, path string) (map[string
 			if !ok {
 				return nil, fmt.Errorf("%s.properties[%q]: must be object", pathOrRoot(path), k)
 			}
-			nv, err := n.normalizeAt(vm, ptrJoin(path, fmt.Sprintf("properties[%q]", k)))
+			nv, err := n.normalizeAt(refs, vm, ptrJoin(path, fmt.Sprintf("properties[%q]", k)))
 			if err != nil {
 				return nil, err
 			}
@@ -285,15 +633,44 @@ func (n *Normalizer) normalizeAt(schema map[string]any, path string) (map[string
 		out["properties"] = nm
 	}
 
+	// Patterns are kept verbatim; they are map keys, so canonical output sorts them.
+	if pp, ok := out["patternProperties"]; ok {
+		ppMap, ok := asMap(pp)
+		if !ok {
+			return nil, fmt.Errorf("%s.patternProperties: must be object", pathOrRoot(path))
+		}
+		nm := make(map[string]any, len(ppMap))
+		for _, k := range sortedKeys(ppMap) {
+			if _, err := regexp.Compile(k); err != nil {
+				return nil, fmt.Errorf("%s.patternProperties[%q]: invalid pattern: %w", pathOrRoot(path), k, err)
+			}
+			vm, ok := asSchema(ppMap[k])
+			if !ok {
+				return nil, fmt.Errorf("%s.patternProperties[%q]: must be boolean or object", pathOrRoot(path), k)
+			}
+			nv, err := n.normalizeAt(refs, vm, ptrJoin(path, fmt.Sprintf("patternProperties[%q]", k)))
+			if err != nil {
+				return nil, err
+			}
+			nm[k] = nv
+		}
+		out["patternProperties"] = nm
+	}
+
 	if ap, ok := out["additionalProperties"]; ok {
 		switch x := ap.(type) {
 		case bool:
 			out["additionalProperties"] = x
 		case map[string]any:
-			nv, err := n.normalizeAt(x, ptrJoin(path, "additionalProperties"))
+			nv, err := n.normalizeAt(refs, x, ptrJoin(path, "additionalProperties"))
 			if err != nil {
 				return nil, err
 			}
+			if len(nv) == 0 {
+				// A schema that normalizes to Top is equivalent to true.
+				out["additionalProperties"] = true
+				break
+			}
 			out["additionalProperties"] = nv
 		default:
 			return nil, fmt.Errorf("%s.additionalProperties: must be boolean or object", pathOrRoot(path))
@@ -301,17 +678,90 @@ func (n *Normalizer) normalizeAt(schema map[string]any, path string) (map[string
 	}
 
 	if items, ok := out["items"]; ok {
-		im, ok := asMap(items)
+		im, ok := asSchema(items)
 		if !ok {
-			return nil, fmt.Errorf("%s.items: must be object", pathOrRoot(path))
+			return nil, fmt.Errorf("%s.items: must be boolean, object, or array", pathOrRoot(path))
 		}
-		nv, err := n.normalizeAt(im, ptrJoin(path, "items"))
+		nv, err := n.normalizeAt(refs, im, ptrJoin(path, "items"))
 		if err != nil {
 			return nil, err
 		}
 		out["items"] = nv
 	}
 
+	if prefix, ok := out["prefixItems"]; ok {
+		arr, ok := asSlice(prefix)
+		if !ok {
+			return nil, fmt.Errorf("%s.prefixItems: must be array", pathOrRoot(path))
+		}
+		nArr := make([]any, 0, len(arr))
+		for idx, item := range arr {
+			im, ok := asSchema(item)
+			if !ok {
+				return nil, fmt.Errorf("%s.prefixItems[%d]: must be boolean or object", pathOrRoot(path), idx)
+			}
+			nv, err := n.normalizeAt(refs, im, ptrJoin(path, fmt.Sprintf("prefixItems[%d]", idx)))
+			if err != nil {
+				return nil, err
+			}
+			nArr = append(nArr, nv)
+		}
+		out["prefixItems"] = nArr
+	}
+
+	if nt, ok := out["not"]; ok {
+		nm, ok := asSchema(nt)
+		if !ok {
+			return nil, fmt.Errorf("%s.not: must be boolean or object", pathOrRoot(path))
+		}
+		nv, err := n.normalizeAt(refs, nm, ptrJoin(path, "not"))
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case len(nv) == 0:
+			// not: {} excludes every value.
+			return bottomSchema(), nil
+		case isBottom(nv):
+			// not: false excludes nothing.
+			delete(out, "not")
+		default:
+			out["not"] = nv
+		}
+	}
+
+	// Conditionals are kept as a block compared by canonical equality (see
+	// compatConditional). then/else that normalize to Top are dropped, and so is
+	// a block left without then and else, or then/else without if.
+	if _, ok := out["if"]; ok {
+		for _, k := range []string{"if", "then", "else"} {
+			v, ok := out[k]
+			if !ok {
+				continue
+			}
+			m, ok := asSchema(v)
+			if !ok {
+				return nil, fmt.Errorf("%s.%s: must be boolean or object", pathOrRoot(path), k)
+			}
+			nv, err := n.normalizeAt(refs, m, ptrJoin(path, k))
+			if err != nil {
+				return nil, err
+			}
+			if k != "if" && len(nv) == 0 {
+				delete(out, k)
+				continue
+			}
+			out[k] = nv
+		}
+	}
+	if !hasKey(out, "then") && !hasKey(out, "else") {
+		delete(out, "if")
+	}
+	if !hasKey(out, "if") {
+		delete(out, "then")
+		delete(out, "else")
+	}
+
 	for _, k := range []string{"oneOf", "anyOf"} {
 		if u, ok := out[k]; ok {
 			arr, ok := asSlice(u)
@@ -324,7 +774,7 @@ func (n *Normalizer) normalizeAt(schema map[string]any, path string) (map[string
 				if !ok {
 					return nil, fmt.Errorf("%s.%s[%d]: must be object", pathOrRoot(path), k, idx)
 				}
-				nv, err := n.normalizeAt(m, ptrJoin(path, fmt.Sprintf("%s[%d]", k, idx)))
+				nv, err := n.normalizeAt(refs, m, ptrJoin(path, fmt.Sprintf("%s[%d]", k, idx)))
 				if err != nil {
 					return nil, err
 				}
@@ -355,63 +805,20 @@ func (n *Normalizer) normalizeAt(schema map[string]any, path string) (map[string
 	return out, nil
 }
 
-// resolveRef resolves a $ref and returns the resolved value plus a cleanup function.
-// The cleanup function MUST be called when the caller is done normalizing the resolved schema,
-// to remove the ref from the cycle-detection stack. This ensures that recursive $refs
-// within the resolved schema are properly detected as cycles.
-func (n *Normalizer) resolveRef(ref string, path string) (any, func(), error) {
-	noop := func() {}
-	u, err := url.Parse(ref)
-	if err != nil {
-		return nil, noop, &RefError{Path: pathOrRoot(path), Ref: ref, Err: err}
-	}
-
-	// Resolve against base if needed.
-	if !u.IsAbs() && u.Path != "" {
-		if n.Base == nil {
-			return nil, noop, &RefError{Path: pathOrRoot(path), Ref: ref, Err: errors.New("relative $ref with no base")}
-		}
-		u = n.Base.ResolveReference(u)
+// applyValueNormalizer rewrites const and enum values in schema through NormalizeValue.
+// The schema map is modified in place; callers pass a copy they own.
+func (n *Normalizer) applyValueNormalizer(schema map[string]any) {
+	if n.NormalizeValue == nil {
+		return
 	}
-
-	key := u.String()
-
-	// Cycle detection: if this ref is already being resolved on the current stack, it's a cycle.
-	if n.refStack[key] {
-		return nil, noop, &RefError{Path: pathOrRoot(path), Ref: ref, Err: errors.New("cycle detected")}
+	if c, ok := schema["const"]; ok {
+		schema["const"] = n.NormalizeValue(c)
 	}
-
-	n.refStack[key] = true
-	cleanup := func() { delete(n.refStack, key) }
-
-	var doc any
-	switch {
-	case u.Scheme == "" && u.Path == "" && (u.Fragment != "" || strings.HasPrefix(ref, "#")):
-		// Fragment-only ref: resolve against root.
-		doc = n.Root
-	default:
-		// External document (optional).
-		if n.Fetch == nil {
-			cleanup()
-			return nil, noop, &RefError{Path: pathOrRoot(path), Ref: ref, Err: errors.New("external $ref unsupported (no fetcher)")}
-		}
-		fetched, err := n.Fetch.Fetch(u)
-		if err != nil {
-			cleanup()
-			return nil, noop, &RefError{Path: pathOrRoot(path), Ref: ref, Err: err}
-		}
-		doc, err = decodeJSON(fetched)
-		if err != nil {
-			cleanup()
-			return nil, noop, &RefError{Path: pathOrRoot(path), Ref: ref, Err: err}
+	if e, ok := asSlice(schema["enum"]); ok {
+		vals := make([]any, len(e))
+		for i, v := range e {
+			vals[i] = n.NormalizeValue(v)
 		}
+		schema["enum"] = vals
 	}
-
-	v, err := resolveJSONPointer(doc, u.Fragment)
-	if err != nil {
-		cleanup()
-		return nil, noop, &RefError{Path: pathOrRoot(path), Ref: ref, Err: err}
-	}
-
-	return v, cleanup, nil
 }
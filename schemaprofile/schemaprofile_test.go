@@ -1,11 +1,16 @@
 package schemaprofile
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/url"
 	"os"
+	"reflect"
 	"strings"
+	"sync"
 	"testing"
 )
 
@@ -138,6 +143,123 @@ func TestNormalize_RefResolutionRelativeWithBase(t *testing.T) {
 	}
 }
 
+func TestNormalize_IDChangesBaseForNestedRefs(t *testing.T) {
+	base, err := url.Parse("https://example.com/api/openbindings.json")
+	if err != nil {
+		t.Fatalf("parse base: %v", err)
+	}
+	var fetched []string
+	n := &Normalizer{
+		Base: base,
+		Root: map[string]any{
+			"schemas": map[string]any{
+				"Order": map[string]any{
+					"$id":  "orders/order.json",
+					"type": "object",
+					"properties": map[string]any{
+						"id":       map[string]any{"$ref": "#/$defs/Id"},
+						"customer": map[string]any{"$ref": "customer.json"},
+						"total":    map[string]any{"$ref": "money.json#/$defs/Amount"},
+					},
+					"$defs": map[string]any{"Id": map[string]any{"type": "string"}},
+				},
+				"Customer": map[string]any{
+					"$id":  "https://example.com/api/orders/customer.json",
+					"type": "object",
+				},
+			},
+		},
+		Fetch: fetcherFunc(func(u *url.URL) ([]byte, error) {
+			fetched = append(fetched, u.String())
+			if !strings.HasPrefix(u.String(), "https://example.com/api/orders/money.json#") {
+				return nil, errors.New("unexpected URL")
+			}
+			return []byte(`{"$defs":{"Amount":{"$ref":"#/$defs/Decimal"},"Decimal":{"type":"number"}}}`), nil
+		}),
+	}
+
+	out, err := n.Normalize(map[string]any{"$ref": "#/schemas/Order"})
+	if err != nil {
+		t.Fatalf("normalize: %v", err)
+	}
+	if _, has := out["$id"]; has {
+		t.Fatal("expected $id to be stripped")
+	}
+	got, err := CanonicalString(out["properties"])
+	if err != nil {
+		t.Fatalf("canonical: %v", err)
+	}
+	want := `{"customer":{"type":["object"]},"id":{"type":["string"]},"total":{"type":["number"]}}`
+	if got != want {
+		t.Fatalf("unexpected properties:\n got: %s\nwant: %s", got, want)
+	}
+	// The fragment-only ref inside money.json resolves within that document.
+	if len(fetched) != 2 || fetched[0] != "https://example.com/api/orders/money.json#/$defs/Amount" ||
+		fetched[1] != "https://example.com/api/orders/money.json#/$defs/Decimal" {
+		t.Fatalf("unexpected fetches: %q", fetched)
+	}
+
+	noBase := &Normalizer{Root: map[string]any{}}
+	_, err = noBase.Normalize(map[string]any{"$id": "relative.json", "type": "string"})
+	var re *RefError
+	if !errors.As(err, &re) {
+		t.Fatalf("expected RefError for relative $id without base, got %v", err)
+	}
+}
+
+func TestNormalize_DisallowExternalRefsIgnoresFetcher(t *testing.T) {
+	fetched := false
+	n := &Normalizer{
+		Root:                 map[string]any{"schemas": map[string]any{"Local": map[string]any{"type": "string"}}},
+		DisallowExternalRefs: true,
+		Fetch: fetcherFunc(func(u *url.URL) ([]byte, error) {
+			fetched = true
+			return []byte(`{"type":"string"}`), nil
+		}),
+	}
+	_, err := n.Normalize(map[string]any{"$ref": "https://example.com/schema.json"})
+	var re *RefError
+	if err == nil || !errors.As(err, &re) {
+		t.Fatalf("expected RefError, got %v", err)
+	}
+	if !strings.Contains(re.Err.Error(), "external $ref disallowed") {
+		t.Fatalf("expected external $ref disallowed, got %v", re.Err)
+	}
+	if fetched {
+		t.Fatalf("expected fetcher not to be called")
+	}
+	if _, err := n.Normalize(map[string]any{"$ref": "#/schemas/Local"}); err != nil {
+		t.Fatalf("expected fragment $ref to resolve, got %v", err)
+	}
+}
+
+func TestNormalizeBatch_CollectsPerKeyErrors(t *testing.T) {
+	n := &Normalizer{Root: map[string]any{}}
+	results, errs := n.NormalizeBatch(map[string]map[string]any{
+		"Good":     {"type": "string"},
+		"Pattern":  {"type": "string", "pattern": "^a$"},
+		"Dangling": {"$ref": "#/schemas/Missing"},
+	})
+	if _, ok := results["Good"]; !ok || len(results) != 1 {
+		t.Fatalf("expected only Good in results, got %#v", results)
+	}
+	var ope *OutsideProfileError
+	if !errors.As(errs["Pattern"], &ope) {
+		t.Fatalf("expected OutsideProfileError for Pattern, got %v", errs["Pattern"])
+	}
+	var re *RefError
+	if !errors.As(errs["Dangling"], &re) {
+		t.Fatalf("expected RefError for Dangling, got %v", errs["Dangling"])
+	}
+	if len(errs) != 2 {
+		t.Fatalf("expected 2 errors, got %v", errs)
+	}
+
+	if _, errs := n.NormalizeBatch(map[string]map[string]any{"A": {"type": "string"}}); errs != nil {
+		t.Fatalf("expected nil errs on success, got %v", errs)
+	}
+}
+
 func TestInputCompatible_EmptyTargetRequiresUnconstrainedCandidate(t *testing.T) {
 	n := &Normalizer{Root: map[string]any{}}
 	// Empty target ({}) is Top — the interface may send any value.
@@ -222,6 +344,7 @@ type profileCaseFile struct {
 
 type profileCase struct {
 	Name       string         `json:"name"`
+	Root       map[string]any `json:"root,omitempty"`
 	Direction  string         `json:"direction"`
 	Target     map[string]any `json:"target"`
 	Candidate  map[string]any `json:"candidate"`
@@ -398,19 +521,26 @@ func TestProfileV01_GoldenCases(t *testing.T) {
 	if err != nil {
 		t.Fatalf("read cases: %v", err)
 	}
+	// Decode numbers as json.Number, as fetched documents are, so cases can
+	// express bounds that float64 would round.
 	var f profileCaseFile
-	if err := json.Unmarshal(data, &f); err != nil {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&f); err != nil {
 		t.Fatalf("unmarshal cases: %v", err)
 	}
 	if len(f.Cases) == 0 {
 		t.Fatalf("no cases")
 	}
 
-	n := &Normalizer{Root: map[string]any{}}
 	for _, c := range f.Cases {
 		if c.Name == "" {
 			t.Fatalf("case missing name")
 		}
+		n := &Normalizer{Root: c.Root}
+		if n.Root == nil {
+			n.Root = map[string]any{}
+		}
 		var (
 			ok  bool
 			err error
@@ -574,3 +704,785 @@ func TestNormalize_NullableInAllOfBranch(t *testing.T) {
 		t.Fatalf("expected type array with null and string, got %v", types)
 	}
 }
+
+func TestNormalize_AdditionalPropertiesTopCollapsesToTrue(t *testing.T) {
+	n := &Normalizer{Root: map[string]any{}}
+	a, err := n.Normalize(map[string]any{"type": "object", "additionalProperties": map[string]any{"title": "any"}})
+	if err != nil {
+		t.Fatalf("normalize: %v", err)
+	}
+	b, err := n.Normalize(map[string]any{"type": "object", "additionalProperties": true})
+	if err != nil {
+		t.Fatalf("normalize: %v", err)
+	}
+	ac, _ := CanonicalString(a)
+	bc, _ := CanonicalString(b)
+	if ac != bc {
+		t.Fatalf("expected identical normalized forms, got %s vs %s", ac, bc)
+	}
+}
+
+func TestEffectiveBounds(t *testing.T) {
+	show := func(schema map[string]any) string {
+		lo, hi, loExcl, hiExcl := EffectiveBounds(schema)
+		out := "(-inf"
+		if lo != nil {
+			out = fmt.Sprintf("[%v", *lo)
+			if loExcl {
+				out = fmt.Sprintf("(%v", *lo)
+			}
+		}
+		if hi == nil {
+			return out + ", +inf)"
+		}
+		if hiExcl {
+			return fmt.Sprintf("%s, %v)", out, *hi)
+		}
+		return fmt.Sprintf("%s, %v]", out, *hi)
+	}
+	cases := []struct {
+		schema map[string]any
+		want   string
+	}{
+		{map[string]any{"type": []any{"number"}}, "(-inf, +inf)"},
+		{map[string]any{"minimum": 1, "maximum": 100}, "[1, 100]"},
+		{map[string]any{"exclusiveMinimum": 1, "exclusiveMaximum": json.Number("100")}, "(1, 100)"},
+		{map[string]any{"minimum": 2.5, "exclusiveMinimum": 1}, "[2.5, +inf)"},
+		{map[string]any{"minimum": 1, "exclusiveMinimum": 1}, "(1, +inf)"},
+		{map[string]any{"maximum": 5, "exclusiveMaximum": 9}, "(-inf, 5]"},
+		{map[string]any{"maximum": 5, "exclusiveMaximum": 5}, "(-inf, 5)"},
+	}
+	for _, c := range cases {
+		if got := show(c.schema); got != c.want {
+			t.Errorf("EffectiveBounds(%v) = %s, want %s", c.schema, got, c.want)
+		}
+	}
+
+	n := &Normalizer{Root: map[string]any{}}
+	norm, err := n.Normalize(map[string]any{
+		"type": "integer",
+		"allOf": []any{
+			map[string]any{"minimum": 0, "maximum": 100},
+			map[string]any{"exclusiveMinimum": 0, "maximum": 50},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := show(norm), "(0, 50]"; got != want {
+		t.Fatalf("EffectiveBounds of normalized allOf = %s, want %s", got, want)
+	}
+}
+
+func TestStrictlyMorePermissive(t *testing.T) {
+	n := &Normalizer{Root: map[string]any{}}
+	target := map[string]any{"type": "string", "maxLength": 10}
+
+	cases := []struct {
+		name      string
+		candidate map[string]any
+		want      bool
+	}{
+		{"broader", map[string]any{"type": "string", "maxLength": 20}, true},
+		{"equivalent", map[string]any{"type": "string", "maxLength": 10, "title": "same"}, false},
+		{"narrower", map[string]any{"type": "string", "maxLength": 5}, false},
+	}
+	for _, tc := range cases {
+		got, err := n.StrictlyMorePermissive(target, tc.candidate)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.name, err)
+		}
+		if got != tc.want {
+			t.Fatalf("%s: expected %v, got %v", tc.name, tc.want, got)
+		}
+	}
+
+	eq, err := n.Equivalent(target, map[string]any{"maxLength": 10, "type": []any{"string"}})
+	if err != nil || !eq {
+		t.Fatalf("expected equivalent, got %v (err %v)", eq, err)
+	}
+}
+
+func TestNormalizeValue_DomainEqualEnumValues(t *testing.T) {
+	target := map[string]any{"type": "string", "enum": []any{"2020-01-01"}}
+	candidate := map[string]any{"type": "string", "enum": []any{"2020-01-01T00:00:00Z"}}
+
+	n := &Normalizer{Root: map[string]any{}}
+	ok, _, err := n.OutputCompatible(target, candidate)
+	if err != nil {
+		t.Fatalf("output compatible: %v", err)
+	}
+	if ok {
+		t.Fatalf("expected incompatible without a value normalizer")
+	}
+
+	n.NormalizeValue = func(v any) any {
+		if s, ok := v.(string); ok {
+			return strings.TrimSuffix(s, "T00:00:00Z")
+		}
+		return v
+	}
+	ok, reason, err := n.OutputCompatible(target, candidate)
+	if err != nil {
+		t.Fatalf("output compatible: %v", err)
+	}
+	if !ok {
+		t.Fatalf("expected compatible with value normalizer, got %s", reason)
+	}
+
+	// allOf enum intersection also sees normalized values.
+	if _, err := n.Normalize(map[string]any{"allOf": []any{
+		map[string]any{"enum": []any{"2020-01-01"}},
+		map[string]any{"enum": []any{"2020-01-01T00:00:00Z"}},
+	}}); err != nil {
+		t.Fatalf("expected non-empty allOf enum intersection, got %v", err)
+	}
+}
+
+func TestNumericTolerance_ReconcilesFloatNoise(t *testing.T) {
+	a, b := 0.1, 0.2
+	target := map[string]any{"type": "number", "maximum": 0.3}
+	candidate := map[string]any{"type": "number", "maximum": a + b}
+
+	n := &Normalizer{Root: map[string]any{}}
+	ok, _, err := n.OutputCompatible(target, candidate)
+	if err != nil {
+		t.Fatalf("output compatible: %v", err)
+	}
+	if ok {
+		t.Fatalf("expected exact comparison to reject 0.1+0.2 > 0.3")
+	}
+
+	n.NumericTolerance = 1e-9
+	ok, reason, err := n.OutputCompatible(target, candidate)
+	if err != nil {
+		t.Fatalf("output compatible: %v", err)
+	}
+	if !ok {
+		t.Fatalf("expected tolerance to reconcile bounds, got %s", reason)
+	}
+
+	// Differences larger than the tolerance are still detected.
+	ok, _, err = n.OutputCompatible(target, map[string]any{"type": "number", "maximum": 0.31})
+	if err != nil {
+		t.Fatalf("output compatible: %v", err)
+	}
+	if ok {
+		t.Fatalf("expected 0.31 > 0.3 to remain incompatible")
+	}
+}
+
+func TestNumericTolerance_MultipleOf(t *testing.T) {
+	a, b := 0.1, 0.2
+	target := map[string]any{"type": "number", "multipleOf": 0.1}
+	candidate := map[string]any{"type": "number", "multipleOf": a + b} // 0.30000000000000004
+
+	n := &Normalizer{Root: map[string]any{}}
+	if ok, _, err := n.OutputCompatible(target, candidate); err != nil || ok {
+		t.Fatalf("expected exact comparison to reject 0.1+0.2 as a multiple of 0.1, got %v, %v", ok, err)
+	}
+	if ok, _, err := n.InputCompatible(candidate, map[string]any{"type": "number", "multipleOf": 0.1}); err != nil || ok {
+		t.Fatalf("expected exact comparison to reject 0.1 as dividing 0.1+0.2, got %v, %v", ok, err)
+	}
+
+	n.NumericTolerance = 1e-9
+	if ok, reason, err := n.OutputCompatible(target, candidate); err != nil || !ok {
+		t.Fatalf("expected tolerance to accept 0.1+0.2 as a multiple of 0.1, got %v, %q, %v", ok, reason, err)
+	}
+	if ok, reason, err := n.InputCompatible(candidate, map[string]any{"type": "number", "multipleOf": 0.1}); err != nil || !ok {
+		t.Fatalf("expected tolerance to accept 0.1 as dividing 0.1+0.2, got %v, %q, %v", ok, reason, err)
+	}
+
+	// Remainders larger than the tolerance are still detected, and a multipleOf
+	// smaller than the target's is not a multiple of it.
+	for _, cand := range []float64{0.35, 0.05} {
+		if ok, _, err := n.OutputCompatible(target, map[string]any{"type": "number", "multipleOf": cand}); err != nil || ok {
+			t.Fatalf("expected multipleOf %v to remain incompatible with 0.1, got %v, %v", cand, ok, err)
+		}
+	}
+}
+
+func TestFormatAsConstraint(t *testing.T) {
+	str := func(format string) map[string]any {
+		s := map[string]any{"type": "string"}
+		if format != "" {
+			s["format"] = format
+		}
+		return s
+	}
+
+	// Off by default: format is an annotation.
+	n := &Normalizer{Root: map[string]any{}}
+	if ok, _, err := n.OutputCompatible(str("email"), str("uri")); err != nil || !ok {
+		t.Fatalf("expected format to be ignored by default, got %v, %v", ok, err)
+	}
+
+	n.FormatAsConstraint = true
+	out, err := n.Normalize(str("email"))
+	if err != nil {
+		t.Fatalf("normalize: %v", err)
+	}
+	if out["format"] != "email" {
+		t.Fatalf("expected format to be kept, got %v", out)
+	}
+
+	cases := []struct {
+		name              string
+		input             bool
+		target, candidate string
+		want              bool
+	}{
+		{"output same format", false, "email", "email", true},
+		{"output narrower candidate", false, "idn-email", "email", true},
+		{"output wider candidate", false, "email", "idn-email", false},
+		{"output unrelated", false, "email", "uri", false},
+		{"output candidate omits format", false, "email", "", false},
+		{"output target omits format", false, "", "email", true},
+		{"output transitive", false, "iri-reference", "uri", true},
+		{"input wider candidate", true, "email", "idn-email", true},
+		{"input narrower candidate", true, "idn-email", "email", false},
+		{"input candidate omits format", true, "email", "", true},
+		{"input target omits format", true, "", "email", false},
+	}
+	for _, c := range cases {
+		check := n.OutputCompatible
+		if c.input {
+			check = n.InputCompatible
+		}
+		ok, reason, err := check(str(c.target), str(c.candidate))
+		if err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		if ok != c.want {
+			t.Fatalf("%s: expected compatible=%v, got %v (%s)", c.name, c.want, ok, reason)
+		}
+	}
+
+	// A registered hierarchy replaces the default one.
+	n.FormatSubsets = map[string][]string{"uuid": {"string-id"}}
+	if ok, _, err := n.OutputCompatible(str("string-id"), str("uuid")); err != nil || !ok {
+		t.Fatalf("expected registered subset to be compatible, got %v, %v", ok, err)
+	}
+	if ok, _, err := n.OutputCompatible(str("idn-email"), str("email")); err != nil || ok {
+		t.Fatalf("expected default hierarchy to be replaced, got %v, %v", ok, err)
+	}
+	n.FormatSubsets = nil
+
+	// allOf keeps the narrower of related formats and rejects unrelated ones.
+	out, err = n.Normalize(map[string]any{"allOf": []any{str("idn-email"), str("email")}})
+	if err != nil {
+		t.Fatalf("normalize allOf: %v", err)
+	}
+	if out["format"] != "email" {
+		t.Fatalf("expected allOf to keep the narrower format, got %v", out)
+	}
+	_, err = n.Normalize(map[string]any{"allOf": []any{str("email"), str("uri")}})
+	var ope *OutsideProfileError
+	if !errors.As(err, &ope) {
+		t.Fatalf("expected OutsideProfileError for unrelated formats, got %v", err)
+	}
+
+	if _, err := n.Normalize(map[string]any{"format": 1.0}); err == nil {
+		t.Fatal("expected error for non-string format")
+	}
+}
+
+func TestNormalize_Not(t *testing.T) {
+	n := &Normalizer{Root: map[string]any{}}
+
+	out, err := n.Normalize(map[string]any{"type": "string", "not": map[string]any{"const": "x", "description": "d"}})
+	if err != nil {
+		t.Fatalf("normalize: %v", err)
+	}
+	if got := canonicalKey(out["not"]); got != `{"const":"x"}` {
+		t.Fatalf("expected not subschema to be normalized, got %s", got)
+	}
+
+	out, err = n.Normalize(map[string]any{"type": "string", "not": false})
+	if err != nil {
+		t.Fatalf("normalize: %v", err)
+	}
+	if _, ok := out["not"]; ok {
+		t.Fatalf("expected not: false to be dropped, got %#v", out)
+	}
+
+	out, err = n.Normalize(map[string]any{"type": "string", "not": map[string]any{}})
+	if err != nil {
+		t.Fatalf("normalize: %v", err)
+	}
+	if !isBottom(out) {
+		t.Fatalf("expected not: {} to normalize to Bottom, got %#v", out)
+	}
+
+	out, err = n.Normalize(map[string]any{"allOf": []any{
+		map[string]any{"type": "string", "not": map[string]any{"const": "a"}},
+		map[string]any{"not": map[string]any{"const": "b"}},
+	}})
+	if err != nil {
+		t.Fatalf("normalize: %v", err)
+	}
+	if got := canonicalKey(out["not"]); got != `{"anyOf":[{"const":"a"},{"const":"b"}]}` {
+		t.Fatalf("expected allOf nots to merge into anyOf, got %s", got)
+	}
+}
+
+func TestNormalize_DependentRequired(t *testing.T) {
+	n := &Normalizer{Root: map[string]any{}}
+	out, err := n.Normalize(map[string]any{
+		"type":              "object",
+		"dependentRequired": map[string]any{"a": []any{"c", "b", "c"}, "b": []any{}},
+	})
+	if err != nil {
+		t.Fatalf("normalize: %v", err)
+	}
+	got, _ := CanonicalString(out["dependentRequired"])
+	if got != `{"a":["b","c"]}` {
+		t.Fatalf("unexpected dependentRequired: %s", got)
+	}
+
+	out, err = n.Normalize(map[string]any{"allOf": []any{
+		map[string]any{"dependentRequired": map[string]any{"a": []any{"b"}}},
+		map[string]any{"dependentRequired": map[string]any{"a": []any{"c"}, "d": []any{"e"}}},
+	}})
+	if err != nil {
+		t.Fatalf("normalize allOf: %v", err)
+	}
+	got, _ = CanonicalString(out["dependentRequired"])
+	if got != `{"a":["b","c"],"d":["e"]}` {
+		t.Fatalf("unexpected merged dependentRequired: %s", got)
+	}
+
+	if _, err := n.Normalize(map[string]any{"dependentRequired": map[string]any{"a": "b"}}); err == nil {
+		t.Fatal("expected error for non-array dependency list")
+	}
+}
+
+func TestNormalize_Conditional(t *testing.T) {
+	n := &Normalizer{Root: map[string]any{}}
+
+	out, err := n.Normalize(map[string]any{
+		"type": "object",
+		"if":   map[string]any{"required": []any{"a"}, "title": "has a"},
+		"then": map[string]any{"required": []any{"b"}},
+		"else": true,
+	})
+	if err != nil {
+		t.Fatalf("normalize: %v", err)
+	}
+	if got := canonicalKey(out); got != `{"if":{"required":["a"]},"then":{"required":["b"]},"type":["object"]}` {
+		t.Fatalf("unexpected normalized conditional: %s", got)
+	}
+
+	for _, in := range []map[string]any{
+		{"type": "object", "if": map[string]any{"required": []any{"a"}}},
+		{"type": "object", "if": map[string]any{"required": []any{"a"}}, "then": map[string]any{}, "else": true},
+		{"type": "object", "then": map[string]any{"required": []any{"b"}}},
+	} {
+		out, err := n.Normalize(in)
+		if err != nil {
+			t.Fatalf("normalize: %v", err)
+		}
+		if got := canonicalKey(out); got != `{"type":["object"]}` {
+			t.Fatalf("expected ineffective conditional to be dropped, got %s", got)
+		}
+	}
+
+	_, err = n.Normalize(map[string]any{"allOf": []any{
+		map[string]any{"if": map[string]any{"required": []any{"a"}}, "then": map[string]any{"required": []any{"b"}}},
+		map[string]any{"if": map[string]any{"required": []any{"c"}}, "then": map[string]any{"required": []any{"d"}}},
+	}})
+	var ope *OutsideProfileError
+	if !errors.As(err, &ope) {
+		t.Fatalf("expected OutsideProfileError for differing allOf conditionals, got %v", err)
+	}
+}
+
+func TestNormalizeCanonical_EqualForEquivalentSchemas(t *testing.T) {
+	n := &Normalizer{Root: map[string]any{"schemas": map[string]any{"Name": map[string]any{"type": "string"}}}}
+	a, err := n.NormalizeCanonical(map[string]any{
+		"type":       "object",
+		"title":      "Person",
+		"required":   []any{"name", "id"},
+		"properties": map[string]any{"name": map[string]any{"$ref": "#/schemas/Name"}, "id": map[string]any{"type": "string"}},
+	})
+	if err != nil {
+		t.Fatalf("normalize a: %v", err)
+	}
+	b, err := n.NormalizeCanonical(map[string]any{
+		"properties": map[string]any{"id": map[string]any{"type": []any{"string"}}, "name": map[string]any{"type": "string"}},
+		"required":   []any{"id", "name"},
+		"type":       []any{"object"},
+	})
+	if err != nil {
+		t.Fatalf("normalize b: %v", err)
+	}
+	if string(a) != string(b) {
+		t.Fatalf("expected identical canonical bytes:\n%s\n%s", a, b)
+	}
+	want := `{"properties":{"id":{"type":["string"]},"name":{"type":["string"]}},"required":["id","name"],"type":["object"]}`
+	if string(a) != want {
+		t.Fatalf("got %s, want %s", a, want)
+	}
+
+	if _, err := n.NormalizeCanonical(map[string]any{"pattern": "x"}); err == nil {
+		t.Fatalf("expected error for out-of-profile schema")
+	}
+
+	// Union variants are ordered canonically too.
+	c, err := n.NormalizeCanonical(map[string]any{"oneOf": []any{map[string]any{"type": "string"}, map[string]any{"type": "integer", "minimum": 1}}})
+	if err != nil {
+		t.Fatalf("normalize c: %v", err)
+	}
+	d, err := n.NormalizeCanonical(map[string]any{"oneOf": []any{map[string]any{"minimum": 1, "type": []any{"integer"}}, map[string]any{"type": "string", "title": "S"}}})
+	if err != nil {
+		t.Fatalf("normalize d: %v", err)
+	}
+	if string(c) != string(d) {
+		t.Fatalf("expected identical canonical bytes for reordered unions:\n%s\n%s", c, d)
+	}
+
+	embedded, err := json.Marshal(map[string]any{"schema": c})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"schema":` + string(c) + `}`; string(embedded) != want {
+		t.Fatalf("expected canonical form to embed as JSON, got %s", embedded)
+	}
+}
+
+func TestAllOf_MultipleOfLeastCommonMultiple(t *testing.T) {
+	n := &Normalizer{Root: map[string]any{}}
+	for _, tc := range []struct {
+		a, b any
+		want string
+	}{
+		{0.01, 0.05, "0.05"},
+		{0.05, 0.01, "0.05"},
+		{0.02, 0.03, "0.06"},
+		{4.0, 6.0, "12"},
+		{json.Number("0.1"), json.Number("0.25"), "0.5"},
+	} {
+		out, err := n.Normalize(map[string]any{"allOf": []any{
+			map[string]any{"type": "number", "multipleOf": tc.a},
+			map[string]any{"multipleOf": tc.b},
+		}})
+		if err != nil {
+			t.Fatalf("normalize %v/%v: %v", tc.a, tc.b, err)
+		}
+		if got := canonicalKey(out["multipleOf"]); got != tc.want {
+			t.Fatalf("lcm(%v, %v): got %s, want %s", tc.a, tc.b, got, tc.want)
+		}
+	}
+
+	// The result is exact, beyond what a float64 can hold.
+	out, err := n.Normalize(map[string]any{"allOf": []any{
+		map[string]any{"type": "number", "multipleOf": json.Number("0.1")},
+		map[string]any{"multipleOf": json.Number("0.30000000000000000001")},
+	}})
+	if err != nil {
+		t.Fatalf("normalize: %v", err)
+	}
+	if got, want := out["multipleOf"], json.Number("3000000000000000000.1"); got != want {
+		t.Fatalf("lcm: got %#v, want %#v", got, want)
+	}
+}
+
+func TestNormalize_TupleItems(t *testing.T) {
+	n := &Normalizer{Root: map[string]any{}}
+
+	out, err := n.Normalize(map[string]any{"type": "array", "items": []any{
+		map[string]any{"type": "string", "title": "first"},
+		true,
+	}})
+	if err != nil {
+		t.Fatalf("normalize: %v", err)
+	}
+	if _, ok := out["items"]; ok {
+		t.Fatalf("expected array-form items to be rewritten, got %#v", out)
+	}
+	if got := canonicalKey(out["prefixItems"]); got != `[{"type":["string"]},{}]` {
+		t.Fatalf("unexpected prefixItems %s", got)
+	}
+
+	_, err = n.Normalize(map[string]any{"items": []any{}, "prefixItems": []any{}})
+	if err == nil || !strings.Contains(err.Error(), "cannot be combined with prefixItems") {
+		t.Fatalf("expected error for items array with prefixItems, got %v", err)
+	}
+
+	out, err = n.Normalize(map[string]any{"allOf": []any{
+		map[string]any{"type": "array", "prefixItems": []any{map[string]any{"type": "string"}}, "items": map[string]any{"type": "number"}},
+		map[string]any{"prefixItems": []any{map[string]any{"minLength": 1}, map[string]any{"minimum": 0}, map[string]any{"maximum": 9}}},
+	}})
+	if err != nil {
+		t.Fatalf("normalize allOf: %v", err)
+	}
+	want := `[{"minLength":1,"type":["string"]},{"minimum":0,"type":["number"]},{"maximum":9,"type":["number"]}]`
+	if got := canonicalKey(out["prefixItems"]); got != want {
+		t.Fatalf("allOf prefixItems: got %s, want %s", got, want)
+	}
+}
+
+func TestNormalizer_ConcurrentUse(t *testing.T) {
+	n := &Normalizer{Root: map[string]any{
+		"schemas": map[string]any{
+			"Name": map[string]any{"type": "string", "minLength": 1},
+			"Person": map[string]any{
+				"type":       "object",
+				"required":   []any{"name"},
+				"properties": map[string]any{"name": map[string]any{"$ref": "#/schemas/Name"}},
+			},
+			"Loop": map[string]any{"$ref": "#/schemas/Loop"},
+		},
+	}}
+	person := map[string]any{"$ref": "#/schemas/Person"}
+	wider := map[string]any{"type": "object", "properties": map[string]any{"name": map[string]any{"type": "string"}}}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 64)
+	for g := 0; g < 16; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				if _, err := n.Normalize(person); err != nil {
+					errs <- fmt.Errorf("normalize: %w", err)
+					return
+				}
+				if ok, reason, err := n.InputCompatible(person, wider); err != nil || !ok {
+					errs <- fmt.Errorf("input compatible: ok=%v reason=%q err=%v", ok, reason, err)
+					return
+				}
+				if ok, _, err := n.OutputCompatible(person, wider); err != nil || ok {
+					errs <- fmt.Errorf("output compatible: ok=%v err=%v", ok, err)
+					return
+				}
+				var re *RefError
+				if _, err := n.Normalize(map[string]any{"$ref": "#/schemas/Loop"}); !errors.As(err, &re) {
+					errs <- fmt.Errorf("expected cycle RefError, got %v", err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatal(err)
+	}
+}
+
+func TestCtxVariants_AbortWhenContextDone(t *testing.T) {
+	schema := map[string]any{
+		"type":       "object",
+		"properties": map[string]any{"a": map[string]any{"oneOf": []any{map[string]any{"type": "string"}, map[string]any{"type": "integer"}}}},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	n := &Normalizer{Root: map[string]any{}}
+	if _, err := n.NormalizeCtx(ctx, schema); !errors.Is(err, context.Canceled) {
+		t.Fatalf("NormalizeCtx: expected context.Canceled, got %v", err)
+	}
+	if _, _, err := n.InputCompatibleCtx(ctx, schema, schema); !errors.Is(err, context.Canceled) {
+		t.Fatalf("InputCompatibleCtx: expected context.Canceled, got %v", err)
+	}
+	if _, _, err := n.OutputCompatibleCtx(ctx, schema, schema); !errors.Is(err, context.Canceled) {
+		t.Fatalf("OutputCompatibleCtx: expected context.Canceled, got %v", err)
+	}
+
+	ok, _, err := n.InputCompatibleCtx(context.Background(), schema, schema)
+	if err != nil || !ok {
+		t.Fatalf("expected a live context to compare normally, got %v, %v", ok, err)
+	}
+}
+
+func TestNormalize_MaxDepth(t *testing.T) {
+	nested := func(depth int, wrap func(inner map[string]any) map[string]any) map[string]any {
+		s := map[string]any{"type": "string"}
+		for i := 1; i < depth; i++ {
+			s = wrap(s)
+		}
+		return s
+	}
+	viaProperties := func(inner map[string]any) map[string]any {
+		return map[string]any{"type": "object", "properties": map[string]any{"a": inner}}
+	}
+	viaItems := func(inner map[string]any) map[string]any {
+		return map[string]any{"type": "array", "items": inner}
+	}
+
+	n := &Normalizer{Root: map[string]any{}}
+	if _, err := n.Normalize(nested(DefaultMaxDepth, viaProperties)); err != nil {
+		t.Fatalf("expected depth %d to normalize, got %v", DefaultMaxDepth, err)
+	}
+	for name, wrap := range map[string]func(map[string]any) map[string]any{"properties": viaProperties, "items": viaItems} {
+		_, err := n.Normalize(nested(DefaultMaxDepth+1, wrap))
+		var se *SchemaError
+		if !errors.As(err, &se) || !strings.Contains(se.Message, "maximum depth 256") {
+			t.Fatalf("%s: expected depth SchemaError, got %v", name, err)
+		}
+	}
+
+	small := &Normalizer{Root: map[string]any{}, MaxDepth: 3}
+	if _, err := small.Normalize(nested(4, viaItems)); err == nil {
+		t.Fatal("expected MaxDepth 3 to reject depth 4")
+	}
+	unlimited := &Normalizer{Root: map[string]any{}, MaxDepth: -1}
+	if _, err := unlimited.Normalize(nested(2*DefaultMaxDepth, viaItems)); err != nil {
+		t.Fatalf("expected negative MaxDepth to disable the limit, got %v", err)
+	}
+}
+
+func TestNormalize_OutsideProfilePolicy(t *testing.T) {
+	schema := map[string]any{
+		"type":          "object",
+		"propertyNames": map[string]any{"maxLength": 8},
+		"properties": map[string]any{
+			"name": map[string]any{"type": "string", "minLength": 1, "pattern": "^[a-z]+$", "contentEncoding": "base64"},
+			"tags": map[string]any{"allOf": []any{
+				map[string]any{"type": "array", "uniqueItems": true},
+				map[string]any{"maxItems": 3},
+			}},
+		},
+		"required": []any{"name"},
+	}
+
+	var ope *OutsideProfileError
+	if _, err := (&Normalizer{}).Normalize(schema); !errors.As(err, &ope) {
+		t.Fatalf("expected FailClosed by default, got %v", err)
+	}
+
+	want := map[string]any{
+		"type":       []any{"object"},
+		"required":   []any{"name"},
+		"properties": map[string]any{
+			"name": map[string]any{"type": []any{"string"}, "minLength": 1},
+			"tags": map[string]any{"type": []any{"array"}, "maxItems": 3},
+		},
+	}
+	strip := &Normalizer{OnOutsideProfile: StripAndContinue, CacheEnabled: true}
+	out, err := strip.Normalize(schema)
+	if err != nil {
+		t.Fatalf("strip: %v", err)
+	}
+	if !reflect.DeepEqual(out, want) {
+		t.Fatalf("strip: unexpected result %#v", out)
+	}
+	res, err := strip.NormalizeWithReport(schema)
+	if err != nil || res.OutsideProfile != nil {
+		t.Fatalf("strip: expected no records, got %v, %v", res, err)
+	}
+
+	collect := &Normalizer{OnOutsideProfile: CollectAndContinue, CacheEnabled: true}
+	for i := 0; i < 2; i++ { // the second call would be a cache hit
+		res, err := collect.NormalizeWithReport(schema)
+		if err != nil {
+			t.Fatalf("collect: %v", err)
+		}
+		if !reflect.DeepEqual(res.Schema, want) {
+			t.Fatalf("collect: unexpected result %#v", res.Schema)
+		}
+		got := make([]string, len(res.OutsideProfile))
+		for i, e := range res.OutsideProfile {
+			got[i] = e.Error()
+		}
+		wantRecords := []string{
+			`outside profile at <root>: keyword "propertyNames"`,
+			`outside profile at properties["name"]: keyword "contentEncoding"`,
+			`outside profile at properties["name"]: keyword "pattern"`,
+			`outside profile at properties["tags"].allOf[0]: keyword "uniqueItems"`,
+		}
+		if !reflect.DeepEqual(got, wantRecords) {
+			t.Fatalf("collect: unexpected records\n got %q\nwant %q", got, wantRecords)
+		}
+	}
+
+	// Structural limits fail under every policy.
+	union := map[string]any{"allOf": []any{map[string]any{"oneOf": []any{map[string]any{"type": "string"}}}}}
+	if _, err := collect.Normalize(union); !errors.As(err, &ope) {
+		t.Fatalf("expected oneOf inside allOf to fail, got %v", err)
+	}
+
+	// Switching back to FailClosed must not be served a stripped cached result.
+	strip.OnOutsideProfile = FailClosed
+	if _, err := strip.Normalize(schema); !errors.As(err, &ope) {
+		t.Fatalf("expected FailClosed after a stripping call, got %v", err)
+	}
+}
+
+func TestPackageCompatible(t *testing.T) {
+	target := map[string]any{
+		"type":       "object",
+		"required":   []any{"id"},
+		"properties": map[string]any{"id": map[string]any{"type": "string"}},
+	}
+	wider := map[string]any{
+		"type":       "object",
+		"properties": map[string]any{"id": map[string]any{"type": "string"}},
+	}
+	if ok, err := InputCompatible(target, wider); err != nil || !ok {
+		t.Fatalf("InputCompatible = %v, %v; want true", ok, err)
+	}
+	if ok, err := OutputCompatible(target, wider); err != nil || ok {
+		t.Fatalf("OutputCompatible = %v, %v; want false", ok, err)
+	}
+
+	for _, ref := range []string{"#", "#/schemas/Id", "other.json", "https://example.com/id.json"} {
+		withRef := map[string]any{
+			"type":       "object",
+			"properties": map[string]any{"id": map[string]any{"$ref": ref}},
+		}
+		_, err := InputCompatible(target, withRef)
+		var re *RefError
+		if !errors.As(err, &re) || re.Ref != ref || !strings.Contains(err.Error(), "use a Normalizer with Root set") {
+			t.Fatalf("$ref %q: expected a RefError, got %v", ref, err)
+		}
+	}
+}
+
+func TestNormalizer_WithRoot(t *testing.T) {
+	n := &Normalizer{NumericTolerance: 0.5, Root: map[string]any{"schemas": map[string]any{"Id": map[string]any{"type": "string"}}}}
+	other := n.WithRoot(map[string]any{"schemas": map[string]any{"Id": map[string]any{"type": "integer"}}})
+	if other.NumericTolerance != 0.5 {
+		t.Fatalf("WithRoot dropped settings: %+v", other)
+	}
+	ref := map[string]any{"$ref": "#/schemas/Id"}
+	for _, c := range []struct {
+		n    *Normalizer
+		want string
+	}{{n, "string"}, {other, "integer"}} {
+		out, err := c.n.Normalize(ref)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := map[string]any{"type": []any{c.want}}; !reflect.DeepEqual(out, want) {
+			t.Fatalf("Normalize = %v, want %v", out, want)
+		}
+	}
+}
+
+func TestNormalizeWithReport_DedupesRecords(t *testing.T) {
+	// The $ref's siblings and its nested allOf branch all merge at allOf[0], so
+	// the dropped keyword is reached there twice.
+	schema := map[string]any{
+		"$defs": map[string]any{"Code": map[string]any{"type": "string", "minLength": 2}},
+		"allOf": []any{map[string]any{
+			"$ref":            "#/$defs/Code",
+			"contentEncoding": "base64",
+			"allOf":           []any{map[string]any{"$ref": "#/$defs/Code", "contentEncoding": "base64"}},
+		}},
+	}
+	n := &Normalizer{OnOutsideProfile: CollectAndContinue, Root: schema}
+	res, err := n.NormalizeWithReport(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make([]string, len(res.OutsideProfile))
+	for i, e := range res.OutsideProfile {
+		got[i] = e.Error()
+	}
+	want := []string{`outside profile at allOf[0]: keyword "contentEncoding"`}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected records\n got %q\nwant %q", got, want)
+	}
+}
@@ -0,0 +1,180 @@
+package openbindings
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SchemaRef is a $ref found inside an embedded JSON Schema.
+type SchemaRef struct {
+	// Path is the RFC 6901 JSON Pointer of the object holding the $ref
+	// (e.g. "/operations/getUser/input/properties/owner").
+	Path string
+	// Ref is the $ref value as written.
+	Ref string
+}
+
+// SchemaRefs returns every $ref that appears in the document's embedded schemas:
+// the schemas map and each operation's input and output. Results are ordered by
+// Path, so the output is deterministic.
+func (i Interface) SchemaRefs() []SchemaRef {
+	var refs []SchemaRef
+	collect := func(ptr string, schema JSONSchema) {
+		walkSchemaObjects(map[string]any(schema), ptr, func(p string, m map[string]any) {
+			if ref, ok := m["$ref"].(string); ok {
+				refs = append(refs, SchemaRef{Path: p, Ref: ref})
+			}
+		})
+	}
+
+	for _, k := range sortedKeys(i.Schemas) {
+		collect("/schemas/"+escapePointerToken(k), i.Schemas[k])
+	}
+	for _, k := range sortedKeys(i.Operations) {
+		op := i.Operations[k]
+		base := "/operations/" + escapePointerToken(k)
+		if op.Input != nil {
+			collect(base+"/input", op.Input)
+		}
+		if op.Output != nil {
+			collect(base+"/output", op.Output)
+		}
+	}
+
+	sort.SliceStable(refs, func(a, b int) bool { return refs[a].Path < refs[b].Path })
+	return refs
+}
+
+// localSchemaRefPrefix starts every reference into the document's schemas map.
+const localSchemaRefPrefix = "#/schemas/"
+
+// localSchemaRefTokens splits a "#/schemas/..." reference into unescaped JSON
+// Pointer tokens below the schemas map. ok is false for any other reference.
+func localSchemaRefTokens(ref string) (toks []string, ok bool) {
+	if !strings.HasPrefix(ref, localSchemaRefPrefix) {
+		return nil, false
+	}
+	toks = strings.Split(strings.TrimPrefix(ref, localSchemaRefPrefix), "/")
+	for idx := range toks {
+		toks[idx] = unescapePointerToken(toks[idx])
+	}
+	return toks, true
+}
+
+// ResolveSchemaRef resolves a "#/schemas/..." reference against i.Schemas. The
+// pointer may descend into a schema (e.g. "#/schemas/User/properties/id"). ok is
+// false for references elsewhere, and for ones that do not reach a schema object.
+// The result shares storage with i.Schemas.
+func (i Interface) ResolveSchemaRef(ref string) (JSONSchema, bool) {
+	toks, ok := localSchemaRefTokens(ref)
+	if !ok {
+		return nil, false
+	}
+	root, ok := i.Schemas[toks[0]]
+	if !ok {
+		return nil, false
+	}
+	var cur any = map[string]any(root)
+	for _, tok := range toks[1:] {
+		switch x := cur.(type) {
+		case map[string]any:
+			if cur, ok = x[tok]; !ok {
+				return nil, false
+			}
+		case []any:
+			idx, err := strconv.Atoi(tok)
+			if err != nil || idx < 0 || idx >= len(x) {
+				return nil, false
+			}
+			cur = x[idx]
+		default:
+			return nil, false
+		}
+	}
+	m, ok := cur.(map[string]any)
+	return JSONSchema(m), ok
+}
+
+// ResolveSchema dereferences schema's top-level "#/schemas/..." $ref, following
+// schemas that are themselves only a $ref, and returns a copy of the schema reached.
+// Keywords beside the $ref still apply, so with any present the result is
+// {"allOf": [reached, siblings]}. Nested references are left as they are; a schema
+// without a top-level $ref is returned as a copy. References outside the schemas
+// map, unresolvable ones, and alias cycles are errors.
+func (i Interface) ResolveSchema(schema JSONSchema) (JSONSchema, error) {
+	ref, ok := schema["$ref"].(string)
+	if !ok {
+		return schema.Clone(), nil
+	}
+	seen := map[string]bool{}
+	target := schema
+	for {
+		if seen[ref] {
+			return nil, fmt.Errorf("openbindings: $ref cycle through %q", ref)
+		}
+		seen[ref] = true
+		if _, local := localSchemaRefTokens(ref); !local {
+			return nil, fmt.Errorf("openbindings: unsupported $ref %q", ref)
+		}
+		next, ok := i.ResolveSchemaRef(ref)
+		if !ok {
+			return nil, fmt.Errorf("openbindings: $ref %q does not resolve", ref)
+		}
+		target = next
+		nextRef, isRef := target["$ref"].(string)
+		if !isRef || len(target) != 1 {
+			break
+		}
+		ref = nextRef
+	}
+	if len(schema) == 1 {
+		return target.Clone(), nil
+	}
+	siblings := schema.Clone()
+	delete(siblings, "$ref")
+	return JSONSchema{"allOf": []any{map[string]any(target.Clone()), map[string]any(siblings)}}, nil
+}
+
+// walkSchemaObjects calls fn for the schema v and every schema nested inside it,
+// in deterministic (sorted key) order, passing each schema's JSON Pointer. Values
+// of value keywords (const, enum, default, examples) and x- extensions are not
+// schemas and are skipped.
+func walkSchemaObjects(v any, ptr string, fn func(ptr string, m map[string]any)) {
+	switch x := v.(type) {
+	case map[string]any:
+		fn(ptr, x)
+		for _, k := range sortedKeys(x) {
+			if _, ok := schemaValueKeywords[k]; ok || strings.HasPrefix(k, "x-") {
+				continue
+			}
+			child := ptr + "/" + escapePointerToken(k)
+			if _, ok := schemaMapKeywords[k]; ok {
+				if named, ok := x[k].(map[string]any); ok {
+					for _, name := range sortedKeys(named) {
+						walkSchemaObjects(named[name], child+"/"+escapePointerToken(name), fn)
+					}
+					continue
+				}
+			}
+			walkSchemaObjects(x[k], child, fn)
+		}
+	case []any:
+		for idx, item := range x {
+			walkSchemaObjects(item, ptr+"/"+strconv.Itoa(idx), fn)
+		}
+	}
+}
+
+// escapePointerToken escapes a JSON Pointer reference token per RFC 6901.
+func escapePointerToken(s string) string {
+	s = strings.ReplaceAll(s, "~", "~0")
+	return strings.ReplaceAll(s, "/", "~1")
+}
+
+// unescapePointerToken reverses escapePointerToken.
+func unescapePointerToken(s string) string {
+	s = strings.ReplaceAll(s, "~1", "/")
+	return strings.ReplaceAll(s, "~0", "~")
+}
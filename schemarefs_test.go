@@ -0,0 +1,117 @@
+package openbindings
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInterface_SchemaRefs(t *testing.T) {
+	i := Interface{
+		Schemas: map[string]JSONSchema{
+			"User": {"type": "object", "properties": map[string]any{
+				"org": map[string]any{"$ref": "#/schemas/Org"},
+			}},
+			"a/b": {"anyOf": []any{map[string]any{"$ref": "#/schemas/User"}}},
+		},
+		Operations: map[string]Operation{
+			"getUser": {
+				Input:  JSONSchema{"type": "object"},
+				Output: JSONSchema{"$ref": "#/schemas/User"},
+			},
+		},
+	}
+	want := []SchemaRef{
+		{Path: "/operations/getUser/output", Ref: "#/schemas/User"},
+		{Path: "/schemas/User/properties/org", Ref: "#/schemas/Org"},
+		{Path: "/schemas/a~1b/anyOf/0", Ref: "#/schemas/User"},
+	}
+	if got := i.SchemaRefs(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected refs:\n got: %+v\nwant: %+v", got, want)
+	}
+}
+
+func TestInterface_SchemaRefs_SkipsValuesAndExtensions(t *testing.T) {
+	var i Interface
+	mustUnmarshalJSON(t, []byte(`{
+  "openbindings": "0.1.0",
+  "schemas": {
+    "Doc": {"examples": [{"$ref": "#/schemas/Nope"}]},
+    "Ref": {
+      "const": {"$ref": "#/schemas/Nope"},
+      "default": {"$ref": "#/schemas/Nope"},
+      "enum": [{"$ref": "#/schemas/Nope"}],
+      "x-source": {"$ref": "#/schemas/Nope"},
+      "properties": {"const": {"$ref": "#/schemas/Doc"}}
+    }
+  },
+  "operations": {}
+}`), &i)
+	want := []SchemaRef{{Path: "/schemas/Ref/properties/const", Ref: "#/schemas/Doc"}}
+	if got := i.SchemaRefs(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected refs:\n got: %+v\nwant: %+v", got, want)
+	}
+}
+
+func TestInterface_ResolveSchemaRef(t *testing.T) {
+	i := Interface{Schemas: map[string]JSONSchema{
+		"User": {"type": "object", "properties": map[string]any{"id": map[string]any{"type": "string"}}},
+		"a/b":  {"type": "integer"},
+	}}
+	cases := []struct {
+		ref  string
+		want string
+		ok   bool
+	}{
+		{ref: "#/schemas/User", want: `{"properties":{"id":{"type":"string"}},"type":"object"}`, ok: true},
+		{ref: "#/schemas/User/properties/id", want: `{"type":"string"}`, ok: true},
+		{ref: "#/schemas/a~1b", want: `{"type":"integer"}`, ok: true},
+		{ref: "#/schemas/Missing"},
+		{ref: "#/schemas/User/type"},
+		{ref: "#/$defs/User"},
+		{ref: "https://example.com/user.json"},
+	}
+	for _, tc := range cases {
+		got, ok := i.ResolveSchemaRef(tc.ref)
+		if ok != tc.ok {
+			t.Fatalf("%s: ok = %v, want %v", tc.ref, ok, tc.ok)
+		}
+		if ok && string(mustMarshalJSON(t, got)) != tc.want {
+			t.Fatalf("%s: got %s, want %s", tc.ref, mustMarshalJSON(t, got), tc.want)
+		}
+	}
+}
+
+func TestInterface_ResolveSchema(t *testing.T) {
+	i := Interface{Schemas: map[string]JSONSchema{
+		"User":  {"type": "object"},
+		"Alias": {"$ref": "#/schemas/User"},
+		"Loop1": {"$ref": "#/schemas/Loop2"},
+		"Loop2": {"$ref": "#/schemas/Loop1"},
+	}}
+
+	got, err := i.ResolveSchema(JSONSchema{"$ref": "#/schemas/Alias"})
+	if err != nil {
+		t.Fatalf("ResolveSchema: %v", err)
+	}
+	if s := string(mustMarshalJSON(t, got)); s != `{"type":"object"}` {
+		t.Fatalf("got %s", s)
+	}
+	got["type"] = "string"
+	if i.Schemas["User"]["type"] != "object" {
+		t.Fatal("ResolveSchema result shares storage with Schemas")
+	}
+
+	got, err = i.ResolveSchema(JSONSchema{"$ref": "#/schemas/User", "required": []any{"id"}})
+	if err != nil {
+		t.Fatalf("ResolveSchema: %v", err)
+	}
+	if s := string(mustMarshalJSON(t, got)); s != `{"allOf":[{"type":"object"},{"required":["id"]}]}` {
+		t.Fatalf("got %s", s)
+	}
+
+	for _, ref := range []string{"#/schemas/Loop1", "#/schemas/Missing", "other.json#/User"} {
+		if _, err := i.ResolveSchema(JSONSchema{"$ref": ref}); err == nil {
+			t.Fatalf("%s: expected error", ref)
+		}
+	}
+}
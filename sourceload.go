@@ -0,0 +1,118 @@
+package openbindings
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/openbindings/openbindings-go/formattoken"
+)
+
+// sourceVersionKeys maps format names to the top-level member in which their
+// documents declare the version they follow.
+var sourceVersionKeys = map[string]string{
+	"openapi":      "openapi",
+	"asyncapi":     "asyncapi",
+	"openbindings": "openbindings",
+}
+
+// sourceLoads checks source locations for WithSourceLoader, loading each distinct
+// location once. decode parses the loaded documents; if nil, they are parsed as JSON.
+type sourceLoads struct {
+	load   func(location string) ([]byte, error)
+	decode func(data []byte) (map[string]any, error)
+	cache  map[string]loadedSource
+}
+
+type loadedSource struct {
+	data []byte
+	err  error
+}
+
+// check loads the location of src and reports a load failure, or a version the
+// loaded document declares that does not match src.Format.
+func (s *sourceLoads) check(errs *problemList, l location, src Source) {
+	tok, err := formattoken.Parse(src.Format)
+	if err != nil {
+		return // reported by the format check
+	}
+	key, ok := sourceVersionKeys[tok.Name]
+	if !ok {
+		return
+	}
+
+	loc := strings.TrimSpace(src.Location)
+	if s.cache == nil {
+		s.cache = map[string]loadedSource{}
+	}
+	ls, ok := s.cache[loc]
+	if !ok {
+		ls.data, ls.err = s.load(loc)
+		s.cache[loc] = ls
+	}
+	if ls.err != nil {
+		errs.add(l.field("location"), ProblemInvalidSource, "cannot load: %v", ls.err)
+		return
+	}
+
+	decode := s.decode
+	if decode == nil {
+		decode = decodeJSONObject
+	}
+	top, err := decode(ls.data)
+	if err != nil || top == nil {
+		errs.add(l.field("location"), ProblemInvalidSource, "location content is not an object")
+		return
+	}
+	version := scalarString(top[key])
+	if version == "" {
+		errs.add(l, ProblemFormatMismatch, "location content does not declare %s, expected %s", key, tok.Version)
+		return
+	}
+	if !versionHasPrefix(version, tok.Version) {
+		errs.add(l, ProblemFormatMismatch, "location content declares %s %s, expected %s", key, version, tok.Version)
+	}
+}
+
+// decodeJSONObject parses a JSON object, keeping numbers as written.
+func decodeJSONObject(data []byte) (map[string]any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var m map[string]any
+	if err := dec.Decode(&m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// scalarString returns a string or number value as text, and "" for anything else.
+func scalarString(v any) string {
+	switch x := v.(type) {
+	case string:
+		return x
+	case json.Number:
+		return x.String()
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	case int:
+		return strconv.Itoa(x)
+	}
+	return ""
+}
+
+// versionHasPrefix reports whether the dot-separated components of prefix lead
+// those of version, so that "3.1" covers "3.1" and "3.1.1" but not "3.10".
+func versionHasPrefix(version, prefix string) bool {
+	v := strings.Split(version, ".")
+	p := strings.Split(prefix, ".")
+	if len(p) > len(v) {
+		return false
+	}
+	for i := range p {
+		if v[i] != p[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -1,21 +1,53 @@
 package openbindings
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"strings"
 )
 
 // JSONSchema is intentionally untyped to avoid coupling to any one JSON Schema library.
 // This preserves arbitrary keys/values structurally, but not raw JSON bytes (use canonicaljson.Marshal if you need stable bytes).
+// Boolean schemas and raw bytes need RawSchema.
+//
+// Numbers decode as json.Number rather than float64, so a schema re-encodes them as
+// written: "minimum": 1.0 stays 1.0 instead of becoming 1, and integers beyond
+// float64 precision survive. Code reading schema values should accept json.Number.
 type JSONSchema map[string]any
 
+// UnmarshalJSON decodes a schema object with numbers as json.Number. As when
+// decoding into a map, null leaves s unchanged and entries are added to an
+// existing map.
+func (s *JSONSchema) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var m map[string]any
+	if err := dec.Decode(&m); err != nil {
+		return err
+	}
+	if m == nil {
+		return nil
+	}
+	if *s == nil {
+		*s = m
+		return nil
+	}
+	for k, v := range m {
+		(*s)[k] = v
+	}
+	return nil
+}
+
 // LosslessFields is embedded in every typed OpenBindings struct to preserve
 // JSON fields that the SDK does not (yet) model. Extensions holds keys starting
 // with "x-"; Unknown holds all other unrecognised keys. During marshaling,
 // typed fields always win over colliding Unknown/Extension entries.
 //
-// Each lossless type requires a parallel wire struct for encoding — when adding
-// fields to a typed struct, update both the public type and its wire counterpart.
+// Each lossless type requires a parallel wire struct for encoding, whose field method
+// maps JSON keys to its fields for decoding — when adding fields to a typed struct,
+// update the public type, its wire counterpart, and the wire field method.
 type LosslessFields struct {
 	// Extensions preserves `x-*` fields at the object level.
 	// It is populated by UnmarshalJSON and included by MarshalJSON.
@@ -26,36 +58,6 @@ type LosslessFields struct {
 	Unknown map[string]json.RawMessage `json:"-"`
 }
 
-// Pre-computed known field sets for efficient lossless JSON unmarshaling.
-// These are computed once at package init to avoid repeated allocations.
-var (
-	knownSatisfiesSet = knownSet(
-		"role", "operation",
-	)
-	knownOperationSet = knownSet(
-		"description", "deprecated", "tags", "aliases", "satisfies",
-		"idempotent", "input", "output", "examples",
-	)
-	knownOperationExampleSet = knownSet(
-		"description", "input", "output",
-	)
-	knownSourceSet = knownSet(
-		"format", "location", "content", "description", "priority",
-	)
-	knownBindingEntrySet = knownSet(
-		"operation", "source", "ref", "priority", "description", "deprecated",
-		"security", "inputTransform", "outputTransform",
-	)
-	knownTransformSet = knownSet(
-		"type", "expression",
-	)
-	knownInterfaceSet = knownSet(
-		"openbindings", "name", "version", "description",
-		"schemas", "operations", "roles",
-		"sources", "bindings", "security", "transforms",
-	)
-)
-
 type Satisfies struct {
 	Role      string `json:"role"`
 	Operation string `json:"operation"`
@@ -67,14 +69,20 @@ type satisfiesWire struct {
 	Operation string `json:"operation"`
 }
 
-func (s *Satisfies) UnmarshalJSON(b []byte) error {
-	var raw map[string]json.RawMessage
-	if err := json.Unmarshal(b, &raw); err != nil {
-		return err
+func (w *satisfiesWire) field(key string) any {
+	switch key {
+	case "role":
+		return &w.Role
+	case "operation":
+		return &w.Operation
 	}
+	return nil
+}
 
+func (s *Satisfies) UnmarshalJSON(b []byte) error {
 	var w satisfiesWire
-	if err := json.Unmarshal(b, &w); err != nil {
+	extensions, unknown, err := unmarshalLossless(b, w.field)
+	if err != nil {
 		return err
 	}
 
@@ -83,7 +91,7 @@ func (s *Satisfies) UnmarshalJSON(b []byte) error {
 		Operation: w.Operation,
 	}
 
-	s.Extensions, s.Unknown = splitLossless(raw, knownSatisfiesSet)
+	s.Extensions, s.Unknown = extensions, unknown
 	return nil
 }
 
@@ -95,6 +103,22 @@ func (s Satisfies) MarshalJSON() ([]byte, error) {
 	return marshalLossless(s.Unknown, s.Extensions, w)
 }
 
+// NewSatisfies builds a Satisfies entry after checking that both fields are non-empty
+// and that role is declared in i.Roles. It applies the same rules as Validate, so
+// builder code can fail early when assembling satisfies relationships.
+func (i Interface) NewSatisfies(role, operation string) (Satisfies, error) {
+	if strings.TrimSpace(role) == "" {
+		return Satisfies{}, errors.New("openbindings: satisfies role is required")
+	}
+	if strings.TrimSpace(operation) == "" {
+		return Satisfies{}, errors.New("openbindings: satisfies operation is required")
+	}
+	if _, ok := i.Roles[role]; !ok {
+		return Satisfies{}, fmt.Errorf("openbindings: satisfies references unknown role %q", role)
+	}
+	return Satisfies{Role: role, Operation: operation}, nil
+}
+
 // OperationExample represents an example input/output pair for an operation.
 type OperationExample struct {
 	Description string `json:"description,omitempty"`
@@ -110,14 +134,22 @@ type operationExampleWire struct {
 	Output      any    `json:"output,omitempty"`
 }
 
-func (e *OperationExample) UnmarshalJSON(b []byte) error {
-	var raw map[string]json.RawMessage
-	if err := json.Unmarshal(b, &raw); err != nil {
-		return err
+func (w *operationExampleWire) field(key string) any {
+	switch key {
+	case "description":
+		return &w.Description
+	case "input":
+		return &w.Input
+	case "output":
+		return &w.Output
 	}
+	return nil
+}
 
+func (e *OperationExample) UnmarshalJSON(b []byte) error {
 	var w operationExampleWire
-	if err := json.Unmarshal(b, &w); err != nil {
+	extensions, unknown, err := unmarshalLossless(b, w.field)
+	if err != nil {
 		return err
 	}
 
@@ -127,7 +159,7 @@ func (e *OperationExample) UnmarshalJSON(b []byte) error {
 		Output:      w.Output,
 	}
 
-	e.Extensions, e.Unknown = splitLossless(raw, knownOperationExampleSet)
+	e.Extensions, e.Unknown = extensions, unknown
 	return nil
 }
 
@@ -154,6 +186,12 @@ type Operation struct {
 	// Examples contains named example input/output pairs.
 	Examples map[string]OperationExample `json:"examples,omitempty"`
 
+	// Security lists operation-level security requirements, such as auth metadata
+	// for API gateways. EXPERIMENTAL: the spec does not define operation-level
+	// security yet (bindings reference Interface.Security instead), so the field's
+	// shape may change. Validate requires each entry to be non-empty.
+	Security []map[string]any `json:"security,omitempty"`
+
 	LosslessFields
 }
 
@@ -169,16 +207,40 @@ type operationWire struct {
 	Output     JSONSchema `json:"output,omitempty"`
 
 	Examples map[string]OperationExample `json:"examples,omitempty"`
-}
 
-func (o *Operation) UnmarshalJSON(b []byte) error {
-	var raw map[string]json.RawMessage
-	if err := json.Unmarshal(b, &raw); err != nil {
-		return err
+	Security []map[string]any `json:"security,omitempty"`
+}
+
+func (w *operationWire) field(key string) any {
+	switch key {
+	case "description":
+		return &w.Description
+	case "deprecated":
+		return &w.Deprecated
+	case "tags":
+		return &w.Tags
+	case "aliases":
+		return &w.Aliases
+	case "satisfies":
+		return &w.Satisfies
+	case "idempotent":
+		return &w.Idempotent
+	case "input":
+		return &w.Input
+	case "output":
+		return &w.Output
+	case "examples":
+		return &w.Examples
+	case "security":
+		return &w.Security
 	}
+	return nil
+}
 
+func (o *Operation) UnmarshalJSON(b []byte) error {
 	var w operationWire
-	if err := json.Unmarshal(b, &w); err != nil {
+	extensions, unknown, err := unmarshalLossless(b, w.field)
+	if err != nil {
 		return err
 	}
 
@@ -192,9 +254,10 @@ func (o *Operation) UnmarshalJSON(b []byte) error {
 		Input:       w.Input,
 		Output:      w.Output,
 		Examples:    w.Examples,
+		Security:    w.Security,
 	}
 
-	o.Extensions, o.Unknown = splitLossless(raw, knownOperationSet)
+	o.Extensions, o.Unknown = extensions, unknown
 	return nil
 }
 
@@ -209,6 +272,7 @@ func (o Operation) MarshalJSON() ([]byte, error) {
 		Input:       o.Input,
 		Output:      o.Output,
 		Examples:    o.Examples,
+		Security:    o.Security,
 	}
 	return marshalLossless(o.Unknown, o.Extensions, w)
 }
@@ -220,9 +284,29 @@ type Source struct {
 	Description string   `json:"description,omitempty"`
 	Priority    *float64 `json:"priority,omitempty"`
 
+	// ContentRaw holds inline content as the JSON bytes it was read from, when the
+	// document was decoded with WithRawSourceContent; Content is nil then. It keeps
+	// the member order of large embedded documents and skips building them as maps.
+	// MarshalJSON writes it as "content" unless Content is set. DecodeContent reads
+	// either form.
+	ContentRaw json.RawMessage `json:"-"`
+
 	LosslessFields
 }
 
+// DecodeContent returns the inline content: Content if set, otherwise ContentRaw
+// decoded as JSON, or nil if neither is set.
+func (s Source) DecodeContent() (any, error) {
+	if s.Content != nil || len(s.ContentRaw) == 0 {
+		return s.Content, nil
+	}
+	var v any
+	if err := json.Unmarshal(s.ContentRaw, &v); err != nil {
+		return nil, fmt.Errorf("openbindings: source content: %w", err)
+	}
+	return v, nil
+}
+
 type sourceWire struct {
 	Format      string   `json:"format"`
 	Location    string   `json:"location,omitempty"`
@@ -231,16 +315,47 @@ type sourceWire struct {
 	Priority    *float64 `json:"priority,omitempty"`
 }
 
-func (s *Source) UnmarshalJSON(b []byte) error {
-	var raw map[string]json.RawMessage
-	if err := json.Unmarshal(b, &raw); err != nil {
-		return err
+func (w *sourceWire) field(key string) any {
+	switch key {
+	case "format":
+		return &w.Format
+	case "location":
+		return &w.Location
+	case "content":
+		return &w.Content
+	case "description":
+		return &w.Description
+	case "priority":
+		return &w.Priority
 	}
+	return nil
+}
+
+func (s *Source) UnmarshalJSON(b []byte) error {
+	return s.unmarshal(b, false)
+}
 
+// unmarshal decodes a source, keeping inline content as raw bytes in ContentRaw
+// when rawContent is set.
+func (s *Source) unmarshal(b []byte, rawContent bool) error {
 	var w sourceWire
-	if err := json.Unmarshal(b, &w); err != nil {
+	var raw json.RawMessage
+	field := w.field
+	if rawContent {
+		field = func(key string) any {
+			if key == "content" {
+				return &raw
+			}
+			return w.field(key)
+		}
+	}
+	extensions, unknown, err := unmarshalLossless(b, field)
+	if err != nil {
 		return err
 	}
+	if string(raw) == "null" {
+		raw = nil
+	}
 
 	*s = Source{
 		Format:      w.Format,
@@ -248,9 +363,10 @@ func (s *Source) UnmarshalJSON(b []byte) error {
 		Content:     w.Content,
 		Description: w.Description,
 		Priority:    w.Priority,
+		ContentRaw:  raw,
 	}
 
-	s.Extensions, s.Unknown = splitLossless(raw, knownSourceSet)
+	s.Extensions, s.Unknown = extensions, unknown
 	return nil
 }
 
@@ -262,6 +378,9 @@ func (s Source) MarshalJSON() ([]byte, error) {
 		Description: s.Description,
 		Priority:    s.Priority,
 	}
+	if s.Content == nil && len(s.ContentRaw) > 0 {
+		w.Content = s.ContentRaw
+	}
 	return marshalLossless(s.Unknown, s.Extensions, w)
 }
 
@@ -279,14 +398,20 @@ type transformWire struct {
 	Expression string `json:"expression"`
 }
 
-func (t *Transform) UnmarshalJSON(b []byte) error {
-	var raw map[string]json.RawMessage
-	if err := json.Unmarshal(b, &raw); err != nil {
-		return err
+func (w *transformWire) field(key string) any {
+	switch key {
+	case "type":
+		return &w.Type
+	case "expression":
+		return &w.Expression
 	}
+	return nil
+}
 
+func (t *Transform) UnmarshalJSON(b []byte) error {
 	var w transformWire
-	if err := json.Unmarshal(b, &w); err != nil {
+	extensions, unknown, err := unmarshalLossless(b, w.field)
+	if err != nil {
 		return err
 	}
 
@@ -295,7 +420,7 @@ func (t *Transform) UnmarshalJSON(b []byte) error {
 		Expression: w.Expression,
 	}
 
-	t.Extensions, t.Unknown = splitLossless(raw, knownTransformSet)
+	t.Extensions, t.Unknown = extensions, unknown
 	return nil
 }
 
@@ -335,25 +460,52 @@ func (t TransformOrRef) IsRef() bool {
 // Returns nil if the reference cannot be resolved.
 // For inline transforms, returns the Transform directly.
 func (t TransformOrRef) Resolve(transforms map[string]Transform) *Transform {
+	tr, _ := t.ResolveErr(transforms)
+	return tr
+}
+
+// ResolveErr is Resolve with an error saying why a reference does not resolve:
+// one wrapping ErrMalformedRef if it is not of the form "#/transforms/<name>", and
+// ErrUnknownTransform if transforms has no such name. Both also match
+// ErrTransformRefNotFound. For an inline transform it returns t.Transform.
+func (t TransformOrRef) ResolveErr(transforms map[string]Transform) (*Transform, error) {
 	if !t.IsRef() {
-		return t.Transform
+		return t.Transform, nil
 	}
-
-	// Parse the $ref - expected format: #/transforms/<name>
 	const prefix = "#/transforms/"
 	if !strings.HasPrefix(t.Ref, prefix) {
-		return nil
+		return nil, &transformRefError{kind: ErrMalformedRef, ref: t.Ref, reason: fmt.Sprintf("must start with %q", prefix)}
 	}
 	name := strings.TrimPrefix(t.Ref, prefix)
 	if name == "" {
-		return nil
+		return nil, &transformRefError{kind: ErrMalformedRef, ref: t.Ref, reason: "empty transform name"}
 	}
-	if tr, ok := transforms[name]; ok {
-		return &tr
+	tr, ok := transforms[name]
+	if !ok {
+		return nil, &transformRefError{kind: ErrUnknownTransform, ref: t.Ref, name: name}
 	}
-	return nil
+	return &tr, nil
 }
 
+// transformRefError is a ResolveErr error, matching both its kind and
+// ErrTransformRefNotFound. reason says why a malformed reference is malformed;
+// name is the transform an ErrUnknownTransform reference names.
+type transformRefError struct {
+	kind   error
+	ref    string
+	name   string
+	reason string
+}
+
+func (e *transformRefError) Error() string {
+	if e.kind == ErrUnknownTransform {
+		return fmt.Sprintf("%v %q", e.kind, e.name)
+	}
+	return fmt.Sprintf("%v %q: %s", e.kind, e.ref, e.reason)
+}
+
+func (e *transformRefError) Unwrap() []error { return []error{e.kind, ErrTransformRefNotFound} }
+
 func (t *TransformOrRef) UnmarshalJSON(b []byte) error {
 	// First, try to detect if this is a $ref
 	var raw map[string]json.RawMessage
@@ -383,12 +535,15 @@ func (t *TransformOrRef) UnmarshalJSON(b []byte) error {
 		return nil
 	}
 
-	// Otherwise, parse as Transform
-	var tr Transform
-	if err := json.Unmarshal(b, &tr); err != nil {
+	// Otherwise, parse as Transform from the entries already split above.
+	var w transformWire
+	extensions, unknown, err := unmarshalLosslessRaw(raw, w.field)
+	if err != nil {
 		return err
 	}
-	*t = TransformOrRef{Transform: &tr}
+	tr := &Transform{Type: w.Type, Expression: w.Expression}
+	tr.Extensions, tr.Unknown = extensions, unknown
+	*t = TransformOrRef{Transform: tr}
 	return nil
 }
 
@@ -441,14 +596,34 @@ type bindingEntryWire struct {
 	OutputTransform *TransformOrRef `json:"outputTransform,omitempty"`
 }
 
-func (be *BindingEntry) UnmarshalJSON(b []byte) error {
-	var raw map[string]json.RawMessage
-	if err := json.Unmarshal(b, &raw); err != nil {
-		return err
+func (w *bindingEntryWire) field(key string) any {
+	switch key {
+	case "operation":
+		return &w.Operation
+	case "source":
+		return &w.Source
+	case "ref":
+		return &w.Ref
+	case "priority":
+		return &w.Priority
+	case "description":
+		return &w.Description
+	case "deprecated":
+		return &w.Deprecated
+	case "security":
+		return &w.Security
+	case "inputTransform":
+		return &w.InputTransform
+	case "outputTransform":
+		return &w.OutputTransform
 	}
+	return nil
+}
 
+func (be *BindingEntry) UnmarshalJSON(b []byte) error {
 	var w bindingEntryWire
-	if err := json.Unmarshal(b, &w); err != nil {
+	extensions, unknown, err := unmarshalLossless(b, w.field)
+	if err != nil {
 		return err
 	}
 
@@ -464,7 +639,7 @@ func (be *BindingEntry) UnmarshalJSON(b []byte) error {
 		OutputTransform: w.OutputTransform,
 	}
 
-	be.Extensions, be.Unknown = splitLossless(raw, knownBindingEntrySet)
+	be.Extensions, be.Unknown = extensions, unknown
 	return nil
 }
 
@@ -507,6 +682,10 @@ type Interface struct {
 	Transforms map[string]Transform `json:"transforms,omitempty"`
 
 	LosslessFields
+
+	// operationOrder holds the operation keys in the order they were decoded,
+	// recorded by DecodeInterface with WithOperationOrder.
+	operationOrder []string
 }
 
 type interfaceWire struct {
@@ -528,14 +707,38 @@ type interfaceWire struct {
 	Transforms map[string]Transform `json:"transforms,omitempty"`
 }
 
-func (i *Interface) UnmarshalJSON(b []byte) error {
-	var raw map[string]json.RawMessage
-	if err := json.Unmarshal(b, &raw); err != nil {
-		return err
+func (w *interfaceWire) field(key string) any {
+	switch key {
+	case "openbindings":
+		return &w.OpenBindings
+	case "name":
+		return &w.Name
+	case "version":
+		return &w.Version
+	case "description":
+		return &w.Description
+	case "schemas":
+		return &w.Schemas
+	case "operations":
+		return &w.Operations
+	case "roles":
+		return &w.Roles
+	case "sources":
+		return &w.Sources
+	case "bindings":
+		return &w.Bindings
+	case "security":
+		return &w.Security
+	case "transforms":
+		return &w.Transforms
 	}
+	return nil
+}
 
+func (i *Interface) UnmarshalJSON(b []byte) error {
 	var w interfaceWire
-	if err := json.Unmarshal(b, &w); err != nil {
+	extensions, unknown, err := unmarshalLossless(b, w.field)
+	if err != nil {
 		return err
 	}
 
@@ -553,7 +756,7 @@ func (i *Interface) UnmarshalJSON(b []byte) error {
 		Transforms:   w.Transforms,
 	}
 
-	i.Extensions, i.Unknown = splitLossless(raw, knownInterfaceSet)
+	i.Extensions, i.Unknown = extensions, unknown
 	return nil
 }
 
@@ -573,3 +776,11 @@ func (i Interface) MarshalJSON() ([]byte, error) {
 	}
 	return marshalLossless(i.Unknown, i.Extensions, w)
 }
+
+// MarshalIndent is like MarshalJSON but indents the output for human review, as
+// json.MarshalIndent does. Every object in the document is written in a stable
+// order: typed fields in spec order, then extensions, then unknown fields, each
+// group sorted by key. Map-valued collections such as operations are sorted by key.
+func (i Interface) MarshalIndent(prefix, indent string) ([]byte, error) {
+	return json.MarshalIndent(i, prefix, indent)
+}
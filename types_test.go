@@ -2,6 +2,8 @@ package openbindings
 
 import (
 	"encoding/json"
+	"errors"
+	"strings"
 	"testing"
 )
 
@@ -191,6 +193,24 @@ func TestOperation_OmitsEmptyTags(t *testing.T) {
 	}
 }
 
+func TestOperation_SecurityRoundTrip(t *testing.T) {
+	in := []byte(`{"description":"Delete a user","security":[{"oauth":["users:write"]},{"apiKey":[]}]}`)
+
+	var op Operation
+	if err := json.Unmarshal(in, &op); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if len(op.Security) != 2 || op.Security[1]["apiKey"] == nil {
+		t.Fatalf("expected two security requirements, got %v", op.Security)
+	}
+	if _, inUnknown := op.Unknown["security"]; inUnknown {
+		t.Fatal("security should be a typed field, not Unknown")
+	}
+	if out := mustMarshalJSON(t, op); string(out) != string(in) {
+		t.Fatalf("round trip:\n got: %s\nwant: %s", out, in)
+	}
+}
+
 func TestSource_LosslessRoundTrip_PreservesExtensionsAndUnknown(t *testing.T) {
 	in := []byte(`{
   "format": "openapi@3.1",
@@ -692,3 +712,189 @@ func TestTransformOrRef_Resolve(t *testing.T) {
 		t.Fatal("expected malformed ref to return nil")
 	}
 }
+
+func TestTransformOrRef_ResolveErr(t *testing.T) {
+	transforms := map[string]Transform{"myTransform": {Type: "jsonata", Expression: "{ foo: bar }"}}
+
+	resolved, err := TransformOrRef{Ref: "#/transforms/myTransform"}.ResolveErr(transforms)
+	if err != nil || resolved == nil || resolved.Expression != "{ foo: bar }" {
+		t.Fatalf("expected ref to resolve, got %+v, %v", resolved, err)
+	}
+	inline := &Transform{Type: "jsonata", Expression: "{ inline: true }"}
+	if resolved, err := (TransformOrRef{Transform: inline}).ResolveErr(transforms); err != nil || resolved != inline {
+		t.Fatalf("expected inline transform, got %+v, %v", resolved, err)
+	}
+
+	for _, tc := range []struct {
+		ref  string
+		kind error
+		msg  string
+	}{
+		{"#/transforms/nonexistent", ErrUnknownTransform, `openbindings: unknown transform "nonexistent"`},
+		{"notavalidref", ErrMalformedRef, `openbindings: malformed transform reference "notavalidref": must start with "#/transforms/"`},
+		{"#/transforms/", ErrMalformedRef, `openbindings: malformed transform reference "#/transforms/": empty transform name`},
+	} {
+		resolved, err := TransformOrRef{Ref: tc.ref}.ResolveErr(transforms)
+		if resolved != nil || !errors.Is(err, tc.kind) || !errors.Is(err, ErrTransformRefNotFound) || err.Error() != tc.msg {
+			t.Fatalf("%s: expected %q, got %+v, %v", tc.ref, tc.msg, resolved, err)
+		}
+	}
+	if _, err := (TransformOrRef{Ref: "#/transforms/nonexistent"}).ResolveErr(transforms); errors.Is(err, ErrMalformedRef) {
+		t.Fatal("an unknown transform must not match ErrMalformedRef")
+	}
+}
+
+func TestInterface_NewSatisfies(t *testing.T) {
+	i := Interface{Roles: map[string]string{"io.example@1.0": "https://example.com/interface.json"}}
+
+	s, err := i.NewSatisfies("io.example@1.0", "op")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.Role != "io.example@1.0" || s.Operation != "op" {
+		t.Fatalf("unexpected satisfies: %+v", s)
+	}
+
+	for _, tc := range []struct{ role, op string }{
+		{"", "op"},
+		{"io.example@1.0", " "},
+		{"io.missing@1.0", "op"},
+	} {
+		if _, err := i.NewSatisfies(tc.role, tc.op); err == nil {
+			t.Fatalf("expected error for role=%q operation=%q", tc.role, tc.op)
+		}
+	}
+}
+
+func TestJSONSchema_PreservesNumberFormatting(t *testing.T) {
+	doc := `{"openbindings":"0.1.0","schemas":{"Amount":{"type":"number","minimum":1.0,"maximum":1e3,"enum":[12345678901234567890]}},"operations":{"pay":{"input":{"multipleOf":0.10}}}}`
+	var i Interface
+	if err := json.Unmarshal([]byte(doc), &i); err != nil {
+		t.Fatal(err)
+	}
+	if min, ok := i.Schemas["Amount"]["minimum"].(json.Number); !ok || min != "1.0" {
+		t.Fatalf("expected json.Number 1.0, got %#v", i.Schemas["Amount"]["minimum"])
+	}
+	b, err := json.Marshal(i)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`"minimum":1.0`, `"maximum":1e3`, `[12345678901234567890]`, `"multipleOf":0.10`} {
+		if !strings.Contains(string(b), want) {
+			t.Fatalf("expected %s in %s", want, b)
+		}
+	}
+
+	s := JSONSchema{"type": "object"}
+	if err := json.Unmarshal([]byte(`{"minimum":0}`), &s); err != nil {
+		t.Fatal(err)
+	}
+	if len(s) != 2 {
+		t.Fatalf("expected entries added to the existing map, got %v", s)
+	}
+	if err := json.Unmarshal([]byte(`null`), &s); err != nil || len(s) != 2 {
+		t.Fatalf("expected null to leave the schema unchanged, got %v, %v", s, err)
+	}
+}
+
+func BenchmarkInterface_UnmarshalJSON(b *testing.B) {
+	doc := largeInterfaceDocument(256 << 10)
+	b.SetBytes(int64(len(doc)))
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		var i Interface
+		if err := json.Unmarshal(doc, &i); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkInterface_UnmarshalJSONSmall(b *testing.B) {
+	doc := []byte(decodeTestDoc)
+	b.SetBytes(int64(len(doc)))
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		var i Interface
+		if err := json.Unmarshal(doc, &i); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestInterface_UnmarshalReportsFieldPath(t *testing.T) {
+	var i Interface
+	err := json.Unmarshal([]byte(`{"openbindings": "0.1.0", "operations": {"x": {"tags": "a", "aliases": 1}}}`), &i)
+	if err == nil || err.Error() != "operations: aliases: json: cannot unmarshal number into Go value of type []string" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestOperation_UnmarshalKeysAreCaseSensitive(t *testing.T) {
+	var op Operation
+	if err := json.Unmarshal([]byte(`{"description": "a", "Description": "b"}`), &op); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if op.Description != "a" {
+		t.Fatalf("expected exact-case key to win, got %q", op.Description)
+	}
+	if string(op.Unknown["Description"]) != `"b"` {
+		t.Fatalf("expected differently-cased key in Unknown, got %#v", op.Unknown)
+	}
+}
+
+func TestInterface_MarshalIndent_StableOrder(t *testing.T) {
+	in := []byte(`{"zeta":1,"x-b":true,"operations":{"b":{"x-op":1,"output":{"type":"string"},"description":"B"},"a":{}},"x-a":[1, 2],"name":"Demo","openbindings":"0.1.0","alpha":"u"}`)
+	var i Interface
+	if err := json.Unmarshal(in, &i); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	want := `{
+  "openbindings": "0.1.0",
+  "name": "Demo",
+  "operations": {
+    "a": {},
+    "b": {
+      "description": "B",
+      "output": {
+        "type": "string"
+      },
+      "x-op": 1
+    }
+  },
+  "x-a": [
+    1,
+    2
+  ],
+  "x-b": true,
+  "alpha": "u",
+  "zeta": 1
+}`
+	for n := 0; n < 20; n++ {
+		out, err := i.MarshalIndent("", "  ")
+		if err != nil {
+			t.Fatalf("marshal: %v", err)
+		}
+		if string(out) != want {
+			t.Fatalf("unexpected output:\n%s", out)
+		}
+	}
+}
+
+func TestMarshalLossless_ExtensionInUnknownWrittenOnce(t *testing.T) {
+	tr := Transform{
+		Type:       "jsonata",
+		Expression: "$",
+		LosslessFields: LosslessFields{
+			Extensions: map[string]json.RawMessage{"x-a": json.RawMessage(`1`)},
+			Unknown:    map[string]json.RawMessage{"x-a": json.RawMessage(`2`), "type": json.RawMessage(`"other"`)},
+		},
+	}
+	out, err := json.Marshal(tr)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if want := `{"type":"jsonata","expression":"$","x-a":1}`; string(out) != want {
+		t.Fatalf("got %s, want %s", out, want)
+	}
+}
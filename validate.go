@@ -2,9 +2,12 @@ package openbindings
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"regexp"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/openbindings/openbindings-go/formattoken"
@@ -13,6 +16,26 @@ import (
 type validateOptions struct {
 	rejectUnknownTypedFields bool
 	requireSupportedVersion  bool
+	lenientVersion           bool
+	deprecationConsistency   bool
+	maxOperations            int
+	maxBindings              int
+	maxSchemaDepth           int
+	requireAllBound          bool
+	validateExpressions      bool
+	jsonataParser            func(expr string) error
+	transformTypes           []string
+	allowLibrary             bool
+	satisfiesResolver        func(role string) (*Interface, error)
+	warningsAsErrors         bool
+	requireDescriptions      bool
+	reportUnused             bool
+	exampleValidator         func(schema map[string]any, value any) error
+	uniquePriorities         bool
+	bindingKeyConvention     bool
+	sourceLoader             func(location string) ([]byte, error)
+	sourceDecoder            func(data []byte) (map[string]any, error)
+	maxProblems              int
 }
 
 // ValidateOption configures Interface.Validate.
@@ -30,45 +53,265 @@ func WithRequireSupportedVersion() ValidateOption {
 	return func(o *validateOptions) { o.requireSupportedVersion = true }
 }
 
-var semverish = regexp.MustCompile(`^\d+\.\d+\.\d+$`)
+// WithLenientVersion is WithRequireSupportedVersion that tolerates versions this SDK
+// supports only partially (see CompatibilityLevel): they are reported as warnings
+// rather than errors, so a document from a slightly newer spec release still
+// validates.
+func WithLenientVersion() ValidateOption {
+	return func(o *validateOptions) {
+		o.requireSupportedVersion = true
+		o.lenientVersion = true
+	}
+}
+
+// WithCheckDeprecationConsistency makes a binding that targets a deprecated
+// operation without being deprecated itself a Validate error instead of a
+// Check warning, so deprecation propagates to the bindings consumers would
+// otherwise still pick.
+func WithCheckDeprecationConsistency() ValidateOption {
+	return func(o *validateOptions) { o.deprecationConsistency = true }
+}
+
+// WithMaxOperations reports a problem when the document declares more than n operations.
+// Values <= 0 disable the check.
+func WithMaxOperations(n int) ValidateOption {
+	return func(o *validateOptions) { o.maxOperations = n }
+}
+
+// WithMaxBindings reports a problem when the document declares more than n bindings.
+// Values <= 0 disable the check.
+func WithMaxBindings(n int) ValidateOption {
+	return func(o *validateOptions) { o.maxBindings = n }
+}
+
+// WithMaxProblems keeps at most n problems, the first n in sorted order, and replaces
+// the rest with a final "... N more problems omitted" problem, so the output for a
+// badly broken document stays readable. Check caps errors and warnings separately.
+// Values <= 0 disable the cap.
+func WithMaxProblems(n int) ValidateOption {
+	return func(o *validateOptions) { o.maxProblems = n }
+}
+
+// WithMaxSchemaDepth reports a problem when any embedded schema (in schemas or in an
+// operation's input/output) nests JSON objects/arrays deeper than n levels. A flat
+// schema object has depth 1. Values <= 0 disable the check.
+func WithMaxSchemaDepth(n int) ValidateOption {
+	return func(o *validateOptions) { o.maxSchemaDepth = n }
+}
+
+// WithRequireAllOperationsBound reports every operation that no binding references.
+// Partial documents are valid during development, so this is an opt-in release gate.
+func WithRequireAllOperationsBound() ValidateOption {
+	return func(o *validateOptions) { o.requireAllBound = true }
+}
+
+// WithRequireDescriptions requires a non-empty description on the interface, every
+// operation, and every source, for documentation-quality gates. Without it a missing
+// operation description is only a Check warning.
+func WithRequireDescriptions() ValidateOption {
+	return func(o *validateOptions) { o.requireDescriptions = true }
+}
+
+// WithReportUnused reports, as Check warnings, every source and named transform
+// that no binding references. Promote them with WithWarningAsError to gate on them.
+func WithReportUnused() ValidateOption {
+	return func(o *validateOptions) { o.reportUnused = true }
+}
+
+// WithUniqueBindingPriorities requires the bindings of each operation that set a
+// priority to set distinct ones, so selecting a binding by priority is never a tie.
+// Bindings without a priority are exempt.
+func WithUniqueBindingPriorities() ValidateOption {
+	return func(o *validateOptions) { o.uniquePriorities = true }
+}
+
+// WithBindingKeyConvention requires every binding key to be its operation and source
+// keys joined by a dot, as in "getUser.api", for tools that look bindings up by that
+// convention. The spec does not require it, so it is opt-in.
+func WithBindingKeyConvention() ValidateOption {
+	return func(o *validateOptions) { o.bindingKeyConvention = true }
+}
+
+// WithSourceLoader checks each source that has a location against the document
+// behind it: load is called once per distinct location, and when the source format
+// is one whose documents declare their version at the top level (openapi, asyncapi,
+// openbindings), the declared version must match the format token's version (so
+// "openapi@3.1" accepts "3.1.0" and "3.1.1" but not "3.0.3"). The document is
+// parsed as JSON unless WithSourceDecoder supplies another decoder. Without a
+// loader, locations are not loaded.
+func WithSourceLoader(load func(location string) ([]byte, error)) ValidateOption {
+	return func(o *validateOptions) { o.sourceLoader = load }
+}
+
+// WithSourceDecoder replaces the JSON parsing of documents loaded by
+// WithSourceLoader, so sources in other encodings can be checked. For YAML, convert
+// with obyaml.ToJSON and unmarshal the result.
+func WithSourceDecoder(decode func(data []byte) (map[string]any, error)) ValidateOption {
+	return func(o *validateOptions) { o.sourceDecoder = decode }
+}
+
+// WithExampleValidator checks each operation example against the operation's
+// schemas: validate is called with the input schema and the example input, and with
+// the output schema and the example output, whenever both are present. A non-nil
+// error is reported as a problem. The SDK does not bundle a JSON Schema validator,
+// so examples are not checked without this option.
+func WithExampleValidator(validate func(schema map[string]any, value any) error) ValidateOption {
+	return func(o *validateOptions) { o.exampleValidator = validate }
+}
+
+// WithValidateTransformExpressions parses every transform expression (named and inline)
+// with the parser supplied via WithJSONataParser and reports syntax errors. The SDK does
+// not bundle a JSONata engine, so without a parser this option has no effect.
+func WithValidateTransformExpressions() ValidateOption {
+	return func(o *validateOptions) { o.validateExpressions = true }
+}
+
+// WithJSONataParser sets the parser used by WithValidateTransformExpressions. The function
+// should return a non-nil error describing the problem (ideally with its position) when
+// expr is not valid JSONata.
+func WithJSONataParser(parse func(expr string) error) ValidateOption {
+	return func(o *validateOptions) { o.jsonataParser = parse }
+}
+
+// WithAllowedTransformTypes replaces the transform types Validate accepts, which by
+// default is only "jsonata", the one type the v0.1 spec defines. It lets tooling
+// experiment with other expression languages (e.g. "jq"); WithJSONataParser still
+// checks only jsonata expressions. With no types the default is kept.
+func WithAllowedTransformTypes(types ...string) ValidateOption {
+	return func(o *validateOptions) {
+		if len(types) > 0 {
+			o.transformTypes = append([]string(nil), types...)
+		}
+	}
+}
+
+// WithAllowLibraryDocument accepts "library" documents that declare shared schemas or
+// roles but no operations of their own. With this option, a document whose operations
+// field is absent or empty is valid as long as it declares at least one schema or role;
+// a document with neither is still reported as missing operations.
+func WithAllowLibraryDocument() ValidateOption {
+	return func(o *validateOptions) { o.allowLibrary = true }
+}
+
+// WithSatisfiesResolver checks each satisfies entry's operation against the interface
+// its role refers to. resolve is called at most once per referenced role key and
+// returns the role's interface; the operation must match one of its operation keys
+// or aliases. Without a resolver, satisfies operations are not cross-checked.
+func WithSatisfiesResolver(resolve func(role string) (*Interface, error)) ValidateOption {
+	return func(o *validateOptions) { o.satisfiesResolver = resolve }
+}
+
+// WithWarningAsError reports advisory problems (see Interface.Check) as errors,
+// so Validate fails on them and Check returns them in Report.Errors.
+func WithWarningAsError() ValidateOption {
+	return func(o *validateOptions) { o.warningsAsErrors = true }
+}
+
+// isLibrary reports whether i is an operation-less document that exists to share
+// schemas or roles. See WithAllowLibraryDocument.
+func (i Interface) isLibrary() bool {
+	return len(i.Operations) == 0 && (len(i.Schemas) > 0 || len(i.Roles) > 0)
+}
 
 // Validate performs shape-level checks useful for tooling correctness.
 // It is intentionally not full JSON Schema validation.
+// Advisory problems are not reported unless WithWarningAsError is given; use
+// Check to receive them separately.
 func (i Interface) Validate(opts ...ValidateOption) error {
+	errs, _ := i.validate(opts)
+	return errs.err()
+}
+
+// Report is the result of Interface.Check.
+type Report struct {
+	// Errors are the problems Validate reports.
+	Errors []Problem
+	// Warnings are advisory problems: the document is valid but likely not what
+	// the author intended (e.g. a binding to a deprecated operation).
+	Warnings []Problem
+}
+
+// Check runs the same checks as Validate and additionally reports advisory
+// problems as warnings. The returned error is the *ValidationError Validate
+// would return, or nil if the report has no errors.
+func (i Interface) Check(opts ...ValidateOption) (*Report, error) {
+	errs, warns := i.validate(opts)
+	return &Report{Errors: errs.structured, Warnings: warns.structured}, errs.err()
+}
+
+// validate collects errors and warnings; with WithWarningAsError the warnings are
+// appended to the errors and none are returned separately.
+func (i Interface) validate(opts []ValidateOption) (errs, warns problemList) {
 	o := validateOptions{
 		rejectUnknownTypedFields: false,
 		requireSupportedVersion:  false,
+		transformTypes:           []string{"jsonata"},
 	}
 	for _, opt := range opts {
 		if opt != nil {
 			opt(&o)
 		}
 	}
-
-	var errs []string
+	defer func() {
+		if o.warningsAsErrors {
+			errs.problems = append(errs.problems, warns.problems...)
+			errs.structured = append(errs.structured, warns.structured...)
+			warns = problemList{}
+		}
+		errs.sortUnique()
+		warns.sortUnique()
+		errs.truncate(o.maxProblems)
+		warns.truncate(o.maxProblems)
+	}()
+
+	var parseExpr func(string) error
+	if o.validateExpressions {
+		parseExpr = o.jsonataParser
+	}
 
 	if strings.TrimSpace(i.OpenBindings) == "" {
-		errs = append(errs, "openbindings: required")
-	} else if !semverish.MatchString(i.OpenBindings) {
-		errs = append(errs, "openbindings: must be MAJOR.MINOR.PATCH (e.g. 0.1.0)")
+		errs.add(at("openbindings"), ProblemRequired, "required")
+	} else if !isSemver(i.OpenBindings) {
+		errs.add(at("openbindings"), ProblemInvalidValue, "must be MAJOR.MINOR.PATCH (e.g. 0.1.0)")
 	} else if o.requireSupportedVersion {
-		ok, err := IsSupportedVersion(i.OpenBindings)
-		if err != nil {
-			errs = append(errs, fmt.Sprintf("openbindings: invalid version: %v", err))
-		} else if !ok {
-			errs = append(errs, fmt.Sprintf("openbindings: unsupported version %q (supported %s-%s)", i.OpenBindings, MinSupportedVersion, MaxTestedVersion))
+		level, err := CompatibilityLevel(i.OpenBindings)
+		switch {
+		case err != nil:
+			errs.add(at("openbindings"), ProblemInvalidValue, "invalid version: %v", err)
+		case level == CompatibilityPartial && o.lenientVersion:
+			warns.add(at("openbindings"), ProblemPartialVersion, "version %q is newer than this SDK supports (supported %s-%s)", i.OpenBindings, MinSupportedVersion, MaxTestedVersion)
+		case level != CompatibilityFull:
+			errs.add(at("openbindings"), ProblemUnsupportedVersion, "unsupported version %q (supported %s-%s)", i.OpenBindings, MinSupportedVersion, MaxTestedVersion)
 		}
 	}
 
+	if o.requireDescriptions && strings.TrimSpace(i.Description) == "" {
+		errs.add(at("description"), ProblemRequired, "required")
+	}
+
 	// Validate roles: values must be non-empty.
 	for k, v := range i.Roles {
 		if strings.TrimSpace(v) == "" {
-			errs = append(errs, fmt.Sprintf("roles[%q]: value must be non-empty", k))
+			errs.add(at("roles", k), ProblemRequired, "value must be non-empty")
 		}
 	}
 
-	if i.Operations == nil {
-		errs = append(errs, "operations: required")
+	if i.Operations == nil && !(o.allowLibrary && i.isLibrary()) {
+		errs.add(at("operations"), ProblemRequired, "required")
+	} else if o.allowLibrary && len(i.Operations) == 0 && !i.isLibrary() {
+		// An empty operations map counts as missing once library documents are allowed.
+		errs.add(at("operations"), ProblemRequired, "required")
+	}
+
+	// Structural size limits (opt-in).
+	if o.maxOperations > 0 && len(i.Operations) > o.maxOperations {
+		errs.add(at("operations"), ProblemLimitExceeded, "%d entries exceeds limit of %d", len(i.Operations), o.maxOperations)
+	}
+	if o.maxBindings > 0 && len(i.Bindings) > o.maxBindings {
+		errs.add(at("bindings"), ProblemLimitExceeded, "%d entries exceeds limit of %d", len(i.Bindings), o.maxBindings)
+	}
+	if o.maxSchemaDepth > 0 {
+		appendSchemaDepthProblems(&errs, i, o.maxSchemaDepth)
 	}
 
 	opKeys := make([]string, 0, len(i.Operations))
@@ -84,21 +327,37 @@ func (i Interface) Validate(opts ...ValidateOption) error {
 		opKeySet[k] = struct{}{}
 	}
 
+	// Role interfaces fetched through the satisfies resolver, by role key.
+	type resolvedRole struct {
+		iface *Interface
+		err   error
+	}
+	roleIfaces := map[string]resolvedRole{}
+
 	for _, k := range opKeys {
 		op := i.Operations[k]
+		opAt := at("operations", k)
+
+		if strings.TrimSpace(op.Description) == "" {
+			if o.requireDescriptions {
+				errs.add(opAt.field("description"), ProblemRequired, "required")
+			} else {
+				warns.add(opAt.field("description"), ProblemMissingDescription, "missing")
+			}
+		}
 
 		// Alias checks.
 		for _, a := range op.Aliases {
 			if strings.TrimSpace(a) == "" {
-				errs = append(errs, fmt.Sprintf("operations[%q].aliases: must not contain empty strings", k))
+				errs.add(opAt.field("aliases"), ProblemInvalidAlias, "must not contain empty strings")
 				continue
 			}
 			if _, isOpKey := opKeySet[a]; isOpKey && a != k {
-				errs = append(errs, fmt.Sprintf("operations[%q].aliases: %q conflicts with operation key %q", k, a, a))
+				errs.add(opAt.field("aliases"), ProblemAliasConflict, "%q conflicts with operation key %q", a, a)
 				continue
 			}
 			if owner, ok := aliasOwner[a]; ok && owner != k {
-				errs = append(errs, fmt.Sprintf("operations[%q].aliases: %q is also an alias of %q", k, a, owner))
+				errs.add(opAt.field("aliases"), ProblemAliasConflict, "%q is also an alias of %q", a, owner)
 				continue
 			}
 			aliasOwner[a] = k
@@ -106,28 +365,74 @@ func (i Interface) Validate(opts ...ValidateOption) error {
 
 		// Satisfies sanity.
 		for idx, s := range op.Satisfies {
+			sAt := opAt.field("satisfies").index(idx)
 			if strings.TrimSpace(s.Role) == "" {
-				errs = append(errs, fmt.Sprintf("operations[%q].satisfies[%d].role: required", k, idx))
+				errs.add(sAt.field("role"), ProblemRequired, "required")
 			} else if _, ok := i.Roles[s.Role]; !ok {
-				errs = append(errs, fmt.Sprintf("operations[%q].satisfies[%d].role: references unknown role %q", k, idx, s.Role))
+				errs.add(sAt.field("role"), ProblemUnknownRole, "references unknown role %q", s.Role)
 			}
 			if strings.TrimSpace(s.Operation) == "" {
-				errs = append(errs, fmt.Sprintf("operations[%q].satisfies[%d].operation: required", k, idx))
+				errs.add(sAt.field("operation"), ProblemRequired, "required")
+				continue
+			}
+			if _, ok := i.Roles[s.Role]; !ok || o.satisfiesResolver == nil {
+				continue
+			}
+			r, seen := roleIfaces[s.Role]
+			if !seen {
+				r.iface, r.err = o.satisfiesResolver(s.Role)
+				if r.err == nil && r.iface == nil {
+					r.err = fmt.Errorf("resolver returned no interface")
+				}
+				roleIfaces[s.Role] = r
+				if r.err != nil {
+					errs.add(at("roles", s.Role), ProblemUnresolvedRole, "cannot resolve interface: %v", r.err)
+				}
+			}
+			if r.err != nil {
+				continue
+			}
+			if _, _, ok := r.iface.OperationByAlias(s.Operation); !ok {
+				errs.add(sAt.field("operation"), ProblemUnknownRoleOperation, "%q not found in role %q", s.Operation, s.Role)
+			}
+		}
+
+		for idx, req := range op.Security {
+			if len(req) == 0 {
+				errs.add(opAt.field("security").index(idx), ProblemInvalidValue, "must not be empty")
+			}
+		}
+
+		if o.exampleValidator != nil {
+			for _, ek := range sortedKeys(op.Examples) {
+				ex := op.Examples[ek]
+				exAt := opAt.field("examples").key(ek)
+				if op.Input != nil && ex.Input != nil {
+					if err := o.exampleValidator(op.Input, ex.Input); err != nil {
+						errs.add(exAt.field("input"), ProblemExampleMismatch, "does not match operation input: %v", err)
+					}
+				}
+				if op.Output != nil && ex.Output != nil {
+					if err := o.exampleValidator(op.Output, ex.Output); err != nil {
+						errs.add(exAt.field("output"), ProblemExampleMismatch, "does not match operation output: %v", err)
+					}
+				}
 			}
 		}
 
 		if o.rejectUnknownTypedFields {
-			appendUnknownFieldProblems(&errs, fmt.Sprintf("operations[%q]", k), op.Unknown)
+			appendUnknownFieldProblems(&errs, opAt, op.Unknown)
 			for idx, s := range op.Satisfies {
-				appendUnknownFieldProblems(&errs, fmt.Sprintf("operations[%q].satisfies[%d]", k, idx), s.Unknown)
+				appendUnknownFieldProblems(&errs, opAt.field("satisfies").index(idx), s.Unknown)
 			}
 			for ek, ex := range op.Examples {
-				appendUnknownFieldProblems(&errs, fmt.Sprintf("operations[%q].examples[%q]", k, ek), ex.Unknown)
+				appendUnknownFieldProblems(&errs, opAt.field("examples").key(ek), ex.Unknown)
 			}
 		}
 	}
 
 	// Validate sources.
+	loaded := sourceLoads{load: o.sourceLoader, decode: o.sourceDecoder}
 	srcKeys := make([]string, 0, len(i.Sources))
 	for k := range i.Sources {
 		srcKeys = append(srcKeys, k)
@@ -135,22 +440,31 @@ func (i Interface) Validate(opts ...ValidateOption) error {
 	sort.Strings(srcKeys)
 	for _, k := range srcKeys {
 		src := i.Sources[k]
+		srcAt := at("sources", k)
+		if o.requireDescriptions && strings.TrimSpace(src.Description) == "" {
+			errs.add(srcAt.field("description"), ProblemRequired, "required")
+		}
 		fmtVal := strings.TrimSpace(src.Format)
 		if fmtVal == "" {
-			errs = append(errs, fmt.Sprintf("sources[%q].format: required", k))
+			errs.add(srcAt.field("format"), ProblemRequired, "required")
 		} else if !formattoken.IsFormatToken(fmtVal) && !formattoken.IsValidName(fmtVal) {
-			errs = append(errs, fmt.Sprintf("sources[%q].format: invalid format %q", k, src.Format))
+			errs.add(srcAt.field("format"), ProblemInvalidFormat, "invalid format %q", src.Format)
+		} else if _, ok := formattoken.LookupFormat(fmtVal); !ok {
+			warns.add(srcAt.field("format"), ProblemUnknownFormat, "unregistered format %q", src.Format)
 		}
 		hasLocation := strings.TrimSpace(src.Location) != ""
-		hasContent := src.Content != nil
+		hasContent := src.Content != nil || len(src.ContentRaw) > 0
 		if hasLocation && hasContent {
-			errs = append(errs, fmt.Sprintf("sources[%q]: cannot have both location and content", k))
+			errs.add(srcAt, ProblemInvalidSource, "cannot have both location and content")
 		}
 		if !hasLocation && !hasContent {
-			errs = append(errs, fmt.Sprintf("sources[%q]: must have location or content", k))
+			errs.add(srcAt, ProblemInvalidSource, "must have location or content")
+		}
+		if hasLocation && o.sourceLoader != nil {
+			loaded.check(&errs, srcAt, src)
 		}
 		if o.rejectUnknownTypedFields {
-			appendUnknownFieldProblems(&errs, fmt.Sprintf("sources[%q]", k), src.Unknown)
+			appendUnknownFieldProblems(&errs, srcAt, src.Unknown)
 		}
 	}
 
@@ -162,9 +476,9 @@ func (i Interface) Validate(opts ...ValidateOption) error {
 	sort.Strings(trKeys)
 	for _, k := range trKeys {
 		tr := i.Transforms[k]
-		validateInlineTransform(&errs, fmt.Sprintf("transforms[%q]", k), &tr)
+		validateInlineTransform(&errs, at("transforms", k), &tr, o.transformTypes, parseExpr)
 		if o.rejectUnknownTypedFields {
-			appendUnknownFieldProblems(&errs, fmt.Sprintf("transforms[%q]", k), tr.Unknown)
+			appendUnknownFieldProblems(&errs, at("transforms", k), tr.Unknown)
 		}
 	}
 
@@ -176,66 +490,174 @@ func (i Interface) Validate(opts ...ValidateOption) error {
 	sort.Strings(bndKeys)
 	for _, k := range bndKeys {
 		b := i.Bindings[k]
+		bAt := at("bindings", k)
 		if strings.TrimSpace(b.Operation) == "" {
-			errs = append(errs, fmt.Sprintf("bindings[%q].operation: required", k))
-		} else if _, ok := i.Operations[b.Operation]; !ok {
-			errs = append(errs, fmt.Sprintf("bindings[%q].operation: references unknown operation %q", k, b.Operation))
+			errs.add(bAt.field("operation"), ProblemRequired, "required")
+		} else if op, ok := i.Operations[b.Operation]; !ok {
+			errs.add(bAt.field("operation"), ProblemUnknownOperation, "references unknown operation %q", b.Operation)
+		} else if op.Deprecated && !b.Deprecated {
+			// Advisory unless WithCheckDeprecationConsistency asks to enforce it.
+			problems := &warns
+			if o.deprecationConsistency {
+				problems = &errs
+			}
+			problems.add(bAt.field("operation"), ProblemDeprecatedOperation, "targets deprecated operation %q; deprecate or remove the binding", b.Operation)
 		}
 		if strings.TrimSpace(b.Source) == "" {
-			errs = append(errs, fmt.Sprintf("bindings[%q].source: required", k))
+			errs.add(bAt.field("source"), ProblemRequired, "required")
 		} else if _, ok := i.Sources[b.Source]; !ok {
-			errs = append(errs, fmt.Sprintf("bindings[%q].source: references unknown source %q", k, b.Source))
+			errs.add(bAt.field("source"), ProblemUnknownSource, "references unknown source %q", b.Source)
 		}
 
 		// Validate security reference.
 		if strings.TrimSpace(b.Security) != "" {
 			if _, ok := i.Security[b.Security]; !ok {
-				errs = append(errs, fmt.Sprintf("bindings[%q].security: references unknown security %q", k, b.Security))
+				errs.add(bAt.field("security"), ProblemUnknownSecurity, "references unknown security %q", b.Security)
 			}
 		}
 
+		// A transform holding both forms marshals as the $ref alone, dropping the inline one.
+		if b.InputTransform != nil && b.InputTransform.IsRef() && b.InputTransform.Transform != nil {
+			errs.add(bAt.field("inputTransform"), ProblemInvalidTransformRef, "has both $ref and inline transform")
+		}
+		if b.OutputTransform != nil && b.OutputTransform.IsRef() && b.OutputTransform.Transform != nil {
+			errs.add(bAt.field("outputTransform"), ProblemInvalidTransformRef, "has both $ref and inline transform")
+		}
+
 		// Validate transform references.
 		if b.InputTransform != nil && b.InputTransform.IsRef() {
-			if err := validateTransformRef(b.InputTransform.Ref, i.Transforms); err != nil {
-				errs = append(errs, fmt.Sprintf("bindings[%q].inputTransform.$ref: %v", k, err))
+			if err := validateTransformRef(*b.InputTransform, i.Transforms); err != nil {
+				errs.add(bAt.field("inputTransform").field("$ref"), ProblemInvalidTransformRef, "%v", err)
 			}
 		}
 		if b.OutputTransform != nil && b.OutputTransform.IsRef() {
-			if err := validateTransformRef(b.OutputTransform.Ref, i.Transforms); err != nil {
-				errs = append(errs, fmt.Sprintf("bindings[%q].outputTransform.$ref: %v", k, err))
+			if err := validateTransformRef(*b.OutputTransform, i.Transforms); err != nil {
+				errs.add(bAt.field("outputTransform").field("$ref"), ProblemInvalidTransformRef, "%v", err)
 			}
 		}
 
 		// Validate inline transforms.
 		if b.InputTransform != nil && !b.InputTransform.IsRef() && b.InputTransform.Transform != nil {
-			validateInlineTransform(&errs, fmt.Sprintf("bindings[%q].inputTransform", k), b.InputTransform.Transform)
+			validateInlineTransform(&errs, bAt.field("inputTransform"), b.InputTransform.Transform, o.transformTypes, parseExpr)
 		}
 		if b.OutputTransform != nil && !b.OutputTransform.IsRef() && b.OutputTransform.Transform != nil {
-			validateInlineTransform(&errs, fmt.Sprintf("bindings[%q].outputTransform", k), b.OutputTransform.Transform)
+			validateInlineTransform(&errs, bAt.field("outputTransform"), b.OutputTransform.Transform, o.transformTypes, parseExpr)
 		}
 
 		if o.rejectUnknownTypedFields {
-			appendUnknownFieldProblems(&errs, fmt.Sprintf("bindings[%q]", k), b.Unknown)
+			appendUnknownFieldProblems(&errs, bAt, b.Unknown)
 			if b.InputTransform != nil && !b.InputTransform.IsRef() && b.InputTransform.Transform != nil {
-				appendUnknownFieldProblems(&errs, fmt.Sprintf("bindings[%q].inputTransform", k), b.InputTransform.Transform.Unknown)
+				appendUnknownFieldProblems(&errs, bAt.field("inputTransform"), b.InputTransform.Transform.Unknown)
 			}
 			if b.OutputTransform != nil && !b.OutputTransform.IsRef() && b.OutputTransform.Transform != nil {
-				appendUnknownFieldProblems(&errs, fmt.Sprintf("bindings[%q].outputTransform", k), b.OutputTransform.Transform.Unknown)
+				appendUnknownFieldProblems(&errs, bAt.field("outputTransform"), b.OutputTransform.Transform.Unknown)
 			}
 		}
 	}
 
-	if o.rejectUnknownTypedFields {
-		appendUnknownFieldProblems(&errs, "", i.Unknown)
+	// Bindings that share an operation, source, and priority are ambiguous for
+	// consumers that select by (operation, source); differing priorities break the tie.
+	type bindingTarget struct {
+		operation, source, priority string
+	}
+	firstForTarget := map[bindingTarget]string{}
+	for _, k := range bndKeys {
+		b := i.Bindings[k]
+		if strings.TrimSpace(b.Operation) == "" || strings.TrimSpace(b.Source) == "" {
+			continue
+		}
+		t := bindingTarget{operation: b.Operation, source: b.Source}
+		if b.Priority != nil {
+			t.priority = fmt.Sprint(*b.Priority)
+		}
+		if first, ok := firstForTarget[t]; ok {
+			// Displayed at the collection level; the pointer names the later duplicate.
+			dup := location{display: "bindings", pointer: at("bindings", k).pointer}
+			errs.add(dup, ProblemAmbiguousBinding, "ambiguous duplicate for operation %q source %q (%q and %q)", b.Operation, b.Source, first, k)
+			continue
+		}
+		firstForTarget[t] = k
 	}
 
-	if len(errs) == 0 {
-		return nil
+	if o.uniquePriorities {
+		type operationPriority struct {
+			operation string
+			priority  float64
+		}
+		firstForPriority := map[operationPriority]string{}
+		for _, k := range bndKeys {
+			b := i.Bindings[k]
+			if b.Priority == nil || strings.TrimSpace(b.Operation) == "" {
+				continue
+			}
+			p := operationPriority{operation: b.Operation, priority: *b.Priority}
+			if first, ok := firstForPriority[p]; ok {
+				dup := location{display: "bindings", pointer: at("bindings", k).pointer}
+				errs.add(dup, ProblemAmbiguousBinding, "operation %q has duplicate priority %v across %q and %q", b.Operation, *b.Priority, first, k)
+				continue
+			}
+			firstForPriority[p] = k
+		}
 	}
-	return &ValidationError{Problems: errs}
+
+	if o.bindingKeyConvention {
+		for _, k := range bndKeys {
+			b := i.Bindings[k]
+			if strings.TrimSpace(b.Operation) == "" || strings.TrimSpace(b.Source) == "" {
+				continue
+			}
+			if want := b.Operation + "." + b.Source; k != want {
+				errs.add(at("bindings", k), ProblemBindingKeyConvention, "key should be %q", want)
+			}
+		}
+	}
+
+	// Validate "#/schemas/..." references inside embedded schemas.
+	appendSchemaRefProblems(&errs, i)
+
+	if o.requireAllBound {
+		bound := map[string]struct{}{}
+		for _, b := range i.Bindings {
+			bound[b.Operation] = struct{}{}
+		}
+		for _, k := range opKeys {
+			if _, ok := bound[k]; !ok {
+				errs.add(at("operations", k), ProblemUnboundOperation, "no binding references this operation")
+			}
+		}
+	}
+
+	if o.reportUnused {
+		usedSources := map[string]struct{}{}
+		usedTransforms := map[string]struct{}{}
+		for _, b := range i.Bindings {
+			usedSources[b.Source] = struct{}{}
+			for _, t := range []*TransformOrRef{b.InputTransform, b.OutputTransform} {
+				if t != nil && t.IsRef() {
+					usedTransforms[strings.TrimPrefix(t.Ref, "#/transforms/")] = struct{}{}
+				}
+			}
+		}
+		for _, k := range sortedKeys(i.Sources) {
+			if _, ok := usedSources[k]; !ok {
+				warns.add(at("sources", k), ProblemUnused, "defined but never referenced")
+			}
+		}
+		for _, k := range sortedKeys(i.Transforms) {
+			if _, ok := usedTransforms[k]; !ok {
+				warns.add(at("transforms", k), ProblemUnused, "defined but never referenced")
+			}
+		}
+	}
+
+	if o.rejectUnknownTypedFields {
+		appendUnknownFieldProblems(&errs, location{}, i.Unknown)
+	}
+
+	return errs, warns
 }
 
-func appendUnknownFieldProblems(errs *[]string, prefix string, unknown map[string]json.RawMessage) {
+func appendUnknownFieldProblems(errs *problemList, l location, unknown map[string]json.RawMessage) {
 	if len(unknown) == 0 {
 		return
 	}
@@ -244,16 +666,112 @@ func appendUnknownFieldProblems(errs *[]string, prefix string, unknown map[strin
 		keys = append(keys, k)
 	}
 	sort.Strings(keys)
-	if prefix == "" {
-		*errs = append(*errs, fmt.Sprintf("unknown fields: %s", strings.Join(keys, ", ")))
-		return
+	errs.add(l, ProblemUnknownField, "unknown fields: %s", strings.Join(keys, ", "))
+}
+
+// appendSchemaDepthProblems reports every embedded schema whose nesting exceeds max.
+func appendSchemaDepthProblems(errs *problemList, i Interface, max int) {
+	schemaKeys := make([]string, 0, len(i.Schemas))
+	for k := range i.Schemas {
+		schemaKeys = append(schemaKeys, k)
+	}
+	sort.Strings(schemaKeys)
+	for _, k := range schemaKeys {
+		if d := jsonDepth(map[string]any(i.Schemas[k]), max); d > max {
+			errs.add(at("schemas", k), ProblemLimitExceeded, "nesting depth exceeds limit of %d", max)
+		}
+	}
+
+	opKeys := make([]string, 0, len(i.Operations))
+	for k := range i.Operations {
+		opKeys = append(opKeys, k)
+	}
+	sort.Strings(opKeys)
+	for _, k := range opKeys {
+		op := i.Operations[k]
+		if op.Input != nil {
+			if d := jsonDepth(map[string]any(op.Input), max); d > max {
+				errs.add(at("operations", k).field("input"), ProblemLimitExceeded, "nesting depth exceeds limit of %d", max)
+			}
+		}
+		if op.Output != nil {
+			if d := jsonDepth(map[string]any(op.Output), max); d > max {
+				errs.add(at("operations", k).field("output"), ProblemLimitExceeded, "nesting depth exceeds limit of %d", max)
+			}
+		}
+	}
+}
+
+// jsonDepth returns the container nesting depth of v (scalars are 0). The walk stops
+// descending once the depth exceeds limit so hostile documents cannot force a full walk.
+func jsonDepth(v any, limit int) int {
+	var children []any
+	switch x := v.(type) {
+	case map[string]any:
+		for _, child := range x {
+			children = append(children, child)
+		}
+	case []any:
+		children = x
+	default:
+		return 0
+	}
+	deepest := 1
+	for _, child := range children {
+		if limit <= 0 {
+			break
+		}
+		if d := 1 + jsonDepth(child, limit-1); d > deepest {
+			deepest = d
+		}
+		if deepest > limit {
+			break
+		}
 	}
-	*errs = append(*errs, fmt.Sprintf("%s: unknown fields: %s", prefix, strings.Join(keys, ", ")))
+	return deepest
 }
 
 // ValidationError is a deterministic, multi-problem validation error.
 type ValidationError struct {
 	Problems []string
+	// Structured holds the same problems as Problems, index for index, with a
+	// JSON Pointer location and a stable code for machine consumption.
+	Structured []Problem
+}
+
+// ByPointer groups Problems for display by the entry they concern: the JSON
+// Pointer of their top-level field and, within a keyed field such as operations
+// or bindings, the entry key (e.g. "/operations/getUser"). Problems about the
+// document as a whole are grouped under "".
+func (e *ValidationError) ByPointer() map[string][]string {
+	if e == nil {
+		return nil
+	}
+	groups := map[string][]string{}
+	for idx, p := range e.Problems {
+		var prefix string
+		if idx < len(e.Structured) {
+			prefix = pointerPrefix(e.Structured[idx].Pointer, 2)
+		}
+		groups[prefix] = append(groups[prefix], p)
+	}
+	return groups
+}
+
+// pointerPrefix returns the leading n tokens of the JSON Pointer ptr.
+func pointerPrefix(ptr string, n int) string {
+	rest := ptr
+	for i := 0; i < n; i++ {
+		if !strings.HasPrefix(rest, "/") {
+			break
+		}
+		next := strings.IndexByte(rest[1:], '/')
+		if next < 0 {
+			return ptr
+		}
+		rest = rest[1+next:]
+	}
+	return ptr[:len(ptr)-len(rest)]
 }
 
 func (e *ValidationError) Error() string {
@@ -263,30 +781,167 @@ func (e *ValidationError) Error() string {
 	return "invalid interface: " + strings.Join(e.Problems, "; ")
 }
 
-// validateTransformRef validates that a $ref points to a valid transform.
-func validateTransformRef(ref string, transforms map[string]Transform) error {
-	const prefix = "#/transforms/"
-	if !strings.HasPrefix(ref, prefix) {
-		return fmt.Errorf("must start with %q", prefix)
+// appendSchemaRefProblems reports "#/schemas/..." references that do not resolve
+// against i.Schemas, and schemas whose $ref alias chain never reaches a schema.
+// Like SchemaRefs it skips "$ref" members inside value keywords (const, enum,
+// default, examples) and x- extensions, which are data rather than references.
+func appendSchemaRefProblems(errs *problemList, i Interface) {
+	schemasDoc := make(map[string]any, len(i.Schemas))
+	for k, v := range i.Schemas {
+		schemasDoc[k] = map[string]any(v)
+	}
+
+	for _, r := range i.SchemaRefs() {
+		toks, ok := localSchemaRefTokens(r.Ref)
+		if !ok {
+			continue // local $defs and external documents are out of scope here
+		}
+		where := location{display: displayPointer(r.Path) + ".$ref", pointer: r.Path + "/$ref"}
+		if _, ok := i.Schemas[toks[0]]; !ok {
+			errs.add(where, ProblemUnknownSchema, "references unknown schema %q", toks[0])
+			continue
+		}
+		if !pointerResolves(schemasDoc, toks) {
+			errs.add(where, ProblemUnresolvedRef, "%q does not resolve", r.Ref)
+		}
+	}
+
+	// A schema that is only an alias for another ("$ref": "#/schemas/Y") must
+	// eventually reach a real schema; alias chains that loop never do.
+	aliasOf := func(name string) (string, bool) {
+		ref, ok := i.Schemas[name]["$ref"].(string)
+		if !ok {
+			return "", false
+		}
+		toks, ok := localSchemaRefTokens(ref)
+		if !ok || len(toks) != 1 {
+			return "", false
+		}
+		return toks[0], true
+	}
+	for _, k := range sortedKeys(i.Schemas) {
+		seen := map[string]bool{k: true}
+		for cur := k; ; {
+			next, ok := aliasOf(cur)
+			if !ok {
+				break
+			}
+			if seen[next] {
+				errs.add(at("schemas", k).field("$ref"), ProblemRefCycle, "reference cycle through %q", next)
+				break
+			}
+			seen[next] = true
+			cur = next
+		}
+	}
+}
+
+// pointerResolves reports whether the unescaped pointer tokens resolve within doc.
+func pointerResolves(doc any, toks []string) bool {
+	cur := doc
+	for _, tok := range toks {
+		switch x := cur.(type) {
+		case map[string]any:
+			nxt, ok := x[tok]
+			if !ok {
+				return false
+			}
+			cur = nxt
+		case []any:
+			idx, err := strconv.Atoi(tok)
+			if err != nil || idx < 0 || idx >= len(x) {
+				return false
+			}
+			cur = x[idx]
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// displayPointer renders a document JSON Pointer in the notation used by
+// validation problems, e.g. /operations/op/input/items -> operations["op"].input.items.
+func displayPointer(ptr string) string {
+	toks := strings.Split(strings.TrimPrefix(ptr, "/"), "/")
+	var b strings.Builder
+	for idx, tok := range toks {
+		tok = unescapePointerToken(tok)
+		switch {
+		case idx == 0:
+			b.WriteString(tok)
+		case idx == 1 && isKeyedCollection(toks[0]):
+			fmt.Fprintf(&b, "[%q]", tok)
+		case isArrayIndex(tok):
+			fmt.Fprintf(&b, "[%s]", tok)
+		case identifierish.MatchString(tok):
+			b.WriteString("." + tok)
+		default:
+			fmt.Fprintf(&b, "[%q]", tok)
+		}
+	}
+	return b.String()
+}
+
+var identifierish = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$-]*$`)
+
+// isKeyedCollection reports whether a top-level field is a map keyed by user-chosen names.
+func isKeyedCollection(field string) bool {
+	switch field {
+	case "schemas", "operations", "roles", "sources", "bindings", "security", "transforms":
+		return true
+	}
+	return false
+}
+
+func isArrayIndex(tok string) bool {
+	if tok == "" {
+		return false
+	}
+	for _, r := range tok {
+		if r < '0' || r > '9' {
+			return false
+		}
 	}
-	name := strings.TrimPrefix(ref, prefix)
-	if name == "" {
-		return fmt.Errorf("transform name is empty")
+	return true
+}
+
+// validateTransformRef validates that t's $ref points to a transform, phrasing
+// ResolveErr's error for a problem at the $ref.
+func validateTransformRef(t TransformOrRef, transforms map[string]Transform) error {
+	_, err := t.ResolveErr(transforms)
+	var re *transformRefError
+	if !errors.As(err, &re) {
+		return err
 	}
-	if _, ok := transforms[name]; !ok {
-		return fmt.Errorf("references unknown transform %q", name)
+	if re.kind == ErrUnknownTransform {
+		return fmt.Errorf("references unknown transform %q", re.name)
 	}
-	return nil
+	return errors.New(re.reason)
 }
 
-// validateInlineTransform validates an inline transform definition.
-func validateInlineTransform(errs *[]string, prefix string, tr *Transform) {
+// validateInlineTransform validates an inline transform definition, whose type must
+// be one of types. If parse is non-nil, jsonata expressions are also checked for
+// syntax errors.
+func validateInlineTransform(errs *problemList, l location, tr *Transform, types []string, parse func(string) error) {
 	if strings.TrimSpace(tr.Type) == "" {
-		*errs = append(*errs, fmt.Sprintf("%s.type: required", prefix))
-	} else if tr.Type != "jsonata" {
-		*errs = append(*errs, fmt.Sprintf("%s.type: must be \"jsonata\" (got %q)", prefix, tr.Type))
+		errs.add(l.field("type"), ProblemRequired, "required")
+	} else if !slices.Contains(types, tr.Type) {
+		quoted := make([]string, len(types))
+		for i, t := range types {
+			quoted[i] = strconv.Quote(t)
+		}
+		if len(quoted) == 1 {
+			errs.add(l.field("type"), ProblemInvalidTransform, "must be %s (got %q)", quoted[0], tr.Type)
+		} else {
+			errs.add(l.field("type"), ProblemInvalidTransform, "must be one of %s (got %q)", strings.Join(quoted, ", "), tr.Type)
+		}
 	}
 	if strings.TrimSpace(tr.Expression) == "" {
-		*errs = append(*errs, fmt.Sprintf("%s.expression: required", prefix))
+		errs.add(l.field("expression"), ProblemRequired, "required")
+	} else if parse != nil && tr.Type == "jsonata" {
+		if err := parse(tr.Expression); err != nil {
+			errs.add(l.field("expression"), ProblemExpressionParse, "parse error: %v", err)
+		}
 	}
 }
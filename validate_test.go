@@ -2,6 +2,11 @@ package openbindings
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
 	"testing"
 )
 
@@ -26,6 +31,29 @@ func TestInterfaceValidate_RequireSupportedVersion(t *testing.T) {
 	}
 }
 
+func TestInterfaceValidate_LenientVersion(t *testing.T) {
+	i := Interface{
+		OpenBindings: "0.2.0",
+		Operations:   map[string]Operation{"x": {Description: "X."}},
+	}
+	report, err := i.Check(WithLenientVersion())
+	if err != nil {
+		t.Fatalf("expected a partially supported version to validate, got %v", err)
+	}
+	if len(report.Warnings) != 1 || report.Warnings[0].Code != ProblemPartialVersion {
+		t.Fatalf("expected a partial_version warning, got %+v", report.Warnings)
+	}
+	if err := i.Validate(WithRequireSupportedVersion()); err == nil {
+		t.Fatalf("expected WithRequireSupportedVersion to stay strict")
+	}
+
+	i.OpenBindings = "1.0.0"
+	want := `openbindings: unsupported version "1.0.0" (supported 0.1.0-0.1.0)`
+	if err := i.Validate(WithLenientVersion()); !containsProblem(err, want) {
+		t.Fatalf("expected problem %q, got %v", want, err)
+	}
+}
+
 func TestInterfaceValidate_UnknownTopLevelFields_StrictMode(t *testing.T) {
 	i := Interface{
 		OpenBindings: "0.1.0",
@@ -226,6 +254,53 @@ func TestInterfaceValidate_OpenBindingsVersionErrorMessageIsStable(t *testing.T)
 	}
 }
 
+func TestInterfaceValidate_OpenBindingsVersionAcceptsSemverSuffixes(t *testing.T) {
+	for _, v := range []string{"0.1.0-rc.1", "0.1.0+build.5"} {
+		i := Interface{OpenBindings: v, Operations: map[string]Operation{}}
+		if err := i.Validate(); err != nil {
+			t.Fatalf("%s: unexpected error: %v", v, err)
+		}
+	}
+
+	i := Interface{OpenBindings: "0.1.0-rc.1", Operations: map[string]Operation{}}
+	err := i.Validate(WithRequireSupportedVersion())
+	if !containsProblem(err, `openbindings: unsupported version "0.1.0-rc.1" (supported 0.1.0-0.1.0)`) {
+		t.Fatalf("expected pre-release to be unsupported, got %v", err)
+	}
+}
+
+func TestInterfaceValidate_RequireDescriptions(t *testing.T) {
+	i := Interface{
+		OpenBindings: "0.1.0",
+		Operations: map[string]Operation{
+			"b": {},
+			"a": {Description: "Documented."},
+			"c": {Description: "  "},
+		},
+		Sources: map[string]Source{
+			"api": {Format: "openapi@3.1", Location: "./api.json"},
+		},
+	}
+	if err := i.Validate(); err != nil {
+		t.Fatalf("descriptions must be optional by default, got %v", err)
+	}
+
+	err := i.Validate(WithRequireDescriptions())
+	var ve *ValidationError
+	if !errors.As(err, &ve) {
+		t.Fatalf("expected ValidationError, got %v", err)
+	}
+	want := []string{
+		"description: required",
+		`operations["b"].description: required`,
+		`operations["c"].description: required`,
+		`sources["api"].description: required`,
+	}
+	if strings.Join(ve.Problems, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("unexpected problems:\n got: %q\nwant: %q", ve.Problems, want)
+	}
+}
+
 func containsProblem(err error, want string) bool {
 	ve, ok := err.(*ValidationError)
 	if !ok {
@@ -339,6 +414,33 @@ func TestInterfaceValidate_BindingTransformRefMustExist(t *testing.T) {
 	}
 }
 
+func TestInterfaceValidate_TransformWithBothRefAndInline(t *testing.T) {
+	i := Interface{
+		OpenBindings: "0.1.0",
+		Operations:   map[string]Operation{"op": {}},
+		Sources: map[string]Source{
+			"api": {Format: "openapi@3.1", Location: "./api.json"},
+		},
+		Transforms: map[string]Transform{
+			"t": {Type: "jsonata", Expression: "$"},
+		},
+		Bindings: map[string]BindingEntry{
+			"op.api": {
+				Operation: "op",
+				Source:    "api",
+				OutputTransform: &TransformOrRef{
+					Ref:       "#/transforms/t",
+					Transform: &Transform{Type: "jsonata", Expression: "$.data"},
+				},
+			},
+		},
+	}
+	err := i.Validate()
+	if !containsProblem(err, "bindings[\"op.api\"].outputTransform: has both $ref and inline transform") {
+		t.Fatalf("expected both-forms error, got %v", err)
+	}
+}
+
 func TestInterfaceValidate_OperationRefMustExist(t *testing.T) {
 	i := Interface{
 		OpenBindings: "0.1.0",
@@ -492,3 +594,782 @@ func TestInterfaceValidate_ValidInterfaceWithTransforms(t *testing.T) {
 		t.Fatalf("expected no error, got %v", err)
 	}
 }
+
+func TestInterfaceValidate_SizeLimits(t *testing.T) {
+	i := Interface{
+		OpenBindings: "0.1.0",
+		Operations: map[string]Operation{
+			"a": {Input: JSONSchema{"type": "object", "properties": map[string]any{
+				"nested": map[string]any{"type": "object"},
+			}}},
+			"b": {},
+		},
+		Sources: map[string]Source{
+			"src": {Format: "openapi@3.1", Location: "./api.json"},
+		},
+		Bindings: map[string]BindingEntry{
+			"a.src": {Operation: "a", Source: "src"},
+			"b.src": {Operation: "b", Source: "src"},
+		},
+	}
+	if err := i.Validate(WithMaxOperations(2), WithMaxBindings(2), WithMaxSchemaDepth(3)); err != nil {
+		t.Fatalf("expected no error within limits, got %v", err)
+	}
+	err := i.Validate(WithMaxOperations(1), WithMaxBindings(1), WithMaxSchemaDepth(2))
+	for _, want := range []string{
+		"operations: 2 entries exceeds limit of 1",
+		"bindings: 2 entries exceeds limit of 1",
+		`operations["a"].input: nesting depth exceeds limit of 2`,
+	} {
+		if !containsProblem(err, want) {
+			t.Fatalf("expected problem %q, got %v", want, err)
+		}
+	}
+}
+
+func TestInterfaceValidate_DeprecationConsistency(t *testing.T) {
+	i := Interface{
+		OpenBindings: "0.1.0",
+		Operations: map[string]Operation{
+			"old": {Deprecated: true},
+			"new": {},
+		},
+		Sources: map[string]Source{
+			"api": {Format: "openapi@3.1", Location: "./api.json"},
+		},
+		Bindings: map[string]BindingEntry{
+			"old.api": {Operation: "old", Source: "api"},
+			"new.api": {Operation: "new", Source: "api"},
+		},
+	}
+	if err := i.Validate(); err != nil {
+		t.Fatalf("the check is opt-in, got %v", err)
+	}
+
+	err := i.Validate(WithCheckDeprecationConsistency())
+	want := `bindings["old.api"].operation: targets deprecated operation "old"; deprecate or remove the binding`
+	if !containsProblem(err, want) {
+		t.Fatalf("expected problem %q, got %v", want, err)
+	}
+
+	i.Bindings["old.api"] = BindingEntry{Operation: "old", Source: "api", Deprecated: true}
+	if err := i.Validate(WithCheckDeprecationConsistency()); err != nil {
+		t.Fatalf("expected a deprecated binding to be consistent, got %v", err)
+	}
+}
+
+func TestInterfaceValidate_RequireAllOperationsBound(t *testing.T) {
+	i := Interface{
+		OpenBindings: "0.1.0",
+		Operations:   map[string]Operation{"bound": {}, "unbound": {}},
+		Sources: map[string]Source{
+			"src": {Format: "openapi@3.1", Location: "./api.json"},
+		},
+		Bindings: map[string]BindingEntry{
+			"bound.src": {Operation: "bound", Source: "src"},
+		},
+	}
+	if err := i.Validate(); err != nil {
+		t.Fatalf("expected partial document to be valid by default, got %v", err)
+	}
+	err := i.Validate(WithRequireAllOperationsBound())
+	if !containsProblem(err, `operations["unbound"]: no binding references this operation`) {
+		t.Fatalf("expected unbound operation problem, got %v", err)
+	}
+	if containsProblem(err, `operations["bound"]: no binding references this operation`) {
+		t.Fatalf("did not expect bound operation to be flagged, got %v", err)
+	}
+}
+
+func TestInterfaceValidate_DuplicateBindingTargets(t *testing.T) {
+	one, two := 1.0, 2.0
+	i := Interface{
+		OpenBindings: "0.1.0",
+		Operations:   map[string]Operation{"op": {}},
+		Sources: map[string]Source{
+			"src": {Format: "openapi@3.1", Location: "./api.json"},
+		},
+		Bindings: map[string]BindingEntry{
+			"a": {Operation: "op", Source: "src"},
+			"b": {Operation: "op", Source: "src"},
+		},
+	}
+	want := `bindings: ambiguous duplicate for operation "op" source "src" ("a" and "b")`
+	if err := i.Validate(); !containsProblem(err, want) {
+		t.Fatalf("expected problem %q, got %v", want, err)
+	}
+
+	i.Bindings["a"] = BindingEntry{Operation: "op", Source: "src", Priority: &one}
+	i.Bindings["b"] = BindingEntry{Operation: "op", Source: "src", Priority: &two}
+	if err := i.Validate(); err != nil {
+		t.Fatalf("expected differing priorities to be valid, got %v", err)
+	}
+
+	i.Bindings["b"] = BindingEntry{Operation: "op", Source: "src", Priority: &one}
+	if err := i.Validate(); !containsProblem(err, want) {
+		t.Fatalf("expected problem %q for equal priorities, got %v", want, err)
+	}
+}
+
+func TestInterfaceValidate_UniqueBindingPriorities(t *testing.T) {
+	five, six := 5.0, 6.0
+	i := Interface{
+		OpenBindings: "0.1.0",
+		Operations:   map[string]Operation{"x": {}},
+		Sources: map[string]Source{
+			"rest": {Format: "openapi@3.1", Location: "./api.json"},
+			"rpc":  {Format: "grpc", Location: "./api.proto"},
+		},
+		Bindings: map[string]BindingEntry{
+			"a": {Operation: "x", Source: "rest", Priority: &five},
+			"b": {Operation: "x", Source: "rpc", Priority: &five},
+			"c": {Operation: "x", Source: "rest"},
+			"d": {Operation: "x", Source: "rpc"},
+		},
+	}
+	if err := i.Validate(); err != nil {
+		t.Fatalf("equal priorities across sources are valid by default, got %v", err)
+	}
+
+	want := `bindings: operation "x" has duplicate priority 5 across "a" and "b"`
+	err := i.Validate(WithUniqueBindingPriorities())
+	if !containsProblem(err, want) {
+		t.Fatalf("expected problem %q, got %v", want, err)
+	}
+	if strings.Contains(err.Error(), `"c"`) {
+		t.Fatalf("bindings without a priority must be exempt, got %v", err)
+	}
+
+	i.Bindings["b"] = BindingEntry{Operation: "x", Source: "rpc", Priority: &six}
+	if err := i.Validate(WithUniqueBindingPriorities()); err != nil {
+		t.Fatalf("expected distinct priorities to be valid, got %v", err)
+	}
+}
+
+func TestInterfaceValidate_BindingKeyConvention(t *testing.T) {
+	i := Interface{
+		OpenBindings: "0.1.0",
+		Operations:   map[string]Operation{"getUser": {}},
+		Sources: map[string]Source{
+			"api": {Format: "openapi@3.1", Location: "./api.json"},
+			"rpc": {Format: "grpc", Location: "./api.proto"},
+		},
+		Bindings: map[string]BindingEntry{
+			"getUser.api": {Operation: "getUser", Source: "api"},
+			"weird":       {Operation: "getUser", Source: "rpc"},
+		},
+	}
+	if err := i.Validate(); err != nil {
+		t.Fatalf("the convention is opt-in, got %v", err)
+	}
+
+	err := i.Validate(WithBindingKeyConvention())
+	want := `bindings["weird"]: key should be "getUser.rpc"`
+	if !containsProblem(err, want) {
+		t.Fatalf("expected problem %q, got %v", want, err)
+	}
+	var ve *ValidationError
+	if !errors.As(err, &ve) || len(ve.Problems) != 1 || ve.Structured[0].Code != ProblemBindingKeyConvention {
+		t.Fatalf("expected only the key problem, got %v", err)
+	}
+
+	delete(i.Bindings, "weird")
+	if err := i.Validate(WithBindingKeyConvention()); err != nil {
+		t.Fatalf("expected conventional keys to be valid, got %v", err)
+	}
+}
+
+func TestInterfaceValidate_OperationSecurityEntriesNonEmpty(t *testing.T) {
+	i := Interface{
+		OpenBindings: "0.1.0",
+		Operations: map[string]Operation{
+			"op": {Security: []map[string]any{{"oauth": []any{"read"}}, {}}},
+		},
+	}
+	err := i.Validate()
+	if want := `operations["op"].security[1]: must not be empty`; !containsProblem(err, want) {
+		t.Fatalf("expected problem %q, got %v", want, err)
+	}
+	if containsProblem(err, `operations["op"].security[0]: must not be empty`) {
+		t.Fatalf("did not expect non-empty entry to be flagged, got %v", err)
+	}
+}
+
+func TestInterfaceValidate_SchemaRefsMustResolve(t *testing.T) {
+	i := Interface{
+		OpenBindings: "0.1.0",
+		Schemas: map[string]JSONSchema{
+			"User":  {"type": "object", "properties": map[string]any{"org": map[string]any{"$ref": "#/schemas/Org"}}},
+			"Alias": {"$ref": "#/schemas/User"},
+			"A":     {"$ref": "#/schemas/B"},
+			"B":     {"$ref": "#/schemas/A"},
+		},
+		Operations: map[string]Operation{
+			"op": {
+				Input:  JSONSchema{"$ref": "#/schemas/Missing"},
+				Output: JSONSchema{"items": map[string]any{"$ref": "#/schemas/User/properties/nope"}},
+			},
+		},
+	}
+	err := i.Validate()
+	for _, want := range []string{
+		`operations["op"].input.$ref: references unknown schema "Missing"`,
+		`operations["op"].output.items.$ref: "#/schemas/User/properties/nope" does not resolve`,
+		`schemas["User"].properties.org.$ref: references unknown schema "Org"`,
+		`schemas["A"].$ref: reference cycle through "A"`,
+		`schemas["B"].$ref: reference cycle through "B"`,
+	} {
+		if !containsProblem(err, want) {
+			t.Fatalf("expected problem %q, got %v", want, err)
+		}
+	}
+	if containsProblem(err, `schemas["Alias"].$ref: references unknown schema "User"`) {
+		t.Fatalf("did not expect valid alias to be flagged: %v", err)
+	}
+}
+
+func TestInterfaceValidate_SchemaRefsInValuesAreData(t *testing.T) {
+	var i Interface
+	mustUnmarshalJSON(t, []byte(`{
+  "openbindings": "0.1.0",
+  "schemas": {
+    "Example": {"type": "object", "examples": [{"$ref": "#/schemas/Nope"}]},
+    "Const": {"const": {"$ref": "#/schemas/Nope"}, "x-origin": {"$ref": "#/schemas/Nope"}}
+  },
+  "operations": {"op": {"input": {"enum": [{"$ref": "#/schemas/Nope"}], "default": {"$ref": "#/schemas/Nope"}}}}
+}`), &i)
+	if err := i.Validate(); err != nil {
+		t.Fatalf("expected $ref inside values to be ignored, got %v", err)
+	}
+}
+
+func TestInterfaceValidate_TransformExpressionsWithParser(t *testing.T) {
+	i := Interface{
+		OpenBindings: "0.1.0",
+		Operations:   map[string]Operation{"op": {}},
+		Sources: map[string]Source{
+			"src": {Format: "openapi@3.1", Location: "./api.json"},
+		},
+		Transforms: map[string]Transform{
+			"broken": {Type: "jsonata", Expression: "$foo("},
+		},
+		Bindings: map[string]BindingEntry{
+			"op.src": {
+				Operation:       "op",
+				Source:          "src",
+				OutputTransform: &TransformOrRef{Transform: &Transform{Type: "jsonata", Expression: "{ a: "}},
+			},
+		},
+	}
+	parser := func(expr string) error {
+		if strings.Count(expr, "(") != strings.Count(expr, ")") || strings.Count(expr, "{") != strings.Count(expr, "}") {
+			return fmt.Errorf("unbalanced brackets at offset %d", len(expr))
+		}
+		return nil
+	}
+
+	if err := i.Validate(WithValidateTransformExpressions()); err != nil {
+		t.Fatalf("expected no-op without a parser, got %v", err)
+	}
+	if err := i.Validate(WithJSONataParser(parser)); err != nil {
+		t.Fatalf("expected parser to be unused without the option, got %v", err)
+	}
+	err := i.Validate(WithValidateTransformExpressions(), WithJSONataParser(parser))
+	for _, want := range []string{
+		`transforms["broken"].expression: parse error: unbalanced brackets at offset 5`,
+		`bindings["op.src"].outputTransform.expression: parse error: unbalanced brackets at offset 5`,
+	} {
+		if !containsProblem(err, want) {
+			t.Fatalf("expected problem %q, got %v", want, err)
+		}
+	}
+}
+
+func TestInterfaceValidate_AllowedTransformTypes(t *testing.T) {
+	i := Interface{
+		OpenBindings: "0.1.0",
+		Operations:   map[string]Operation{"op": {}},
+		Sources: map[string]Source{
+			"src": {Format: "openapi@3.1", Location: "./api.json"},
+		},
+		Transforms: map[string]Transform{
+			"pick": {Type: "jq", Expression: ".items[0]"},
+		},
+		Bindings: map[string]BindingEntry{
+			"op.src": {
+				Operation:      "op",
+				Source:         "src",
+				InputTransform: &TransformOrRef{Transform: &Transform{Type: "cel", Expression: "input.id"}},
+			},
+		},
+	}
+	parsed := 0
+	parser := func(expr string) error {
+		parsed++
+		return nil
+	}
+
+	err := i.Validate()
+	if !containsProblem(err, `transforms["pick"].type: must be "jsonata" (got "jq")`) {
+		t.Fatalf("expected jq to be rejected by default, got %v", err)
+	}
+	err = i.Validate(WithAllowedTransformTypes("jsonata", "jq"), WithValidateTransformExpressions(), WithJSONataParser(parser))
+	want := `bindings["op.src"].inputTransform.type: must be one of "jsonata", "jq" (got "cel")`
+	if !containsProblem(err, want) {
+		t.Fatalf("expected problem %q, got %v", want, err)
+	}
+	var ve *ValidationError
+	if !errors.As(err, &ve) || len(ve.Problems) != 1 {
+		t.Fatalf("expected exactly 1 problem, got %v", err)
+	}
+	if parsed != 0 {
+		t.Fatalf("expected the JSONata parser to skip other types, got %d calls", parsed)
+	}
+}
+
+func TestInterfaceValidate_AllowLibraryDocument(t *testing.T) {
+	lib := Interface{
+		OpenBindings: "0.1.0",
+		Schemas:      map[string]JSONSchema{"Shared": {"type": "string"}},
+	}
+	if err := lib.Validate(); !containsProblem(err, "operations: required") {
+		t.Fatalf("expected operations: required by default, got %v", err)
+	}
+	if err := lib.Validate(WithAllowLibraryDocument()); err != nil {
+		t.Fatalf("expected library document to be valid, got %v", err)
+	}
+
+	empty := Interface{OpenBindings: "0.1.0"}
+	if err := empty.Validate(WithAllowLibraryDocument()); !containsProblem(err, "operations: required") {
+		t.Fatalf("expected document with nothing to share to still require operations, got %v", err)
+	}
+
+	emptyOps := Interface{OpenBindings: "0.1.0", Operations: map[string]Operation{}}
+	if err := emptyOps.Validate(); err != nil {
+		t.Fatalf("expected empty operations to be valid without the option, got %v", err)
+	}
+	if err := emptyOps.Validate(WithAllowLibraryDocument()); !containsProblem(err, "operations: required") {
+		t.Fatalf("expected empty operations with nothing to share to require operations, got %v", err)
+	}
+}
+
+func TestInterfaceValidate_SatisfiesResolver(t *testing.T) {
+	i := Interface{
+		OpenBindings: "0.1.0",
+		Roles:        map[string]string{"store": "./store.obi.json", "broken": "./broken.obi.json"},
+		Operations: map[string]Operation{
+			"a": {Satisfies: []Satisfies{{Role: "store", Operation: "getItem"}}},
+			"b": {Satisfies: []Satisfies{{Role: "store", Operation: "fetchItem"}}},
+			"c": {Satisfies: []Satisfies{{Role: "store", Operation: "getItme"}}},
+			"d": {Satisfies: []Satisfies{{Role: "broken", Operation: "x"}, {Role: "broken", Operation: "y"}}},
+		},
+	}
+	calls := map[string]int{}
+	resolver := func(role string) (*Interface, error) {
+		calls[role]++
+		if role == "broken" {
+			return nil, errors.New("not found")
+		}
+		return &Interface{Operations: map[string]Operation{
+			"getItem": {Aliases: []string{"fetchItem"}},
+		}}, nil
+	}
+
+	if err := i.Validate(); err != nil {
+		t.Fatalf("expected no cross-check without a resolver, got %v", err)
+	}
+	err := i.Validate(WithSatisfiesResolver(resolver))
+	if !containsProblem(err, `operations["c"].satisfies[0].operation: "getItme" not found in role "store"`) {
+		t.Fatalf("expected typo to be reported, got %v", err)
+	}
+	if !containsProblem(err, `roles["broken"]: cannot resolve interface: not found`) {
+		t.Fatalf("expected resolver error to be reported, got %v", err)
+	}
+	var ve *ValidationError
+	if !errors.As(err, &ve) || len(ve.Problems) != 2 {
+		t.Fatalf("expected exactly 2 problems, got %v", err)
+	}
+	if calls["store"] != 1 || calls["broken"] != 1 {
+		t.Fatalf("expected one resolver call per role, got %v", calls)
+	}
+}
+
+func TestInterfaceValidate_SourceLoader(t *testing.T) {
+	i := Interface{
+		OpenBindings: "0.1.0",
+		Operations:   map[string]Operation{"x": {}},
+		Sources: map[string]Source{
+			"current": {Format: "openapi@3.1", Location: "./v31.json"},
+			"drifted": {Format: "openapi@3.1", Location: "./v30.json"},
+			"events":  {Format: "asyncapi@3.0", Location: "./events.json"},
+			"bare":    {Format: "openapi@3.1", Location: "./bare.json"},
+			"missing": {Format: "openapi@3.1", Location: "./missing.json"},
+			"again":   {Format: "openapi@3.1", Location: "./v31.json"},
+			"rpc":     {Format: "grpc", Location: "./api.proto"},
+		},
+	}
+	docs := map[string]string{
+		"./v31.json":    `{"openapi": "3.1.1", "paths": {}}`,
+		"./v30.json":    `{"openapi": "3.0.3", "paths": {}}`,
+		"./events.json": `{"asyncapi": "3.0.0", "channels": {}}`,
+		"./bare.json":   `{"paths": {}}`,
+	}
+	calls := map[string]int{}
+	loader := func(location string) ([]byte, error) {
+		calls[location]++
+		d, ok := docs[location]
+		if !ok {
+			return nil, errors.New("not found")
+		}
+		return []byte(d), nil
+	}
+
+	if err := i.Validate(); err != nil {
+		t.Fatalf("expected no loading without a loader, got %v", err)
+	}
+	err := i.Validate(WithSourceLoader(loader))
+	for _, want := range []string{
+		`sources["drifted"]: location content declares openapi 3.0.3, expected 3.1`,
+		`sources["bare"]: location content does not declare openapi, expected 3.1`,
+		`sources["missing"].location: cannot load: not found`,
+	} {
+		if !containsProblem(err, want) {
+			t.Fatalf("expected problem %q, got %v", want, err)
+		}
+	}
+	var ve *ValidationError
+	if !errors.As(err, &ve) || len(ve.Problems) != 3 {
+		t.Fatalf("expected exactly 3 problems, got %v", err)
+	}
+	codes := map[string]int{}
+	for _, p := range ve.Structured {
+		codes[p.Code]++
+	}
+	if codes[ProblemFormatMismatch] != 2 || codes[ProblemInvalidSource] != 1 {
+		t.Fatalf("unexpected problem codes: %+v", ve.Structured)
+	}
+	if calls["./v31.json"] != 1 || calls["./api.proto"] != 0 {
+		t.Fatalf("expected one load per location and none for unversioned formats, got %v", calls)
+	}
+}
+
+func TestInterfaceValidate_SourceDecoder(t *testing.T) {
+	i := Interface{
+		OpenBindings: "0.1.0",
+		Operations:   map[string]Operation{"x": {}},
+		Sources:      map[string]Source{"events": {Format: "asyncapi@3.0", Location: "./events.yaml"}},
+	}
+	loader := func(string) ([]byte, error) { return []byte("asyncapi: 3.0.0\nchannels: {}\n"), nil }
+
+	err := i.Validate(WithSourceLoader(loader))
+	if !containsProblem(err, `sources["events"].location: location content is not an object`) {
+		t.Fatalf("expected YAML to fail the default JSON decoder, got %v", err)
+	}
+
+	// A minimal "key: value" decoder stands in for a YAML library.
+	decode := func(data []byte) (map[string]any, error) {
+		m := map[string]any{}
+		for _, line := range strings.Split(string(data), "\n") {
+			if k, v, ok := strings.Cut(line, ": "); ok {
+				m[k] = v
+			}
+		}
+		return m, nil
+	}
+	if err := i.Validate(WithSourceLoader(loader), WithSourceDecoder(decode)); err != nil {
+		t.Fatalf("expected the decoder to read the version, got %v", err)
+	}
+}
+
+func TestInterfaceValidate_ProblemsSortedAndGrouped(t *testing.T) {
+	i := Interface{
+		OpenBindings: "0.1.0",
+		Operations: map[string]Operation{
+			"b": {Satisfies: []Satisfies{{Role: "missing", Operation: "x"}, {Role: "missing", Operation: "x"}}},
+			"a": {},
+		},
+		Sources: map[string]Source{"api": {Format: "openapi@3.1"}},
+	}
+	err := i.Validate(WithRequireDescriptions())
+	var ve *ValidationError
+	if !errors.As(err, &ve) {
+		t.Fatalf("expected ValidationError, got %v", err)
+	}
+	if !sort.StringsAreSorted(ve.Problems) {
+		t.Fatalf("expected sorted problems, got %q", ve.Problems)
+	}
+	for n := 1; n < len(ve.Problems); n++ {
+		if ve.Problems[n] == ve.Problems[n-1] {
+			t.Fatalf("expected duplicates collapsed, got %q", ve.Problems)
+		}
+	}
+	if len(ve.Structured) != len(ve.Problems) {
+		t.Fatalf("expected structured problems aligned with Problems, got %d and %d", len(ve.Structured), len(ve.Problems))
+	}
+	for n, p := range ve.Structured {
+		if !strings.HasSuffix(ve.Problems[n], p.Message) {
+			t.Fatalf("structured problem %d %+v does not match %q", n, p, ve.Problems[n])
+		}
+	}
+
+	groups := ve.ByPointer()
+	want := map[string][]string{
+		"/description":  {"description: required"},
+		"/operations/a": {`operations["a"].description: required`},
+		"/operations/b": {
+			`operations["b"].description: required`,
+			`operations["b"].satisfies[0].role: references unknown role "missing"`,
+			`operations["b"].satisfies[1].role: references unknown role "missing"`,
+		},
+		"/sources/api": {
+			`sources["api"].description: required`,
+			`sources["api"]: must have location or content`,
+		},
+	}
+	if !reflect.DeepEqual(groups, want) {
+		t.Fatalf("unexpected groups:\n got %q\nwant %q", groups, want)
+	}
+
+	var dup problemList
+	dup.add(at("roles", "b"), ProblemInvalidValue, "bad")
+	dup.add(at("roles", "a"), ProblemInvalidValue, "bad")
+	dup.add(at("roles", "b"), ProblemInvalidValue, "bad")
+	dup.add(location{}, ProblemRequired, "operations required")
+	dup.sortUnique()
+	if got := fmt.Sprint(dup.problems); got != `[operations required roles["a"]: bad roles["b"]: bad]` {
+		t.Fatalf("unexpected sorted problems %s", got)
+	}
+	if len(dup.structured) != 3 || dup.structured[2].Pointer != "/roles/b" {
+		t.Fatalf("unexpected structured problems %+v", dup.structured)
+	}
+	if got := (&ValidationError{Problems: dup.problems, Structured: dup.structured}).ByPointer()[""]; len(got) != 1 {
+		t.Fatalf("expected document-level problems grouped under \"\", got %q", got)
+	}
+}
+
+func TestInterfaceValidate_MaxProblems(t *testing.T) {
+	i := Interface{OpenBindings: "0.1.0", Operations: map[string]Operation{}, Bindings: map[string]BindingEntry{}}
+	for _, k := range []string{"e", "d", "c", "b", "a"} {
+		i.Bindings[k] = BindingEntry{Operation: k, Source: "api"}
+	}
+	full := i.Validate()
+	var all *ValidationError
+	if !errors.As(full, &all) || len(all.Problems) <= 3 {
+		t.Fatalf("expected more than 3 problems, got %v", full)
+	}
+
+	err := i.Validate(WithMaxProblems(3))
+	var ve *ValidationError
+	if !errors.As(err, &ve) || len(ve.Problems) != 4 || len(ve.Structured) != 4 {
+		t.Fatalf("expected 3 problems and a summary, got %v", err)
+	}
+	if !reflect.DeepEqual(ve.Problems[:3], all.Problems[:3]) {
+		t.Fatalf("expected the first problems in sorted order, got %q", ve.Problems)
+	}
+	want := fmt.Sprintf("... %d more problems omitted", len(all.Problems)-3)
+	if ve.Problems[3] != want || ve.Structured[3].Code != ProblemLimitExceeded {
+		t.Fatalf("expected summary %q, got %q %+v", want, ve.Problems[3], ve.Structured[3])
+	}
+
+	if err := i.Validate(WithMaxProblems(len(all.Problems))); err.Error() != full.Error() {
+		t.Fatalf("expected no summary when nothing is omitted, got %v", err)
+	}
+}
+
+func TestInterfaceValidate_StructuredProblems(t *testing.T) {
+	i := Interface{
+		OpenBindings: "0.1.0",
+		Operations: map[string]Operation{
+			"op": {Satisfies: []Satisfies{{Role: "missing", Operation: "x"}}},
+		},
+		Sources: map[string]Source{
+			"api": {Format: "openapi@3.1", Location: "./api.json"},
+		},
+		Schemas: map[string]JSONSchema{
+			"a/b": {"$ref": "#/schemas/Nope"},
+		},
+		Bindings: map[string]BindingEntry{
+			"op.api": {Operation: "op", Source: "nope"},
+		},
+		LosslessFields: LosslessFields{
+			Unknown: map[string]json.RawMessage{"stray": json.RawMessage(`1`)},
+		},
+	}
+	err := i.Validate(WithRejectUnknownTypedFields())
+	var ve *ValidationError
+	if !errors.As(err, &ve) {
+		t.Fatalf("expected ValidationError, got %v", err)
+	}
+	if len(ve.Structured) != len(ve.Problems) {
+		t.Fatalf("expected %d structured problems, got %d", len(ve.Problems), len(ve.Structured))
+	}
+
+	want := map[string]Problem{
+		`operations["op"].satisfies[0].role: references unknown role "missing"`: {
+			Pointer: "/operations/op/satisfies/0/role", Code: ProblemUnknownRole, Message: `references unknown role "missing"`,
+		},
+		`bindings["op.api"].source: references unknown source "nope"`: {
+			Pointer: "/bindings/op.api/source", Code: ProblemUnknownSource, Message: `references unknown source "nope"`,
+		},
+		`schemas["a/b"].$ref: references unknown schema "Nope"`: {
+			Pointer: "/schemas/a~1b/$ref", Code: ProblemUnknownSchema, Message: `references unknown schema "Nope"`,
+		},
+		`unknown fields: stray`: {
+			Pointer: "", Code: ProblemUnknownField, Message: "unknown fields: stray",
+		},
+	}
+	for idx, p := range ve.Problems {
+		w, ok := want[p]
+		if !ok {
+			continue
+		}
+		if ve.Structured[idx] != w {
+			t.Fatalf("problem %q: got %+v, want %+v", p, ve.Structured[idx], w)
+		}
+		delete(want, p)
+	}
+	if len(want) != 0 {
+		t.Fatalf("missing problems %v in %v", want, ve.Problems)
+	}
+}
+
+func TestInterfaceCheck_Warnings(t *testing.T) {
+	i := Interface{
+		OpenBindings: "0.1.0",
+		Operations: map[string]Operation{
+			"old": {Description: "Legacy lookup.", Deprecated: true},
+			"new": {},
+		},
+		Sources: map[string]Source{
+			"api":    {Format: "openapi@3.1", Location: "./api.json"},
+			"custom": {Format: "acme-rpc@1", Location: "./acme.json"},
+		},
+		Bindings: map[string]BindingEntry{
+			"old.api":    {Operation: "old", Source: "api"},
+			"old.legacy": {Operation: "old", Source: "api", Deprecated: true, Priority: new(float64)},
+			"new.custom": {Operation: "new", Source: "custom"},
+		},
+	}
+
+	if err := i.Validate(); err != nil {
+		t.Fatalf("warnings must not fail Validate: %v", err)
+	}
+	report, err := i.Check()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(report.Errors) != 0 {
+		t.Fatalf("expected no errors, got %+v", report.Errors)
+	}
+	want := []Problem{
+		{Pointer: "/bindings/old.api/operation", Code: ProblemDeprecatedOperation, Message: `targets deprecated operation "old"; deprecate or remove the binding`},
+		{Pointer: "/operations/new/description", Code: ProblemMissingDescription, Message: "missing"},
+		{Pointer: "/sources/custom/format", Code: ProblemUnknownFormat, Message: `unregistered format "acme-rpc@1"`},
+	}
+	if fmt.Sprint(report.Warnings) != fmt.Sprint(want) {
+		t.Fatalf("warnings:\n got %+v\nwant %+v", report.Warnings, want)
+	}
+
+	err = i.Validate(WithWarningAsError())
+	if !containsProblem(err, `bindings["old.api"].operation: targets deprecated operation "old"; deprecate or remove the binding`) {
+		t.Fatalf("expected promoted warning, got %v", err)
+	}
+	report, err = i.Check(WithWarningAsError())
+	if err == nil || len(report.Errors) != 3 || len(report.Warnings) != 0 {
+		t.Fatalf("expected 3 promoted errors, got %+v, %v", report, err)
+	}
+}
+
+func TestInterfaceCheck_ReportUnused(t *testing.T) {
+	i := Interface{
+		OpenBindings: "0.1.0",
+		Operations:   map[string]Operation{"op": {Description: "Op."}},
+		Sources: map[string]Source{
+			"api":    {Format: "openapi@3.1", Location: "./api.json"},
+			"spare":  {Format: "openapi@3.1", Location: "./spare.json"},
+			"backup": {Format: "openapi@3.1", Location: "./backup.json"},
+		},
+		Transforms: map[string]Transform{
+			"used":   {Type: "jsonata", Expression: "$"},
+			"unused": {Type: "jsonata", Expression: "$"},
+		},
+		Bindings: map[string]BindingEntry{
+			"op.api": {
+				Operation:       "op",
+				Source:          "api",
+				InputTransform:  &TransformOrRef{Ref: "#/transforms/used"},
+				OutputTransform: &TransformOrRef{Transform: &Transform{Type: "jsonata", Expression: "$"}},
+			},
+		},
+	}
+
+	report, err := i.Check()
+	if err != nil || len(report.Warnings) != 0 {
+		t.Fatalf("unused entries must only be reported on request, got %+v, %v", report, err)
+	}
+
+	report, err = i.Check(WithReportUnused())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []Problem{
+		{Pointer: "/sources/backup", Code: ProblemUnused, Message: "defined but never referenced"},
+		{Pointer: "/sources/spare", Code: ProblemUnused, Message: "defined but never referenced"},
+		{Pointer: "/transforms/unused", Code: ProblemUnused, Message: "defined but never referenced"},
+	}
+	if fmt.Sprint(report.Warnings) != fmt.Sprint(want) {
+		t.Fatalf("warnings:\n got %+v\nwant %+v", report.Warnings, want)
+	}
+
+	err = i.Validate(WithReportUnused(), WithWarningAsError())
+	if !containsProblem(err, `transforms["unused"]: defined but never referenced`) {
+		t.Fatalf("expected promoted warning, got %v", err)
+	}
+}
+
+func TestInterfaceValidate_ExampleValidator(t *testing.T) {
+	i := Interface{
+		OpenBindings: "0.1.0",
+		Operations: map[string]Operation{
+			"getUser": {
+				Input:  JSONSchema{"type": "object", "required": []any{"id"}},
+				Output: JSONSchema{"type": "object"},
+				Examples: map[string]OperationExample{
+					"ok":      {Input: map[string]any{"id": "1"}, Output: map[string]any{"name": "Ada"}},
+					"missing": {Input: map[string]any{}},
+				},
+			},
+		},
+	}
+	// A stand-in validator that understands only "required".
+	var calls int
+	validate := func(schema map[string]any, value any) error {
+		calls++
+		obj, _ := value.(map[string]any)
+		req, _ := schema["required"].([]any)
+		for _, r := range req {
+			if _, ok := obj[r.(string)]; !ok {
+				return fmt.Errorf("missing property %q", r)
+			}
+		}
+		return nil
+	}
+
+	if err := i.Validate(); err != nil {
+		t.Fatalf("examples must not be checked by default: %v", err)
+	}
+	err := i.Validate(WithExampleValidator(validate))
+	var ve *ValidationError
+	if !errors.As(err, &ve) || len(ve.Problems) != 1 {
+		t.Fatalf("expected one problem, got %v", err)
+	}
+	if want := `operations["getUser"].examples["missing"].input: does not match operation input: missing property "id"`; ve.Problems[0] != want {
+		t.Fatalf("unexpected problem:\n got: %s\nwant: %s", ve.Problems[0], want)
+	}
+	if ve.Structured[0].Code != ProblemExampleMismatch {
+		t.Fatalf("unexpected code %q", ve.Structured[0].Code)
+	}
+	if calls != 3 {
+		t.Fatalf("expected 3 validator calls, got %d", calls)
+	}
+}
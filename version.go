@@ -36,6 +36,8 @@ func init() {
 }
 
 // IsSupportedVersion reports whether the provided OpenBindings version is within the supported range.
+// v may carry SemVer pre-release and build suffixes: a pre-release sorts before its
+// release (so 0.1.0-rc.1 precedes 0.1.0), and build metadata is ignored.
 func IsSupportedVersion(v string) (bool, error) {
 	parsed, err := parseSemverStrict(v)
 	if err != nil {
@@ -44,32 +46,168 @@ func IsSupportedVersion(v string) (bool, error) {
 	return compareSemver(parsed, minSupportedSemver) >= 0 && compareSemver(parsed, maxTestedSemver) <= 0, nil
 }
 
+// ForwardMinorWindow is how many minor versions past MaxTestedVersion, within the
+// same major version, IsSupportedVersionLenient and CompatibilityLevel still accept
+// as partially supported. Such documents are newer than this SDK but are expected to
+// be mostly readable by it. Use CompatibilityLevelWithin for a different window.
+const ForwardMinorWindow = 1
+
+// VersionCompatibility is how well this SDK supports an OpenBindings version.
+type VersionCompatibility int
+
+const (
+	// CompatibilityUnsupported is a version below MinSupportedVersion, of a different
+	// major version, or beyond the forward window.
+	CompatibilityUnsupported VersionCompatibility = iota
+	// CompatibilityPartial is a version past MaxTestedVersion within the forward
+	// window: it can likely be read, but fields it adds may be ignored.
+	CompatibilityPartial
+	// CompatibilityFull is a version within SupportedRange.
+	CompatibilityFull
+)
+
+func (c VersionCompatibility) String() string {
+	switch c {
+	case CompatibilityFull:
+		return "full"
+	case CompatibilityPartial:
+		return "partial"
+	case CompatibilityUnsupported:
+		return "unsupported"
+	}
+	return fmt.Sprintf("VersionCompatibility(%d)", int(c))
+}
+
+// CompatibilityLevel reports how well this SDK supports the OpenBindings version v:
+// fully within SupportedRange, partially past MaxTestedVersion by at most
+// ForwardMinorWindow minor versions of the same major version, or not at all. v is
+// parsed as by IsSupportedVersion.
+func CompatibilityLevel(v string) (VersionCompatibility, error) {
+	return CompatibilityLevelWithin(v, ForwardMinorWindow)
+}
+
+// CompatibilityLevelWithin is CompatibilityLevel with a forward window of window
+// minor versions instead of ForwardMinorWindow; 0 accepts only patch releases past
+// MaxTestedVersion as partially supported.
+func CompatibilityLevelWithin(v string, window int) (VersionCompatibility, error) {
+	parsed, err := parseSemverStrict(v)
+	if err != nil {
+		return CompatibilityUnsupported, err
+	}
+	switch {
+	case compareSemver(parsed, minSupportedSemver) < 0:
+		return CompatibilityUnsupported, nil
+	case compareSemver(parsed, maxTestedSemver) <= 0:
+		return CompatibilityFull, nil
+	case parsed.major == maxTestedSemver.major && parsed.minor <= maxTestedSemver.minor+window:
+		return CompatibilityPartial, nil
+	}
+	return CompatibilityUnsupported, nil
+}
+
+// IsSupportedVersionLenient is like IsSupportedVersion but also accepts versions
+// that are only partially supported (see CompatibilityLevel), so documents from a
+// slightly newer release of the spec are not rejected outright.
+func IsSupportedVersionLenient(v string) (bool, error) {
+	level, err := CompatibilityLevel(v)
+	if err != nil {
+		return false, err
+	}
+	return level != CompatibilityUnsupported, nil
+}
+
+// semver is a SemVer 2.0 version. Build metadata is dropped after parsing, since
+// it does not affect precedence.
 type semver struct {
 	major int
 	minor int
 	patch int
+	// pre is the pre-release (dot-separated identifiers), or "" for a release.
+	pre string
 }
 
+// parseSemverStrict parses MAJOR.MINOR.PATCH with optional -prerelease and +build
+// suffixes, ignoring surrounding whitespace.
 func parseSemverStrict(v string) (semver, error) {
-	parts := strings.Split(strings.TrimSpace(v), ".")
+	return parseSemver(strings.TrimSpace(v))
+}
+
+// isSemver reports whether v is exactly a SemVer 2.0 version.
+func isSemver(v string) bool {
+	_, err := parseSemver(v)
+	return err == nil
+}
+
+func parseSemver(v string) (semver, error) {
+	invalid := fmt.Errorf("invalid semver: %q", v)
+	core := v
+	if i := strings.IndexByte(core, '+'); i >= 0 {
+		if !validIdentifiers(core[i+1:], false) {
+			return semver{}, invalid
+		}
+		core = core[:i]
+	}
+	var pre string
+	if i := strings.IndexByte(core, '-'); i >= 0 {
+		if !validIdentifiers(core[i+1:], true) {
+			return semver{}, invalid
+		}
+		pre = core[i+1:]
+		core = core[:i]
+	}
+
+	parts := strings.Split(core, ".")
 	if len(parts) != 3 {
-		return semver{}, fmt.Errorf("invalid semver: %q", v)
+		return semver{}, invalid
 	}
-	major, err := strconv.Atoi(parts[0])
-	if err != nil || major < 0 {
-		return semver{}, fmt.Errorf("invalid semver: %q", v)
+	var nums [3]int
+	for idx, p := range parts {
+		if !isDigits(p) {
+			return semver{}, invalid
+		}
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return semver{}, invalid
+		}
+		nums[idx] = n
 	}
-	minor, err := strconv.Atoi(parts[1])
-	if err != nil || minor < 0 {
-		return semver{}, fmt.Errorf("invalid semver: %q", v)
+	return semver{major: nums[0], minor: nums[1], patch: nums[2], pre: pre}, nil
+}
+
+// validIdentifiers reports whether s is a non-empty dot-separated list of
+// [0-9A-Za-z-] identifiers. Numeric pre-release identifiers may not have
+// leading zeros.
+func validIdentifiers(s string, prerelease bool) bool {
+	for _, id := range strings.Split(s, ".") {
+		if id == "" {
+			return false
+		}
+		for _, r := range id {
+			if !(r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r == '-') {
+				return false
+			}
+		}
+		if prerelease && isDigits(id) && len(id) > 1 && id[0] == '0' {
+			return false
+		}
 	}
-	patch, err := strconv.Atoi(parts[2])
-	if err != nil || patch < 0 {
-		return semver{}, fmt.Errorf("invalid semver: %q", v)
+	return true
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
 	}
-	return semver{major: major, minor: minor, patch: patch}, nil
+	return true
 }
 
+// compareSemver orders versions by SemVer 2.0 precedence: the core version first,
+// then a pre-release sorts before the release it precedes.
 func compareSemver(a, b semver) int {
 	if a.major != b.major {
 		return cmp.Compare(a.major, b.major)
@@ -77,5 +215,40 @@ func compareSemver(a, b semver) int {
 	if a.minor != b.minor {
 		return cmp.Compare(a.minor, b.minor)
 	}
-	return cmp.Compare(a.patch, b.patch)
+	if a.patch != b.patch {
+		return cmp.Compare(a.patch, b.patch)
+	}
+	switch {
+	case a.pre == b.pre:
+		return 0
+	case a.pre == "":
+		return 1
+	case b.pre == "":
+		return -1
+	}
+	ap, bp := strings.Split(a.pre, "."), strings.Split(b.pre, ".")
+	for i := 0; i < len(ap) && i < len(bp); i++ {
+		if c := comparePrerelease(ap[i], bp[i]); c != 0 {
+			return c
+		}
+	}
+	return cmp.Compare(len(ap), len(bp))
+}
+
+// comparePrerelease compares two pre-release identifiers: numeric ones
+// numerically, and below alphanumeric ones, which compare in ASCII order.
+func comparePrerelease(a, b string) int {
+	an, bn := isDigits(a), isDigits(b)
+	switch {
+	case an && bn:
+		if len(a) != len(b) {
+			return cmp.Compare(len(a), len(b))
+		}
+		return strings.Compare(a, b)
+	case an:
+		return -1
+	case bn:
+		return 1
+	}
+	return strings.Compare(a, b)
 }
@@ -80,6 +80,16 @@ func TestIsSupportedVersion(t *testing.T) {
 			version: " 0.1.0 ",
 			want:    true, // trimmed
 		},
+		{
+			name:    "pre-release sorts before the minimum release",
+			version: "0.1.0-rc.1",
+			want:    false,
+		},
+		{
+			name:    "build metadata is ignored",
+			version: "0.1.0+build.5",
+			want:    true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -123,6 +133,46 @@ func TestParseSemverStrict(t *testing.T) {
 			input: "  1.2.3  ",
 			want:  semver{major: 1, minor: 2, patch: 3},
 		},
+		{
+			name:  "valid with pre-release",
+			input: "0.1.0-rc.1",
+			want:  semver{major: 0, minor: 1, patch: 0, pre: "rc.1"},
+		},
+		{
+			name:  "valid with build metadata",
+			input: "0.1.0+build.5",
+			want:  semver{major: 0, minor: 1, patch: 0},
+		},
+		{
+			name:  "valid with pre-release and build metadata",
+			input: "1.0.0-alpha-1.0+sha.5114f85",
+			want:  semver{major: 1, minor: 0, patch: 0, pre: "alpha-1.0"},
+		},
+		{
+			name:    "empty pre-release",
+			input:   "1.0.0-",
+			wantErr: true,
+		},
+		{
+			name:    "empty pre-release identifier",
+			input:   "1.0.0-rc..1",
+			wantErr: true,
+		},
+		{
+			name:    "pre-release numeric identifier with leading zero",
+			input:   "1.0.0-rc.01",
+			wantErr: true,
+		},
+		{
+			name:    "empty build metadata",
+			input:   "1.0.0+",
+			wantErr: true,
+		},
+		{
+			name:    "invalid character in build metadata",
+			input:   "1.0.0+build_5",
+			wantErr: true,
+		},
 		{
 			name:    "empty string",
 			input:   "",
@@ -193,50 +243,80 @@ func TestCompareSemver(t *testing.T) {
 	}{
 		{
 			name: "equal versions",
-			a:    semver{1, 2, 3},
-			b:    semver{1, 2, 3},
+			a:    semver{major: 1, minor: 2, patch: 3},
+			b:    semver{major: 1, minor: 2, patch: 3},
 			want: 0,
 		},
 		{
 			name: "a major greater",
-			a:    semver{2, 0, 0},
-			b:    semver{1, 9, 9},
+			a:    semver{major: 2, minor: 0, patch: 0},
+			b:    semver{major: 1, minor: 9, patch: 9},
 			want: 1,
 		},
 		{
 			name: "a major less",
-			a:    semver{1, 9, 9},
-			b:    semver{2, 0, 0},
+			a:    semver{major: 1, minor: 9, patch: 9},
+			b:    semver{major: 2, minor: 0, patch: 0},
 			want: -1,
 		},
 		{
 			name: "a minor greater",
-			a:    semver{1, 3, 0},
-			b:    semver{1, 2, 9},
+			a:    semver{major: 1, minor: 3, patch: 0},
+			b:    semver{major: 1, minor: 2, patch: 9},
 			want: 1,
 		},
 		{
 			name: "a minor less",
-			a:    semver{1, 2, 9},
-			b:    semver{1, 3, 0},
+			a:    semver{major: 1, minor: 2, patch: 9},
+			b:    semver{major: 1, minor: 3, patch: 0},
 			want: -1,
 		},
 		{
 			name: "a patch greater",
-			a:    semver{1, 2, 4},
-			b:    semver{1, 2, 3},
+			a:    semver{major: 1, minor: 2, patch: 4},
+			b:    semver{major: 1, minor: 2, patch: 3},
 			want: 1,
 		},
 		{
 			name: "a patch less",
-			a:    semver{1, 2, 3},
-			b:    semver{1, 2, 4},
+			a:    semver{major: 1, minor: 2, patch: 3},
+			b:    semver{major: 1, minor: 2, patch: 4},
 			want: -1,
 		},
+		{
+			name: "pre-release less than release",
+			a:    semver{major: 1, minor: 0, patch: 0, pre: "rc.1"},
+			b:    semver{major: 1, minor: 0, patch: 0},
+			want: -1,
+		},
+		{
+			name: "core version wins over pre-release",
+			a:    semver{major: 1, minor: 0, patch: 1, pre: "alpha"},
+			b:    semver{major: 1, minor: 0, patch: 0},
+			want: 1,
+		},
+		{
+			name: "numeric pre-release identifiers compare numerically",
+			a:    semver{major: 1, minor: 0, patch: 0, pre: "rc.10"},
+			b:    semver{major: 1, minor: 0, patch: 0, pre: "rc.9"},
+			want: 1,
+		},
+		{
+			name: "numeric identifier below alphanumeric",
+			a:    semver{major: 1, minor: 0, patch: 0, pre: "1"},
+			b:    semver{major: 1, minor: 0, patch: 0, pre: "alpha"},
+			want: -1,
+		},
+		{
+			name: "longer pre-release wins when prefix is equal",
+			a:    semver{major: 1, minor: 0, patch: 0, pre: "alpha.1"},
+			b:    semver{major: 1, minor: 0, patch: 0, pre: "alpha"},
+			want: 1,
+		},
 		{
 			name: "zero versions",
-			a:    semver{0, 0, 0},
-			b:    semver{0, 0, 0},
+			a:    semver{major: 0, minor: 0, patch: 0},
+			b:    semver{major: 0, minor: 0, patch: 0},
 			want: 0,
 		},
 	}
@@ -251,3 +331,47 @@ func TestCompareSemver(t *testing.T) {
 	}
 }
 
+
+func TestCompatibilityLevel(t *testing.T) {
+	tests := []struct {
+		version string
+		want    VersionCompatibility
+	}{
+		{"0.1.0", CompatibilityFull},
+		{"0.1.0+build.5", CompatibilityFull},
+		{"0.1.0-rc.1", CompatibilityUnsupported},
+		{"0.1.3", CompatibilityPartial},
+		{"0.2.0-rc.1", CompatibilityPartial},
+		{"0.2.9", CompatibilityPartial},
+		{"0.3.0", CompatibilityUnsupported},
+		{"1.1.0", CompatibilityUnsupported},
+		{"0.0.9", CompatibilityUnsupported},
+	}
+	for _, tt := range tests {
+		got, err := CompatibilityLevel(tt.version)
+		if err != nil {
+			t.Fatalf("CompatibilityLevel(%q): %v", tt.version, err)
+		}
+		if got != tt.want {
+			t.Errorf("CompatibilityLevel(%q) = %v, want %v", tt.version, got, tt.want)
+		}
+		lenient, _ := IsSupportedVersionLenient(tt.version)
+		if lenient != (tt.want != CompatibilityUnsupported) {
+			t.Errorf("IsSupportedVersionLenient(%q) = %v", tt.version, lenient)
+		}
+	}
+
+	if _, err := CompatibilityLevel("0.2"); err == nil {
+		t.Error("expected error for invalid version")
+	}
+
+	if got, _ := CompatibilityLevelWithin("0.2.0", 0); got != CompatibilityUnsupported {
+		t.Errorf("with no forward window, 0.2.0 = %v, want unsupported", got)
+	}
+	if got, _ := CompatibilityLevelWithin("0.1.1", 0); got != CompatibilityPartial {
+		t.Errorf("with no forward window, 0.1.1 = %v, want partial", got)
+	}
+	if got, _ := CompatibilityLevelWithin("0.3.0", 2); got != CompatibilityPartial {
+		t.Errorf("with a window of 2, 0.3.0 = %v, want partial", got)
+	}
+}
@@ -0,0 +1,132 @@
+package openbindings
+
+import "errors"
+
+// SkipChildren may be returned by a Walk visitor to skip the children of the node
+// it was called with; the walk continues with the node's next sibling.
+var SkipChildren = errors.New("openbindings: skip children")
+
+// Walk calls visitor for every node of i in document order, parents before
+// children, with the node's RFC 6901 JSON Pointer:
+//
+//   - "" with i itself (*Interface)
+//   - /schemas/{key} with each shared schema (JSONSchema)
+//   - /operations/{key} with each *Operation, followed by its /input and /output
+//     schemas (JSONSchema), each /satisfies/{n} (*Satisfies), and each
+//     /examples/{key} (*OperationExample)
+//   - /sources/{key} with each *Source
+//   - /transforms/{key} with each named *Transform
+//   - /bindings/{key} with each *BindingEntry, followed by its /inputTransform and
+//     /outputTransform (*TransformOrRef) when set
+//
+// Keys are visited in sorted order. Nodes are passed by pointer (schemas are maps),
+// so the visitor may modify them in place; changes to a node's fields are stored
+// back into i before its siblings are visited. Returning SkipChildren prunes the
+// node's children; any other non-nil error stops the walk and is returned.
+func Walk(i *Interface, visitor func(pointer string, node any) error) error {
+	// visit calls visitor and reports whether to descend into the node's children.
+	visit := func(l location, node any) (bool, error) {
+		err := visitor(l.pointer, node)
+		if errors.Is(err, SkipChildren) {
+			return false, nil
+		}
+		return err == nil, err
+	}
+
+	descend, err := visit(location{}, i)
+	if err != nil || !descend {
+		return err
+	}
+
+	for _, k := range sortedKeys(i.Schemas) {
+		if _, err := visit(at("schemas", k), i.Schemas[k]); err != nil {
+			return err
+		}
+	}
+
+	for _, k := range sortedKeys(i.Operations) {
+		op := i.Operations[k]
+		err := walkOperation(at("operations", k), &op, visit)
+		i.Operations[k] = op
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, k := range sortedKeys(i.Sources) {
+		src := i.Sources[k]
+		_, err := visit(at("sources", k), &src)
+		i.Sources[k] = src
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, k := range sortedKeys(i.Transforms) {
+		tr := i.Transforms[k]
+		_, err := visit(at("transforms", k), &tr)
+		i.Transforms[k] = tr
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, k := range sortedKeys(i.Bindings) {
+		b := i.Bindings[k]
+		err := walkBinding(at("bindings", k), &b, visit)
+		i.Bindings[k] = b
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func walkOperation(l location, op *Operation, visit func(location, any) (bool, error)) error {
+	descend, err := visit(l, op)
+	if err != nil || !descend {
+		return err
+	}
+	if op.Input != nil {
+		if _, err := visit(l.field("input"), op.Input); err != nil {
+			return err
+		}
+	}
+	if op.Output != nil {
+		if _, err := visit(l.field("output"), op.Output); err != nil {
+			return err
+		}
+	}
+	for idx := range op.Satisfies {
+		if _, err := visit(l.field("satisfies").index(idx), &op.Satisfies[idx]); err != nil {
+			return err
+		}
+	}
+	for _, k := range sortedKeys(op.Examples) {
+		ex := op.Examples[k]
+		_, err := visit(l.field("examples").key(k), &ex)
+		op.Examples[k] = ex
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func walkBinding(l location, b *BindingEntry, visit func(location, any) (bool, error)) error {
+	descend, err := visit(l, b)
+	if err != nil || !descend {
+		return err
+	}
+	if b.InputTransform != nil {
+		if _, err := visit(l.field("inputTransform"), b.InputTransform); err != nil {
+			return err
+		}
+	}
+	if b.OutputTransform != nil {
+		if _, err := visit(l.field("outputTransform"), b.OutputTransform); err != nil {
+			return err
+		}
+	}
+	return nil
+}
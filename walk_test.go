@@ -0,0 +1,82 @@
+package openbindings
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestWalk(t *testing.T) {
+	var i Interface
+	mustUnmarshalJSON(t, []byte(`{
+  "openbindings": "0.1.0",
+  "schemas": {"Id": {"type": "string"}},
+  "operations": {
+    "a/b": {
+      "description": "secret",
+      "input": {"type": "object"},
+      "satisfies": [{"role": "r", "operation": "x"}],
+      "examples": {"one": {"input": {}}}
+    },
+    "skip": {"output": {"type": "string"}}
+  },
+  "roles": {"r": "./r.json"},
+  "sources": {"api": {"format": "openapi@3.1", "location": "./api.json"}},
+  "transforms": {"t": {"type": "jsonata", "expression": "$"}},
+  "bindings": {"b": {"operation": "a/b", "source": "api", "inputTransform": {"$ref": "#/transforms/t"}}}
+}`), &i)
+
+	var visited []string
+	err := Walk(&i, func(pointer string, node any) error {
+		visited = append(visited, fmt.Sprintf("%s %T", pointer, node))
+		switch n := node.(type) {
+		case *Operation:
+			if pointer == "/operations/skip" {
+				return SkipChildren
+			}
+			n.Description = "[redacted]"
+		case *OperationExample:
+			n.Description = "redacted too"
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Walk: %v", err)
+	}
+	want := []string{
+		" *openbindings.Interface",
+		"/schemas/Id openbindings.JSONSchema",
+		"/operations/a~1b *openbindings.Operation",
+		"/operations/a~1b/input openbindings.JSONSchema",
+		"/operations/a~1b/satisfies/0 *openbindings.Satisfies",
+		"/operations/a~1b/examples/one *openbindings.OperationExample",
+		"/operations/skip *openbindings.Operation",
+		"/sources/api *openbindings.Source",
+		"/transforms/t *openbindings.Transform",
+		"/bindings/b *openbindings.BindingEntry",
+		"/bindings/b/inputTransform *openbindings.TransformOrRef",
+	}
+	if !reflect.DeepEqual(visited, want) {
+		t.Fatalf("visited:\n%q\nwant:\n%q", visited, want)
+	}
+	if got := i.Operations["a/b"].Description; got != "[redacted]" {
+		t.Fatalf("expected operation change to be stored, got %q", got)
+	}
+	if got := i.Operations["a/b"].Examples["one"].Description; got != "redacted too" {
+		t.Fatalf("expected example change to be stored, got %q", got)
+	}
+
+	stop := errors.New("stop")
+	visited = nil
+	err = Walk(&i, func(pointer string, node any) error {
+		visited = append(visited, pointer)
+		if _, ok := node.(*Operation); ok {
+			return stop
+		}
+		return nil
+	})
+	if !errors.Is(err, stop) || len(visited) != 3 {
+		t.Fatalf("expected walk to stop at the first operation, got %v after %q", err, visited)
+	}
+}